@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/michaelcade/kollect/pkg/diff"
+)
+
+func runDiff(args []string) {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	format := flags.String("format", "text", fmt.Sprintf("Output format (%s)", strings.Join(diff.Formats, "/")))
+	output := flags.String("output", "", "Output file to save the report")
+	flags.Usage = func() {
+		fmt.Println("Usage: kollect diff [flags] old.json new.json")
+		fmt.Println("Flags:")
+		flags.PrintDefaults()
+	}
+
+	// Allow flags before, between or after the two file names.
+	var files []string
+	for {
+		flags.Parse(args)
+		args = flags.Args()
+		if len(args) == 0 {
+			break
+		}
+		files = append(files, args[0])
+		args = args[1:]
+	}
+
+	if len(files) != 2 {
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	report, err := diff.Files(files[0], files[1])
+	if err != nil {
+		fmt.Printf("Error comparing inventories: %v\n", err)
+		os.Exit(1)
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			fmt.Printf("Error writing to file: %v\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if err := diff.Write(out, report, *format); err != nil {
+		fmt.Printf("Error writing report: %v\n", err)
+		os.Exit(1)
+	}
+}
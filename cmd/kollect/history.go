@@ -0,0 +1,160 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/michaelcade/kollect/pkg/export"
+	"github.com/michaelcade/kollect/pkg/history"
+	"github.com/michaelcade/kollect/pkg/inventory"
+)
+
+const defaultHistoryPath = "kollect-history.db"
+
+func runHistory(args []string) {
+	flags := flag.NewFlagSet("history", flag.ExitOnError)
+	historyPath := flags.String("history", defaultHistoryPath, "Path to the history database")
+	source := flags.String("source", "", "Only include runs that collected this source")
+	id := flags.String("id", "", "Run ID to show")
+	at := flags.String("at", "", "Show the latest run at or before this time (RFC3339 or YYYY-MM-DD)")
+	output := flags.String("output", "", "Output file to save the run")
+	format := flags.String("format", "json", fmt.Sprintf("Output format for --output (%s)", strings.Join(export.Formats, "/")))
+	flags.Usage = func() {
+		fmt.Println("Usage: kollect history <list|show|counts> [flags]")
+		fmt.Println("  list     List stored runs")
+		fmt.Println("  show     Print a stored run (--id, --at, or the latest run)")
+		fmt.Println("  counts   Show resource counts over time for --source")
+		fmt.Println("Flags:")
+		flags.PrintDefaults()
+	}
+
+	var positional []string
+	for {
+		flags.Parse(args)
+		args = flags.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if len(positional) != 1 {
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	store, err := history.Open(*historyPath)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	switch positional[0] {
+	case "list":
+		runs, err := store.Runs(*source)
+		if err != nil {
+			fmt.Printf("Error listing runs: %v\n", err)
+			os.Exit(1)
+		}
+		printRuns(runs)
+	case "show":
+		var doc *inventory.Document
+		switch {
+		case *id != "":
+			doc, err = store.Load(*id)
+		case *at != "":
+			var t time.Time
+			t, err = parseTime(*at)
+			if err == nil {
+				doc, err = store.At(t, *source)
+			}
+		default:
+			doc, err = store.Latest()
+		}
+		if err != nil {
+			fmt.Printf("Error loading run: %v\n", err)
+			os.Exit(1)
+		}
+
+		if *output != "" {
+			if err := saveDocument(doc, *output, *format); err != nil {
+				fmt.Printf("Error writing to file: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Data saved to %s\n", *output)
+			return
+		}
+		printData(doc)
+	case "counts":
+		if *source == "" {
+			fmt.Println("Error: counts requires --source")
+			os.Exit(1)
+		}
+		points, err := store.Counts(*source)
+		if err != nil {
+			fmt.Printf("Error reading counts: %v\n", err)
+			os.Exit(1)
+		}
+		printCounts(points)
+	default:
+		flags.Usage()
+		os.Exit(1)
+	}
+}
+
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected RFC3339 or YYYY-MM-DD", value)
+	}
+	// A bare date means the end of that day.
+	return t.Add(24*time.Hour - time.Nanosecond), nil
+}
+
+func printRuns(runs []history.Run) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTARTED\tDURATION\tSOURCES\tFAILED")
+	for _, run := range runs {
+		duration := ""
+		if !run.FinishedAt.IsZero() {
+			duration = run.FinishedAt.Sub(run.StartedAt).Round(time.Second).String()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", run.ID, run.StartedAt.Local().Format(time.RFC3339), duration, strings.Join(run.Sources, ","), strings.Join(run.Failed, ","))
+	}
+	w.Flush()
+}
+
+func printCounts(points []history.Point) {
+	collections := map[string]bool{}
+	for _, point := range points {
+		for collection := range point.Counts {
+			collections[collection] = true
+		}
+	}
+	names := make([]string, 0, len(collections))
+	for name := range collections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "STARTED\tTOTAL\t%s\n", strings.Join(names, "\t"))
+	for _, point := range points {
+		values := make([]string, len(names))
+		for i, name := range names {
+			values[i] = fmt.Sprintf("%d", point.Counts[name])
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\n", point.StartedAt.Local().Format(time.RFC3339), point.Total, strings.Join(values, "\t"))
+	}
+	w.Flush()
+}
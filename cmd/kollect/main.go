@@ -16,57 +16,79 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/michaelcade/kollect/pkg/aws"
+	_ "github.com/michaelcade/kollect/pkg/aws"
 	"github.com/michaelcade/kollect/pkg/azure"
+	"github.com/michaelcade/kollect/pkg/collector"
+	"github.com/michaelcade/kollect/pkg/config"
 	"github.com/michaelcade/kollect/pkg/cost"
 	"github.com/michaelcade/kollect/pkg/docker"
+	"github.com/michaelcade/kollect/pkg/export"
 	"github.com/michaelcade/kollect/pkg/gcp"
-	"github.com/michaelcade/kollect/pkg/kollect"
+	"github.com/michaelcade/kollect/pkg/history"
+	"github.com/michaelcade/kollect/pkg/inventory"
+	_ "github.com/michaelcade/kollect/pkg/kollect"
+	"github.com/michaelcade/kollect/pkg/metrics"
+	"github.com/michaelcade/kollect/pkg/scheduler"
 	"github.com/michaelcade/kollect/pkg/snapshots"
 	"github.com/michaelcade/kollect/pkg/terraform"
 	"github.com/michaelcade/kollect/pkg/vault"
 	"github.com/michaelcade/kollect/pkg/veeam"
 	"golang.org/x/term"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
+const defaultAddr = ":8080"
+
 var (
-	dataMutex sync.Mutex
-	data      interface{}
+	dataMutex    sync.Mutex
+	data         interface{}
+	historyStore *history.Store
+	// activeScheduler is set in serve mode so /metrics can report run status.
+	activeScheduler *scheduler.Scheduler
+	// configSources holds the sources of the config file when one is given,
+	// so the web interface collects from them instead of the flag defaults.
+	configSources []collector.Source
 	//go:embed web/*
 	staticFiles embed.FS
 )
 
 func main() {
-	storageOnly := flag.Bool("storage", false, "Collect only storage-related objects (Kubernetes Only)")
-	kubeconfig := flag.String("kubeconfig", filepath.Join(os.Getenv("HOME"), ".kube", "config"), "Path to the kubeconfig file")
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "diff":
+			runDiff(os.Args[2:])
+			return
+		case "history":
+			runHistory(os.Args[2:])
+			return
+		case "serve":
+			runServe(os.Args[2:])
+			return
+		}
+	}
+
+	opts := collector.Options{VeeamIgnoreSSL: true}
+	addCollectorFlags(flag.CommandLine, &opts)
 	browser := flag.Bool("browser", false, "Open the web interface in a browser (can be used alone to import data)")
-	dockerHost := flag.String("docker-host", "", "Docker host (e.g. unix:///var/run/docker.sock or tcp://host:2375)")
-	output := flag.String("output", "", "Output file to save the collected data")
-	inventoryType := flag.String("inventory", "", "Type of inventory to collect (kubernetes/aws/azure/gcp/terraform/vault/docker/veeam)")
-	baseURL := flag.String("veeam-url", "", "Veeam server URL")
-	username := flag.String("veeam-username", "", "Veeam username")
-	password := flag.String("veeam-password", "", "Veeam password")
-	terraformStateFile := flag.String("terraform-state", "", "Path to a local Terraform state file")
-	terraformS3Bucket := flag.String("terraform-s3", "", "S3 bucket containing Terraform state (format: bucket/key)")
-	terraformS3Region := flag.String("terraform-s3-region", "", "AWS region for S3 bucket (defaults to AWS_REGION env var)")
-	terraformAzureContainer := flag.String("terraform-azure", "", "Azure storage container (format: storageaccount/container/blob)")
-	terraformGCSBucket := flag.String("terraform-gcs", "", "GCS bucket and object (format: bucket/object)")
-	kubeContext := flag.String("kube-context", "", "Kubernetes context to use")
+	output := flag.String("output", "", "Output file to save the collected data (a directory for --format csv)")
+	format := flag.String("format", "json", fmt.Sprintf("Output format for --output (%s)", strings.Join(export.Formats, "/")))
+	inventoryType := flag.String("inventory", "", "Type of inventory to collect (kubernetes/aws/azure/gcp/terraform/vault/docker/veeam), a comma separated list, or all")
 	snapshotFlag := flag.Bool("snapshots", false, "Collect snapshots from all available platforms")
-	vaultAddr := flag.String("vault-addr", "", "Vault server address")
-	vaultToken := flag.String("vault-token", "", "Vault token")
+	historyPath := flag.String("history", "", "Append each collection to this history database (e.g. "+defaultHistoryPath+")")
+	configPath := flag.String("config", "", "Configuration file describing named sources (--inventory then selects sources by name or type)")
 	help := flag.Bool("help", false, "Show help message")
 
 	flag.Parse()
 	if *help {
 		fmt.Println("Usage: kollect [flags]")
+		fmt.Println("       kollect diff [--format text|json|markdown] old.json new.json")
+		fmt.Println("       kollect history <list|show|counts> [--history path]")
+		fmt.Println("       kollect serve [--config kollect.yaml] [--schedule source=spec ...] [--addr :8080]")
 		fmt.Println("Flags:")
 		flag.PrintDefaults()
 		fmt.Println("\nTo pretty-print JSON output, you can use `jq`:")
@@ -74,24 +96,67 @@ func main() {
 		return
 	}
 
-	if *snapshotFlag {
-		fmt.Println("Collecting snapshots from all available platforms...")
-		kubeconfigPath := filepath.Join(os.Getenv("HOME"), ".kube", "config")
-		snapshotData, err := snapshots.CollectAllSnapshots(context.Background(), kubeconfigPath)
+	if !contains(export.Formats, *format) {
+		log.Fatalf("Invalid format %q (available: %s)", *format, strings.Join(export.Formats, ", "))
+	}
+	if *format != "json" && *output == "" {
+		log.Fatalf("--format %s requires --output", *format)
+	}
+
+	if *historyPath != "" {
+		store, err := history.Open(*historyPath)
 		if err != nil {
-			fmt.Printf("Error collecting snapshots: %v\n", err)
-			os.Exit(1)
+			log.Fatalf("%v", err)
 		}
+		defer store.Close()
+		historyStore = store
+	}
 
-		outputData := *output
-		if outputData != "" {
-			jsonData, err := json.MarshalIndent(snapshotData, "", "  ")
+	var sources []collector.Source
+	if *configPath != "" {
+		if err := checkConfigFlags(flag.CommandLine); err != nil {
+			log.Fatalf("%v", err)
+		}
+		cfg, err := config.Load(*configPath)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		selected, err := cfg.Select(*inventoryType)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		sources, err = config.Collectors(selected)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		configSources = sources
+	}
+
+	if *snapshotFlag {
+		ctx := context.Background()
+		var snapshotData *inventory.Document
+		if *configPath != "" {
+			fmt.Printf("Collecting snapshots from the sources in %s...\n", *configPath)
+			snapshotData = snapshots.CollectSources(ctx, sources)
+		} else {
+			fmt.Println("Collecting snapshots from all available platforms...")
+			startedAt := time.Now().UTC()
+			collected, err := snapshots.CollectAllSnapshots(ctx, opts)
 			if err != nil {
-				fmt.Printf("Error marshaling data: %v\n", err)
+				fmt.Printf("Error collecting snapshots: %v\n", err)
 				os.Exit(1)
 			}
 
-			err = os.WriteFile(outputData, jsonData, 0644)
+			snapshotData = inventory.NewDocument()
+			snapshotData.StartedAt = startedAt
+			snapshotData.Sources["snapshots"] = collector.Succeeded(ctx, "snapshots", opts, collected, startedAt)
+			snapshotData.Finish()
+		}
+		recordHistory(snapshotData)
+
+		outputData := *output
+		if outputData != "" {
+			err := saveDocument(snapshotData, outputData, *format)
 			if err != nil {
 				fmt.Printf("Error writing to file: %v\n", err)
 				os.Exit(1)
@@ -111,15 +176,15 @@ func main() {
 		return
 	}
 
-	if *browser && *inventoryType == "" && *output == "" {
+	if *browser && *inventoryType == "" && *output == "" && *configPath == "" {
 		fmt.Println("Starting browser interface. Use the import function to load data.")
-		startWebServer(map[string]interface{}{}, true, "", "", "")
+		startWebServer(map[string]interface{}{}, true, opts, defaultAddr)
 		return
 	}
 
-	if *inventoryType == "" && !*snapshotFlag && !(*browser && *output == "") {
+	if *inventoryType == "" && *configPath == "" && !*snapshotFlag && !(*browser && *output == "") {
 		fmt.Println("Error: You must specify an inventory type with --inventory")
-		fmt.Println("Available inventory types: kubernetes, aws, azure, gcp, veeam, terraform, vault, docker")
+		fmt.Printf("Available inventory types: %s\n", strings.Join(collector.Names(), ", "))
 		fmt.Println("Or use --browser alone to start web interface for importing data")
 		fmt.Println("Or use --snapshots to collect snapshot data from all available platforms")
 		os.Exit(1)
@@ -127,110 +192,38 @@ func main() {
 
 	ctx := context.Background()
 
-	var err error
-	switch *inventoryType {
-	case "aws":
-		data, err = aws.CollectAWSData(ctx)
-	case "azure":
-		data, err = azure.CollectAzureData(ctx)
-	case "gcp":
-		data, err = gcp.CollectGCPData(ctx)
-	case "kubernetes":
-		if *kubeContext != "" {
-			data, err = collectData(ctx, *storageOnly, *kubeconfig, *kubeContext)
-		} else {
-			data, err = collectData(ctx, *storageOnly, *kubeconfig)
-		}
-	case "terraform":
-		if *terraformStateFile != "" {
-			data, err = terraform.CollectTerraformData(ctx, *terraformStateFile)
-		} else if *terraformS3Bucket != "" {
-			parts := strings.SplitN(*terraformS3Bucket, "/", 2)
-			if len(parts) != 2 {
-				fmt.Println("Error: terraform-s3 flag must be in format 'bucket/key'")
-				os.Exit(1)
-			}
-			region := *terraformS3Region
-			if region == "" {
-				region = os.Getenv("AWS_REGION")
-				if region == "" {
-					region = "us-east-1"
-				}
-			}
-			data, err = terraform.CollectTerraformDataFromS3(ctx, parts[0], parts[1], region)
-		} else if *terraformAzureContainer != "" {
-			parts := strings.SplitN(*terraformAzureContainer, "/", 3)
-			if len(parts) != 3 {
-				fmt.Println("Error: terraform-azure flag must be in format 'storageaccount/container/blob'")
-				os.Exit(1)
-			}
-			data, err = terraform.CollectTerraformDataFromAzure(ctx, parts[0], parts[1], parts[2])
-		} else if *terraformGCSBucket != "" {
-			parts := strings.SplitN(*terraformGCSBucket, "/", 2)
-			if len(parts) != 2 {
-				fmt.Println("Error: terraform-gcs flag must be in format 'bucket/object'")
-				os.Exit(1)
-			}
-			data, err = terraform.CollectTerraformDataFromGCS(ctx, parts[0], parts[1])
-		} else {
-			fmt.Println("Error: You must specify a Terraform state source with one of:")
-			fmt.Println("  --terraform-state (local file)")
-			fmt.Println("  --terraform-s3 (AWS S3)")
-			fmt.Println("  --terraform-azure (Azure Blob Storage)")
-			fmt.Println("  --terraform-gcs (Google Cloud Storage)")
-			os.Exit(1)
-		}
-	case "veeam":
-		if *baseURL == "" {
-			*baseURL = os.Getenv("VBR_SERVER_URL")
-		}
-		if *baseURL == "" {
-			serverAddress := promptUser("Enter VBR Server IP or DNS name: ")
-			*baseURL = fmt.Sprintf("https://%s:9419", serverAddress)
-		}
-		if *username == "" {
-			*username = getEnv("VBR_USERNAME", "Enter VBR Username: ")
-		}
-		if *password == "" {
-			*password = getSensitiveInput("Enter VBR Password: ")
-		}
-		if !strings.HasPrefix(*baseURL, "http://") && !strings.HasPrefix(*baseURL, "https://") {
-			*baseURL = "http://" + *baseURL
+	if *configPath == "" {
+		names, err := collector.ParseNames(*inventoryType)
+		if err != nil {
+			log.Fatalf("%v (available: %s, all)", err, strings.Join(collector.Names(), ", "))
 		}
 
-		data, err = veeam.CollectVeeamData(ctx, *baseURL, *username, *password, true)
-	case "docker":
-		data, err = docker.CollectDockerData(ctx, *dockerHost)
-
-	default:
-		log.Fatalf("Invalid inventory type: %s", *inventoryType)
-	case "vault":
-		vaultAddress := *vaultAddr
-		vaultTokenVal := *vaultToken
-		vaultIgnoreSSL := false
-
-		if vaultAddress == "" {
-			vaultAddress = os.Getenv("VAULT_ADDR")
-			if vaultAddress == "" {
-				vaultAddress = promptUser("Enter Vault server address (e.g. http://localhost:8200): ")
+		// Sources that are not configured are expected to fail when collecting
+		// everything, so only prompt when sources were named explicitly.
+		if *inventoryType != "all" {
+			for _, name := range names {
+				promptForMissingOptions(name, &opts)
 			}
 		}
+		sources = collector.Sources(names, opts)
+	}
 
-		if vaultTokenVal == "" {
-			vaultTokenVal = os.Getenv("VAULT_TOKEN")
-			if vaultTokenVal == "" {
-				vaultTokenVal = getSensitiveInput("Enter Vault token: ")
-			}
+	collected := collector.RunSources(ctx, sources)
+	if failed := collected.Failed(); len(failed) > 0 {
+		if len(sources) == 1 {
+			log.Fatalf("Error collecting data: %s", collected.Sources[sources[0].Name].Error)
 		}
-
-		data, err = vault.CollectVaultData(ctx, vaultAddress, vaultTokenVal, vaultIgnoreSSL)
-	}
-	if err != nil {
-		log.Fatalf("Error collecting data: %v", err)
+		log.Printf("Warning: Failed to collect %d of %d sources: %s", len(failed), len(sources), strings.Join(failed, ", "))
 	}
 
+	recordHistory(collected)
+
+	dataMutex.Lock()
+	data = collected
+	dataMutex.Unlock()
+
 	if *output != "" {
-		err = saveToFile(data, *output)
+		err := saveDocument(collected, *output, *format)
 		if err != nil {
 			log.Fatalf("Error saving data to file: %v", err)
 		}
@@ -238,63 +231,92 @@ func main() {
 		return
 	}
 
-	printData(data)
+	printData(collected)
 
 	if *browser {
-		startWebServer(data, true, *baseURL, *username, *password)
+		startWebServer(collected, true, opts, defaultAddr)
 	}
 
 }
 
-func collectAllSnapshots(ctx context.Context) (map[string]interface{}, error) {
-	results := make(map[string]interface{})
-	credentials := checkCredentials(ctx)
+// addCollectorFlags registers the flags that configure collectors and
+// stores their values in opts.
+func addCollectorFlags(flags *flag.FlagSet, opts *collector.Options) {
+	flags.BoolVar(&opts.StorageOnly, "storage", false, "Collect only storage-related objects (Kubernetes Only)")
+	flags.StringVar(&opts.Kubeconfig, "kubeconfig", filepath.Join(os.Getenv("HOME"), ".kube", "config"), "Path to the kubeconfig file")
+	flags.StringVar(&opts.KubeContext, "kube-context", "", "Kubernetes context to use")
+	flags.StringVar(&opts.AWSProfile, "aws-profile", "", "AWS shared config profile to use, or a comma separated list to collect several accounts (defaults to AWS_PROFILE or the default profile)")
+	flags.StringVar(&opts.AWSRole, "aws-role", "", "IAM role name to assume into every member account of the AWS Organization (e.g. OrganizationAccountAccessRole)")
+	flags.StringVar(&opts.AzureSubscription, "azure-subscription", "", "Azure subscription ID or name to collect, or a comma separated list (defaults to every subscription the credential can see)")
+	flags.StringVar(&opts.AzureManagementGroup, "azure-management-group", "", "Azure management group ID whose subscriptions are collected")
+	flags.BoolVar(&opts.AzureResourceGraph, "azure-resource-graph", false, "Collect Azure resources with Resource Graph queries instead of one ARM list per resource type")
+	flags.BoolVar(&opts.AzureRaw, "azure-raw", false, "Also export the raw Azure SDK objects alongside the kollect summaries")
+	flags.StringVar(&opts.DockerHost, "docker-host", "", "Docker host (e.g. unix:///var/run/docker.sock or tcp://host:2375)")
+	flags.StringVar(&opts.VeeamURL, "veeam-url", "", "Veeam server URL")
+	flags.StringVar(&opts.VeeamUsername, "veeam-username", "", "Veeam username")
+	flags.StringVar(&opts.VeeamPassword, "veeam-password", "", "Veeam password")
+	flags.StringVar(&opts.TerraformStateFile, "terraform-state", "", "Path to a local Terraform state file")
+	flags.StringVar(&opts.TerraformS3Bucket, "terraform-s3", "", "S3 bucket containing Terraform state (format: bucket/key)")
+	flags.StringVar(&opts.TerraformS3Region, "terraform-s3-region", "", "AWS region for S3 bucket (defaults to AWS_REGION env var)")
+	flags.StringVar(&opts.TerraformAzureContainer, "terraform-azure", "", "Azure storage container (format: storageaccount/container/blob)")
+	flags.StringVar(&opts.TerraformGCSBucket, "terraform-gcs", "", "GCS bucket and object (format: bucket/object)")
+	flags.StringVar(&opts.VaultAddr, "vault-addr", "", "Vault server address")
+	flags.StringVar(&opts.VaultToken, "vault-token", "", "Vault token")
+}
 
-	if credentials["kubernetes"] {
-		fmt.Println("Collecting Kubernetes snapshots...")
-		k8sSnapshots, err := kollect.CollectSnapshotData(ctx, filepath.Join(os.Getenv("HOME"), ".kube", "config"))
-		if err != nil {
-			fmt.Printf("Warning: Error collecting Kubernetes snapshots: %v\n", err)
-		} else if k8sSnapshots != nil {
-			fmt.Println("Successfully collected Kubernetes snapshots")
-			results["kubernetes"] = k8sSnapshots
-		}
-	}
+// checkConfigFlags rejects collector flags given alongside --config, since
+// the sources in the config file carry their own options and the flags
+// would otherwise be silently ignored.
+func checkConfigFlags(flags *flag.FlagSet) error {
+	collectorFlags := flag.NewFlagSet("", flag.ContinueOnError)
+	addCollectorFlags(collectorFlags, &collector.Options{})
 
-	if credentials["aws"] {
-		fmt.Println("Collecting AWS snapshots...")
-		awsSnapshots, err := aws.CollectSnapshotData(ctx)
-		if err != nil {
-			fmt.Printf("Warning: Error collecting AWS snapshots: %v\n", err)
-		} else if awsSnapshots != nil {
-			fmt.Println("Successfully collected AWS snapshots")
-			results["aws"] = awsSnapshots
+	var set []string
+	flags.Visit(func(f *flag.Flag) {
+		if collectorFlags.Lookup(f.Name) != nil {
+			set = append(set, "--"+f.Name)
 		}
+	})
+	if len(set) > 0 {
+		return fmt.Errorf("%s cannot be used with --config, set these options on the sources in the config file instead", strings.Join(set, ", "))
 	}
+	return nil
+}
 
-	if credentials["azure"] {
-		fmt.Println("Collecting Azure snapshots...")
-		azureSnapshots, err := azure.CollectSnapshotData(ctx)
-		if err != nil {
-			fmt.Printf("Warning: Error collecting Azure snapshots: %v\n", err)
-		} else if azureSnapshots != nil {
-			fmt.Println("Successfully collected Azure snapshots")
-			results["azure"] = azureSnapshots
+func promptForMissingOptions(name string, opts *collector.Options) {
+	switch name {
+	case "veeam":
+		if opts.VeeamURL == "" {
+			opts.VeeamURL = os.Getenv("VBR_SERVER_URL")
+		}
+		if opts.VeeamURL == "" {
+			serverAddress := promptUser("Enter VBR Server IP or DNS name: ")
+			opts.VeeamURL = fmt.Sprintf("https://%s:9419", serverAddress)
+		}
+		if opts.VeeamUsername == "" {
+			opts.VeeamUsername = getEnv("VBR_USERNAME", "Enter VBR Username: ")
+		}
+		if opts.VeeamPassword == "" {
+			opts.VeeamPassword = getSensitiveInput("Enter VBR Password: ")
+		}
+		if !strings.HasPrefix(opts.VeeamURL, "http://") && !strings.HasPrefix(opts.VeeamURL, "https://") {
+			opts.VeeamURL = "http://" + opts.VeeamURL
+		}
+	case "vault":
+		if opts.VaultAddr == "" {
+			opts.VaultAddr = os.Getenv("VAULT_ADDR")
+			if opts.VaultAddr == "" {
+				opts.VaultAddr = promptUser("Enter Vault server address (e.g. http://localhost:8200): ")
+			}
 		}
-	}
 
-	if credentials["gcp"] {
-		fmt.Println("Collecting GCP snapshots...")
-		gcpSnapshots, err := gcp.CollectSnapshotData(ctx)
-		if err != nil {
-			fmt.Printf("Warning: Error collecting GCP snapshots: %v\n", err)
-		} else if gcpSnapshots != nil {
-			fmt.Println("Successfully collected GCP snapshots")
-			results["gcp"] = gcpSnapshots
+		if opts.VaultToken == "" {
+			opts.VaultToken = os.Getenv("VAULT_TOKEN")
+			if opts.VaultToken == "" {
+				opts.VaultToken = getSensitiveInput("Enter Vault token: ")
+			}
 		}
 	}
-
-	return results, nil
 }
 
 func promptUser(prompt string) string {
@@ -322,18 +344,6 @@ func getEnv(envVar, prompt string) string {
 	return value
 }
 
-func collectData(ctx context.Context, storageOnly bool, kubeconfigPath string, contextName ...string) (interface{}, error) {
-	if storageOnly {
-		return kollect.CollectStorageData(ctx, kubeconfigPath)
-	}
-
-	if len(contextName) > 0 && contextName[0] != "" {
-		return kollect.CollectDataWithContext(ctx, kubeconfigPath, contextName[0])
-	}
-
-	return kollect.CollectData(ctx, kubeconfigPath)
-}
-
 func saveToFile(data interface{}, filename string) error {
 	file, err := os.Create(filename)
 	if err != nil {
@@ -350,6 +360,18 @@ func saveToFile(data interface{}, filename string) error {
 	return err
 }
 
+// saveDocument writes doc to path as JSON, a directory of CSV files, or an
+// Excel workbook.
+func saveDocument(doc *inventory.Document, path, format string) error {
+	switch format {
+	case "csv":
+		return export.WriteCSV(doc, path)
+	case "xlsx":
+		return export.WriteXLSX(doc, path)
+	}
+	return saveToFile(doc, path)
+}
+
 func printData(data interface{}) {
 	prettyData, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
@@ -358,47 +380,133 @@ func printData(data interface{}) {
 	fmt.Println(string(prettyData))
 }
 
-func checkCredentials(ctx context.Context) map[string]bool {
-	results := make(map[string]bool)
-
-	k8sConfig, err := clientcmd.BuildConfigFromFlags("", filepath.Join(os.Getenv("HOME"), ".kube", "config"))
-	if err == nil {
-		clientset, err := kubernetes.NewForConfig(k8sConfig)
-		if err == nil {
-			_, err = clientset.CoreV1().Namespaces().List(ctx, v1.ListOptions{Limit: 1})
-			results["kubernetes"] = err == nil
+// platformSources returns the sources the web interface uses for platform:
+// those in the config file, or one with the command line options when
+// kollect was started without --config or the config has none for platform.
+func platformSources(platform string, opts collector.Options) []collector.Source {
+	var sources []collector.Source
+	for _, src := range configSources {
+		if src.Platform == platform {
+			sources = append(sources, src)
 		}
-	} else {
-		results["kubernetes"] = false
 	}
+	if len(sources) == 0 {
+		sources = collector.Sources([]string{platform}, opts)
+	}
+	return sources
+}
 
-	awsHasCredentials, _ := aws.CheckCredentials(ctx)
-	results["aws"] = awsHasCredentials
+// platformOptions returns the options of the first source for platform, which
+// collections started from the web interface build on.
+func platformOptions(platform string, opts collector.Options) (collector.Options, error) {
+	return resolveOptions(platformSources(platform, opts)[0])
+}
 
-	azureHasCredentials, _ := azure.CheckCredentials(ctx)
-	results["azure"] = azureHasCredentials
+func resolveOptions(src collector.Source) (collector.Options, error) {
+	opts := src.Options
+	if src.Resolve != nil {
+		if err := src.Resolve(&opts); err != nil {
+			return collector.Options{}, err
+		}
+	}
+	return opts, nil
+}
 
-	gcpHasCredentials, _ := gcp.CheckCredentials(ctx)
-	results["gcp"] = gcpHasCredentials
+// checkCredentials reports which platforms have credentials available. Vault
+// and Veeam are not probed, since they are connected from the web interface,
+// and Veeam counts as connected once its data has been loaded.
+func checkCredentials(ctx context.Context, opts collector.Options) map[string]bool {
+	results := make(map[string]bool)
 
-	dockerHasCredentials, _ := docker.CheckCredentials(ctx, "")
-	results["docker"] = dockerHasCredentials
+	for _, c := range collector.All() {
+		if c.Name() == "vault" || c.Name() == "veeam" {
+			continue
+		}
+		results[c.Name()] = false
+		for _, src := range platformSources(c.Name(), opts) {
+			srcOpts, err := resolveOptions(src)
+			if err != nil {
+				continue
+			}
+			if hasCredentials, _ := c.CheckCredentials(ctx, srcOpts); hasCredentials {
+				results[c.Name()] = true
+				break
+			}
+		}
+	}
 
 	dataMutex.Lock()
 	veeamConnected := false
-	if d, ok := data.(veeam.VeeamData); ok {
+	current := data
+	if doc, ok := data.(*inventory.Document); ok {
+		if result, ok := doc.Sources["veeam"]; ok {
+			current = result.Data
+		}
+	}
+	if d, ok := current.(veeam.VeeamData); ok {
 		veeamConnected = d.ServerInfo != nil && len(d.ServerInfo) > 0
 	}
 	dataMutex.Unlock()
 	results["veeam"] = veeamConnected
 
-	_, err = exec.LookPath("terraform")
-	results["terraform"] = err == nil
-
 	return results
 }
 
-func startWebServer(initialData interface{}, openBrowser bool, baseURL, username, password string) {
+// collectingDisabled answers the request with an error in serve mode, where
+// the web interface shows the scheduler's inventory and collecting a single
+// source from it would replace that until the next scheduled run.
+func collectingDisabled(w http.ResponseWriter) bool {
+	if activeScheduler == nil {
+		return false
+	}
+	http.Error(w, "Collecting from the web interface is disabled in serve mode, sources are collected on their schedule", http.StatusConflict)
+	return true
+}
+
+// setSource replaces the data served by the web interface with a document
+// holding the single source that was just collected.
+func setSource(ctx context.Context, name string, opts collector.Options, collected interface{}, startedAt time.Time) {
+	doc := inventory.NewDocument()
+	doc.StartedAt = startedAt
+	doc.Sources[name] = collector.Succeeded(ctx, name, opts, collected, startedAt)
+	doc.Finish()
+	recordHistory(doc)
+
+	dataMutex.Lock()
+	data = doc
+	dataMutex.Unlock()
+}
+
+func recordHistory(doc *inventory.Document) {
+	if historyStore == nil {
+		return
+	}
+	run, err := historyStore.Append(doc)
+	if err != nil {
+		log.Printf("Warning: %v", err)
+		return
+	}
+	log.Printf("Saved run %s to history", run.ID)
+}
+
+// currentDocument returns the data being served when it is an inventory
+// document, or nil for legacy data.
+func currentDocument() *inventory.Document {
+	dataMutex.Lock()
+	defer dataMutex.Unlock()
+	doc, _ := data.(*inventory.Document)
+	return doc
+}
+
+// startWebServer serves the web interface on addr. When initialData is nil
+// the data already set by the caller is served.
+func startWebServer(initialData interface{}, openBrowser bool, opts collector.Options, addr string) {
+	if initialData != nil {
+		dataMutex.Lock()
+		data = initialData
+		dataMutex.Unlock()
+	}
+
 	fsys, err := fs.Sub(staticFiles, "web")
 	if err != nil {
 		panic(err)
@@ -417,13 +525,15 @@ func startWebServer(initialData interface{}, openBrowser bool, baseURL, username
 		}
 	})
 
+	http.Handle("/metrics", metrics.Handler(currentDocument, activeScheduler))
+
 	http.HandleFunc("/api/costs", cost.HandleCostRequest)
 	http.HandleFunc("/api/refresh-pricing", cost.HandleRefreshPricing)
 	http.HandleFunc("/api/pricing-info", cost.HandlePricingInfo)
 
 	http.HandleFunc("/api/check-credentials", func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
-		results := checkCredentials(ctx)
+		results := checkCredentials(ctx, opts)
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(results)
@@ -434,60 +544,149 @@ func startWebServer(initialData interface{}, openBrowser bool, baseURL, username
 			http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
 			return
 		}
-		var importedData interface{}
-		err := json.NewDecoder(r.Body).Decode(&importedData)
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		importedData, fromVersion, err := inventory.Decode(body)
 		if err != nil {
 			log.Printf("Error decoding imported data: %v", err)
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		if fromVersion < inventory.SchemaVersion {
+			log.Printf("Migrated imported data from schema version %d to %d", fromVersion, inventory.SchemaVersion)
+		}
 		dataMutex.Lock()
 		data = importedData
 		dataMutex.Unlock()
 		w.WriteHeader(http.StatusOK)
-		err = json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+		err = json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":        "success",
+			"schemaVersion": inventory.SchemaVersion,
+			"migratedFrom":  fromVersion,
+		})
 		if err != nil {
 			log.Printf("Error encoding response: %v", err)
 		}
 	})
 
-	http.HandleFunc("/api/switch", func(w http.ResponseWriter, r *http.Request) {
-		inventoryType := r.URL.Query().Get("type")
-		ctx := context.Background()
+	http.HandleFunc("/api/history", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if historyStore == nil {
+			json.NewEncoder(w).Encode(map[string]interface{}{"enabled": false})
+			return
+		}
+
+		runs, err := historyStore.Runs(r.URL.Query().Get("source"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"enabled": true,
+			"runs":    runs,
+		})
+	})
+
+	http.HandleFunc("/api/history/load", func(w http.ResponseWriter, r *http.Request) {
+		if historyStore == nil {
+			http.Error(w, "History is not enabled, start kollect with --history", http.StatusNotFound)
+			return
+		}
+
+		var doc *inventory.Document
 		var err error
-		switch inventoryType {
-		case "aws":
-			data, err = aws.CollectAWSData(ctx)
-		case "azure":
-			data, err = azure.CollectAzureData(ctx)
-		case "kubernetes":
-			data, err = collectData(ctx, false, filepath.Join(os.Getenv("HOME"), ".kube", "config"))
-		case "gcp":
-			data, err = gcp.CollectGCPData(ctx)
-		case "terraform":
-			if r.URL.Query().Get("state-file") == "" {
-				http.Error(w, "Terraform state file must be provided", http.StatusBadRequest)
+		if at := r.URL.Query().Get("at"); at != "" {
+			t, parseErr := parseTime(at)
+			if parseErr != nil {
+				http.Error(w, parseErr.Error(), http.StatusBadRequest)
 				return
 			}
-			data, err = terraform.CollectTerraformData(ctx, r.URL.Query().Get("state-file"))
-		case "veeam":
-			if baseURL == "" || username == "" || password == "" {
-				http.Error(w, "Veeam URL, username, and password must be provided", http.StatusBadRequest)
+			doc, err = historyStore.At(t, r.URL.Query().Get("source"))
+		} else {
+			doc, err = historyStore.Load(r.URL.Query().Get("id"))
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		dataMutex.Lock()
+		data = doc
+		dataMutex.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+	})
+
+	http.HandleFunc("/api/history/counts", func(w http.ResponseWriter, r *http.Request) {
+		if historyStore == nil {
+			http.Error(w, "History is not enabled, start kollect with --history", http.StatusNotFound)
+			return
+		}
+
+		counts := map[string][]history.Point{}
+		sources := []string{r.URL.Query().Get("source")}
+		if sources[0] == "" {
+			runs, err := historyStore.Runs("")
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
 			}
-			data, err = veeam.CollectVeeamData(ctx, baseURL, username, password, true)
-		default:
+			sources = nil
+			for _, run := range runs {
+				for _, name := range run.Sources {
+					if !contains(sources, name) {
+						sources = append(sources, name)
+					}
+				}
+			}
+		}
+
+		for _, source := range sources {
+			points, err := historyStore.Counts(source)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			counts[source] = points
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(counts)
+	})
+
+	http.HandleFunc("/api/switch", func(w http.ResponseWriter, r *http.Request) {
+		if collectingDisabled(w) {
+			return
+		}
+		inventoryType := r.URL.Query().Get("type")
+		c, ok := collector.Get(inventoryType)
+		if !ok {
 			http.Error(w, "Invalid inventory type", http.StatusBadRequest)
 			return
 		}
+
+		switchOpts, err := platformOptions(c.Name(), opts)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if stateFile := r.URL.Query().Get("state-file"); stateFile != "" {
+			switchOpts.TerraformStateFile = stateFile
+		}
+
+		ctx := context.Background()
+		startedAt := time.Now().UTC()
+		collected, err := c.Collect(ctx, switchOpts)
 		if err != nil {
 			log.Printf("Error collecting data: %v", err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		dataMutex.Lock()
-		data = data
-		dataMutex.Unlock()
+		setSource(ctx, c.Name(), switchOpts, collected, startedAt)
 		w.WriteHeader(http.StatusOK)
 		err = json.NewEncoder(w).Encode(map[string]string{"status": "success"})
 		if err != nil {
@@ -598,6 +797,9 @@ func startWebServer(initialData interface{}, openBrowser bool, baseURL, username
 	})
 
 	http.HandleFunc("/api/veeam/connect", func(w http.ResponseWriter, r *http.Request) {
+		if collectingDisabled(w) {
+			return
+		}
 		if r.Method != http.MethodPost {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			return
@@ -621,6 +823,7 @@ func startWebServer(initialData interface{}, openBrowser bool, baseURL, username
 		}
 
 		ctx := r.Context()
+		startedAt := time.Now().UTC()
 
 		veeamData, err := veeam.CollectVeeamData(ctx, params.BaseUrl, params.Username, params.Password, params.IgnoreSSL)
 		if err != nil {
@@ -629,9 +832,9 @@ func startWebServer(initialData interface{}, openBrowser bool, baseURL, username
 			return
 		}
 
-		dataMutex.Lock()
-		data = veeamData
-		dataMutex.Unlock()
+		veeamOpts := opts
+		veeamOpts.VeeamURL = params.BaseUrl
+		setSource(ctx, "veeam", veeamOpts, veeamData, startedAt)
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]string{
@@ -665,6 +868,9 @@ func startWebServer(initialData interface{}, openBrowser bool, baseURL, username
 	})
 
 	http.HandleFunc("/api/vault/connect", func(w http.ResponseWriter, r *http.Request) {
+		if collectingDisabled(w) {
+			return
+		}
 		if r.Method != http.MethodPost {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			return
@@ -687,6 +893,7 @@ func startWebServer(initialData interface{}, openBrowser bool, baseURL, username
 		}
 
 		ctx := r.Context()
+		startedAt := time.Now().UTC()
 		var vaultToken string
 		vaultAddr := params.Server
 
@@ -742,9 +949,9 @@ func startWebServer(initialData interface{}, openBrowser bool, baseURL, username
 			return
 		}
 
-		dataMutex.Lock()
-		data = vaultData
-		dataMutex.Unlock()
+		vaultOpts := opts
+		vaultOpts.VaultAddr = vaultAddr
+		setSource(ctx, "vault", vaultOpts, vaultData, startedAt)
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]string{
@@ -837,6 +1044,9 @@ func startWebServer(initialData interface{}, openBrowser bool, baseURL, username
 	})
 
 	http.HandleFunc("/api/kubernetes/connect", func(w http.ResponseWriter, r *http.Request) {
+		if collectingDisabled(w) {
+			return
+		}
 		if r.Method != http.MethodPost {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			return
@@ -862,16 +1072,25 @@ func startWebServer(initialData interface{}, openBrowser bool, baseURL, username
 		}
 
 		ctx := r.Context()
+		startedAt := time.Now().UTC()
+
+		kubeOpts, err := platformOptions("kubernetes", opts)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		kubeOpts.Kubeconfig = params.KubeconfigPath
+		kubeOpts.KubeContext = params.Context
+		kubeOpts.StorageOnly = false
 
-		kubeData, err := collectData(ctx, false, params.KubeconfigPath, params.Context)
+		kubernetesCollector, _ := collector.Get("kubernetes")
+		kubeData, err := kubernetesCollector.Collect(ctx, kubeOpts)
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Error connecting to Kubernetes: %v", err), http.StatusInternalServerError)
 			return
 		}
 
-		dataMutex.Lock()
-		data = kubeData
-		dataMutex.Unlock()
+		setSource(ctx, "kubernetes", kubeOpts, kubeData, startedAt)
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]string{
@@ -927,17 +1146,22 @@ func startWebServer(initialData interface{}, openBrowser bool, baseURL, username
 	})
 
 	http.HandleFunc("/api/aws/connect", func(w http.ResponseWriter, r *http.Request) {
+		if collectingDisabled(w) {
+			return
+		}
 		if r.Method != http.MethodPost {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			return
 		}
 
 		var params struct {
-			Type      string `json:"type"`
-			Profile   string `json:"profile"`
-			AccessKey string `json:"accessKey"`
-			SecretKey string `json:"secretKey"`
-			Region    string `json:"region"`
+			Type      string   `json:"type"`
+			Profile   string   `json:"profile"`
+			Profiles  []string `json:"profiles"`
+			Role      string   `json:"role"`
+			AccessKey string   `json:"accessKey"`
+			SecretKey string   `json:"secretKey"`
+			Region    string   `json:"region"`
 		}
 
 		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
@@ -945,32 +1169,43 @@ func startWebServer(initialData interface{}, openBrowser bool, baseURL, username
 			return
 		}
 
+		awsOpts, err := platformOptions("aws", opts)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		awsOpts.AWSProfile = ""
+		awsOpts.AWSRole = params.Role
 		if params.Type == "credentials" {
 			os.Setenv("AWS_ACCESS_KEY_ID", params.AccessKey)
 			os.Setenv("AWS_SECRET_ACCESS_KEY", params.SecretKey)
 			if params.Region != "" {
 				os.Setenv("AWS_REGION", params.Region)
 			}
-		} else if params.Type == "profile" && params.Profile != "" {
-			os.Setenv("AWS_PROFILE", params.Profile)
+		} else if params.Type == "profile" {
+			profiles := params.Profiles
+			if len(profiles) == 0 && params.Profile != "" {
+				profiles = []string{params.Profile}
+			}
+			awsOpts.AWSProfile = strings.Join(profiles, ",")
 		}
 
 		ctx := r.Context()
-		hasCredentials, err := aws.CheckCredentials(ctx)
+		startedAt := time.Now().UTC()
+		awsCollector, _ := collector.Get("aws")
+		hasCredentials, err := awsCollector.CheckCredentials(ctx, awsOpts)
 		if err != nil || !hasCredentials {
 			http.Error(w, fmt.Sprintf("Error connecting to AWS: %v", err), http.StatusBadRequest)
 			return
 		}
 
-		awsData, err := aws.CollectAWSData(ctx)
+		awsData, err := awsCollector.Collect(ctx, awsOpts)
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Error collecting AWS data: %v", err), http.StatusInternalServerError)
 			return
 		}
 
-		dataMutex.Lock()
-		data = awsData
-		dataMutex.Unlock()
+		setSource(ctx, "aws", awsOpts, awsData, startedAt)
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]string{
@@ -981,67 +1216,46 @@ func startWebServer(initialData interface{}, openBrowser bool, baseURL, username
 
 	http.HandleFunc("/api/azure/subscriptions", func(w http.ResponseWriter, r *http.Request) {
 		subscriptions := []map[string]string{}
-		defaultSub := ""
-		subscriptionsCount := 0
-
-		cmd := exec.Command("az", "account", "list", "--query", "[].{name:name, id:id, isDefault:isDefault}", "--output", "json")
-		output, err := cmd.Output()
-
-		if err == nil {
-			var azSubs []map[string]interface{}
-			if err := json.Unmarshal(output, &azSubs); err == nil {
-				subscriptionsCount = len(azSubs)
-
-				log.Printf("Found %d subscriptions in Azure CLI output", subscriptionsCount)
-
-				for _, sub := range azSubs {
-					if name, ok := sub["name"].(string); ok {
-						if id, ok := sub["id"].(string); ok {
-							subscription := map[string]string{
-								"name":      name,
-								"id":        id,
-								"isDefault": "false",
-							}
-
-							if isDefault, ok := sub["isDefault"].(bool); ok && isDefault {
-								subscription["isDefault"] = "true"
-								defaultSub = id
-							}
+		defaultSub := os.Getenv("AZURE_SUBSCRIPTION_ID")
 
-							subscriptions = append(subscriptions, subscription)
-						}
-					}
-				}
-
-				log.Printf("Processed %d Azure subscriptions", len(subscriptions))
-			} else {
-				log.Printf("Error parsing Azure CLI output: %v", err)
-			}
-		} else {
-			log.Printf("Error running Azure CLI command: %v", err)
+		found, err := azure.Subscriptions(r.Context(), nil, "")
+		if err != nil {
+			log.Printf("Error listing Azure subscriptions: %v", err)
 		}
+		for _, sub := range found {
+			subscriptions = append(subscriptions, map[string]string{
+				"name":      sub.Name,
+				"id":        sub.ID,
+				"isDefault": strconv.FormatBool(strings.EqualFold(sub.ID, defaultSub)),
+			})
+		}
+		log.Printf("Found %d Azure subscriptions", len(subscriptions))
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"subscriptions":      subscriptions,
 			"default":            defaultSub,
-			"subscriptionsCount": subscriptionsCount,
+			"subscriptionsCount": len(subscriptions),
 		})
 	})
 
 	http.HandleFunc("/api/azure/connect", func(w http.ResponseWriter, r *http.Request) {
+		if collectingDisabled(w) {
+			return
+		}
 		if r.Method != http.MethodPost {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			return
 		}
 
 		var params struct {
-			Type           string `json:"type"`
-			Subscription   string `json:"subscription"`
-			TenantId       string `json:"tenantId"`
-			ClientId       string `json:"clientId"`
-			ClientSecret   string `json:"clientSecret"`
-			SubscriptionId string `json:"subscriptionId"`
+			Type            string `json:"type"`
+			Subscription    string `json:"subscription"`
+			ManagementGroup string `json:"managementGroup"`
+			TenantId        string `json:"tenantId"`
+			ClientId        string `json:"clientId"`
+			ClientSecret    string `json:"clientSecret"`
+			SubscriptionId  string `json:"subscriptionId"`
 		}
 
 		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
@@ -1049,27 +1263,31 @@ func startWebServer(initialData interface{}, openBrowser bool, baseURL, username
 			return
 		}
 
+		azureOpts, err := platformOptions("azure", opts)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		azureOpts.AzureSubscription = params.Subscription
+		azureOpts.AzureManagementGroup = params.ManagementGroup
 		if params.Type == "service_principal" {
 			os.Setenv("AZURE_TENANT_ID", params.TenantId)
 			os.Setenv("AZURE_CLIENT_ID", params.ClientId)
 			os.Setenv("AZURE_CLIENT_SECRET", params.ClientSecret)
 			os.Setenv("AZURE_SUBSCRIPTION_ID", params.SubscriptionId)
-		} else if params.Type == "cli" && params.Subscription != "" {
-			cmd := exec.Command("az", "account", "set", "--subscription", params.Subscription)
-			if err := cmd.Run(); err != nil {
-				http.Error(w, fmt.Sprintf("Error setting Azure subscription: %v", err), http.StatusBadRequest)
-				return
-			}
+			azureOpts.AzureSubscription = params.SubscriptionId
 		}
 
 		ctx := r.Context()
-		hasCredentials, err := azure.CheckCredentials(ctx)
+		startedAt := time.Now().UTC()
+		azureCollector, _ := collector.Get("azure")
+		hasCredentials, err := azureCollector.CheckCredentials(ctx, azureOpts)
 		if err != nil || !hasCredentials {
 			http.Error(w, fmt.Sprintf("Error connecting to Azure: %v", err), http.StatusBadRequest)
 			return
 		}
 
-		azureData, err := azure.CollectAzureData(ctx)
+		azureData, err := azureCollector.Collect(ctx, azureOpts)
 		if err != nil {
 			if strings.Contains(err.Error(), "Authorization") ||
 				strings.Contains(err.Error(), "authorization") ||
@@ -1083,9 +1301,7 @@ func startWebServer(initialData interface{}, openBrowser bool, baseURL, username
 			return
 		}
 
-		dataMutex.Lock()
-		data = azureData
-		dataMutex.Unlock()
+		setSource(ctx, "azure", azureOpts, azureData, startedAt)
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]string{
@@ -1136,15 +1352,24 @@ func startWebServer(initialData interface{}, openBrowser bool, baseURL, username
 		platform := r.URL.Query().Get("platform")
 
 		ctx := r.Context()
-		kubeconfigPath := filepath.Join(os.Getenv("HOME"), ".kube", "config")
+
+		if configSources != nil {
+			sources := configSources
+			if platform != "all" {
+				sources = platformSources(platform, opts)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(snapshots.CollectSources(ctx, sources))
+			return
+		}
 
 		var data map[string]interface{}
 		var err error
 
 		if platform == "all" {
-			data, err = snapshots.CollectAllSnapshots(ctx, kubeconfigPath)
+			data, err = snapshots.CollectAllSnapshots(ctx, opts)
 		} else {
-			data, err = snapshots.CollectPlatformSnapshots(ctx, platform, kubeconfigPath)
+			data, err = snapshots.CollectPlatformSnapshots(ctx, platform, opts)
 		}
 
 		if err != nil {
@@ -1158,6 +1383,9 @@ func startWebServer(initialData interface{}, openBrowser bool, baseURL, username
 	})
 
 	http.HandleFunc("/api/gcp/connect", func(w http.ResponseWriter, r *http.Request) {
+		if collectingDisabled(w) {
+			return
+		}
 		if r.Method != http.MethodPost {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			return
@@ -1206,6 +1434,7 @@ func startWebServer(initialData interface{}, openBrowser bool, baseURL, username
 		}
 
 		ctx := r.Context()
+		startedAt := time.Now().UTC()
 		hasCredentials, err := gcp.CheckCredentials(ctx)
 		if err != nil || !hasCredentials {
 			if tempKeyFile != "" {
@@ -1226,9 +1455,7 @@ func startWebServer(initialData interface{}, openBrowser bool, baseURL, username
 			return
 		}
 
-		dataMutex.Lock()
-		data = gcpData
-		dataMutex.Unlock()
+		setSource(ctx, "gcp", opts, gcpData, startedAt)
 
 		if tempKeyFile != "" {
 			os.Remove(tempKeyFile)
@@ -1273,6 +1500,9 @@ func startWebServer(initialData interface{}, openBrowser bool, baseURL, username
 	})
 
 	http.HandleFunc("/api/docker/connect", func(w http.ResponseWriter, r *http.Request) {
+		if collectingDisabled(w) {
+			return
+		}
 		if r.Method != http.MethodPost {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			return
@@ -1288,6 +1518,7 @@ func startWebServer(initialData interface{}, openBrowser bool, baseURL, username
 		}
 
 		ctx := r.Context()
+		startedAt := time.Now().UTC()
 		hasCredentials, err := docker.CheckCredentials(ctx, params.Host)
 		if err != nil || !hasCredentials {
 			http.Error(w, fmt.Sprintf("Error connecting to Docker: %v", err), http.StatusBadRequest)
@@ -1300,9 +1531,9 @@ func startWebServer(initialData interface{}, openBrowser bool, baseURL, username
 			return
 		}
 
-		dataMutex.Lock()
-		data = dockerData
-		dataMutex.Unlock()
+		dockerOpts := opts
+		dockerOpts.DockerHost = params.Host
+		setSource(ctx, "docker", dockerOpts, dockerData, startedAt)
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]string{
@@ -1311,24 +1542,28 @@ func startWebServer(initialData interface{}, openBrowser bool, baseURL, username
 		})
 	})
 
-	log.Println("Server starting on port http://localhost:8080")
+	url := "http://" + addr
+	if strings.HasPrefix(addr, ":") {
+		url = "http://localhost" + addr
+	}
+	log.Printf("Server starting on port %s", url)
 	if openBrowser {
 		go func() {
 			var err error
 			switch runtime.GOOS {
 			case "darwin":
-				err = exec.Command("open", "http://localhost:8080").Start()
+				err = exec.Command("open", url).Start()
 			case "windows":
-				err = exec.Command("rundll32", "url.dll,FileProtocolHandler", "http://localhost:8080").Start()
+				err = exec.Command("rundll32", "url.dll,FileProtocolHandler", url).Start()
 			default:
-				err = exec.Command("xdg-open", "http://localhost:8080").Start()
+				err = exec.Command("xdg-open", url).Start()
 			}
 			if err != nil {
 				log.Printf("Warning: Failed to open browser: %v", err)
 			}
 		}()
 	}
-	err = http.ListenAndServe(":8080", nil)
+	err = http.ListenAndServe(addr, nil)
 	if err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/michaelcade/kollect/pkg/collector"
+	"github.com/michaelcade/kollect/pkg/config"
+	"github.com/michaelcade/kollect/pkg/history"
+	"github.com/michaelcade/kollect/pkg/inventory"
+	"github.com/michaelcade/kollect/pkg/scheduler"
+)
+
+// scheduleFlags collects repeated --schedule values.
+type scheduleFlags []string
+
+func (s *scheduleFlags) String() string {
+	return strings.Join(*s, " ")
+}
+
+func (s *scheduleFlags) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+func runServe(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	opts := collector.Options{VeeamIgnoreSSL: true}
+	addCollectorFlags(flags, &opts)
+	addr := flags.String("addr", defaultAddr, "Address for the web interface and API to listen on")
+	historyPath := flags.String("history", "", "Append each collection to this history database (e.g. "+defaultHistoryPath+")")
+	configPath := flags.String("config", "", "Configuration file describing named sources and their schedules")
+	var schedules scheduleFlags
+	flags.Var(&schedules, "schedule", "Source and cron schedule as source=spec, e.g. aws=@every 1h or kubernetes=*/15 * * * * (repeatable, overrides the config file)")
+	flags.Usage = func() {
+		fmt.Println("Usage: kollect serve [--config kollect.yaml] [--schedule source=spec ...] [flags]")
+		fmt.Println("Flags:")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if len(schedules) == 0 && *configPath == "" {
+		fmt.Println("Error: serve requires --config or at least one --schedule")
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	var cfg *config.Config
+	if *configPath != "" {
+		if err := checkConfigFlags(flags); err != nil {
+			log.Fatalf("%v", err)
+		}
+		var err error
+		cfg, err = config.Load(*configPath)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		configSources, err = config.Collectors(cfg.Sources)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+	}
+
+	if *historyPath != "" {
+		store, err := history.Open(*historyPath)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		historyStore = store
+	}
+
+	var sched *scheduler.Scheduler
+	sched = scheduler.New(func(run *inventory.Document) {
+		recordHistory(run)
+
+		dataMutex.Lock()
+		data = sched.Document()
+		dataMutex.Unlock()
+	})
+
+	sources, specs, err := scheduledSources(cfg, schedules, opts)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	if len(sources) == 0 {
+		log.Fatalf("No sources are scheduled")
+	}
+	for _, src := range sources {
+		if err := sched.Add(src, specs[src.Name]); err != nil {
+			log.Fatalf("%v", err)
+		}
+	}
+
+	http.HandleFunc("/api/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(sched.Status()); err != nil {
+			log.Printf("Error encoding status: %v", err)
+		}
+	})
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-stop
+		log.Println("Shutting down, waiting for running collections to finish...")
+		sched.Stop()
+		if historyStore != nil {
+			historyStore.Close()
+		}
+		os.Exit(0)
+	}()
+
+	dataMutex.Lock()
+	data = sched.Document()
+	dataMutex.Unlock()
+
+	activeScheduler = sched
+	log.Printf("Scheduling %s", strings.Join(sched.Names(), ", "))
+	sched.Start()
+	startWebServer(nil, false, opts, *addr)
+}
+
+// scheduledSources returns every source to schedule with its cron spec.
+// Sources come from the config file when one is given, otherwise --schedule
+// names platforms collected with the command line options. A --schedule
+// entry overrides the schedule of the config sources it matches.
+func scheduledSources(cfg *config.Config, schedules []string, opts collector.Options) ([]collector.Source, map[string]string, error) {
+	specs := map[string]string{}
+	if cfg != nil {
+		for _, src := range cfg.Sources {
+			if src.Schedule != "" {
+				specs[src.Name] = src.Schedule
+			}
+		}
+	}
+
+	var sources []collector.Source
+	for _, schedule := range schedules {
+		value, spec, ok := strings.Cut(schedule, "=")
+		spec = strings.TrimSpace(spec)
+		if !ok || spec == "" {
+			return nil, nil, fmt.Errorf("invalid schedule %q, expected source=spec", schedule)
+		}
+
+		if cfg != nil {
+			matched, err := cfg.Select(value)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, src := range matched {
+				specs[src.Name] = spec
+			}
+			continue
+		}
+
+		names, err := collector.ParseNames(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%v (available: %s, all)", err, strings.Join(collector.Names(), ", "))
+		}
+		for _, name := range names {
+			if _, exists := specs[name]; !exists {
+				sources = append(sources, collector.Source{Name: name, Platform: name, Options: opts})
+			}
+			specs[name] = spec
+		}
+	}
+
+	if cfg == nil {
+		return sources, specs, nil
+	}
+
+	var selected []config.Source
+	for _, src := range cfg.Sources {
+		if _, ok := specs[src.Name]; !ok {
+			log.Printf("Warning: Source %s has no schedule and will not be collected", src.Name)
+			continue
+		}
+		selected = append(selected, src)
+	}
+	resolved, err := config.Collectors(selected)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resolved, specs, nil
+}
@@ -2,35 +2,138 @@
 
 registerDataHandler('aws', 
     function(data) {
+        // Azure exports also have Subnets and LoadBalancers.
+        if (data.ResourceGroups || data.VirtualNetworks || data.AzureVirtualNetworks) {
+            return false;
+        }
         return data.EC2Instances || data.S3Buckets || data.RDSInstances || 
-               data.DynamoDBTables || data.VPCs;
+               data.DynamoDBTables || data.VPCs || data.Subnets || data.SecurityGroups || data.LoadBalancers || data.EBSVolumes || data.EKSClusters || data.ECSClusters ||
+               data.LambdaFunctions || data.Errors;
     },
     function(data) {
         console.log("Processing AWS data");
         
+        const multiAccount = data.Accounts && data.Accounts.length > 1;
+        const withAccount = (template, headers) => multiAccount
+            ? [item => awsAccountCell(item) + template(item), ['Account', ...headers]]
+            : [template, headers];
+
+        if (multiAccount) {
+            createTable('Accounts', data.Accounts, awsAccountRowTemplate,
+                ['Account ID', 'Alias', 'Profile', 'Role', 'Status']);
+        }
+
         if (data.EC2Instances) {
-            createTable('EC2 Instances', data.EC2Instances, ec2InstanceRowTemplate, 
-                ['Name', 'Instance ID', 'Type', 'State', 'Region']);
+            createTable('EC2 Instances', data.EC2Instances, ...withAccount(ec2InstanceRowTemplate, 
+                ['Name', 'Instance ID', 'Type', 'State', 'Region', 'Private IP', 'Public IP', 'VPC / Subnet', 'AMI', 'Platform', 'Lifecycle', 'Launched', 'Volumes', 'Tags']));
+        }
+        
+        if (data.EBSVolumes) {
+            createTable('EBS Volumes', data.EBSVolumes, ...withAccount(ebsVolumeRowTemplate,
+                ['Name', 'Volume ID', 'Size', 'Type', 'IOPS', 'Throughput', 'Encrypted', 'State', 'Attached To', 'Availability Zone', 'Created']));
+        }
+        
+        if (data.OrphanedSnapshots && data.OrphanedSnapshots.length > 0) {
+            createTable('Orphaned EBS Snapshots', data.OrphanedSnapshots, ...withAccount(orphanedSnapshotRowTemplate,
+                ['Snapshot ID', 'Deleted Volume', 'Size', 'Started', 'Region', 'Description']));
         }
         
         if (data.S3Buckets) {
-            createTable('S3 Buckets', data.S3Buckets, s3BucketRowTemplate, 
-                ['Bucket Name', 'Immutable', 'Region']);
+            createTable('S3 Buckets', data.S3Buckets, ...withAccount(s3BucketRowTemplate, 
+                ['Bucket Name', 'Region', 'Size', 'Objects', 'Versioning', 'Object Lock', 'Encryption', 'Public Access', 'Lifecycle', 'Replication']));
         }
         
         if (data.RDSInstances) {
-            createTable('RDS Instances', data.RDSInstances, rdsInstanceRowTemplate, 
-                ['Instance ID', 'Engine', 'Status', 'Region']);
+            createTable('RDS Instances', data.RDSInstances, ...withAccount(rdsInstanceRowTemplate, 
+                ['Instance ID', 'Engine', 'Status', 'Region']));
         }
         
         if (data.DynamoDBTables) {
-            createTable('DynamoDB Tables', data.DynamoDBTables, dynamoDBTableRowTemplate, 
-                ['Table Name', 'Status', 'Region']);
+            createTable('DynamoDB Tables', data.DynamoDBTables, ...withAccount(dynamoDBTableRowTemplate, 
+                ['Table Name', 'Status', 'Region']));
         }
         
         if (data.VPCs) {
-            createTable('VPCs', data.VPCs, vpcRowTemplate, 
-                ['VPC ID', 'State', 'Region']);
+            createTable('VPCs', data.VPCs, ...withAccount(vpcRowTemplate, 
+                ['VPC ID', 'Name', 'CIDR', 'Default', 'State', 'Region']));
+        }
+        
+        if (data.Subnets) {
+            createTable('Subnets', data.Subnets, ...withAccount(subnetRowTemplate,
+                ['Subnet ID', 'Name', 'VPC', 'CIDR', 'Availability Zone', 'Free IPs', 'Public IP on Launch']));
+        }
+        
+        if (data.RouteTables) {
+            createTable('Route Tables', data.RouteTables, ...withAccount(routeTableRowTemplate,
+                ['Route Table ID', 'Name', 'VPC', 'Main', 'Subnets', 'Routes']));
+        }
+        
+        if (data.InternetGateways) {
+            createTable('Internet Gateways', data.InternetGateways, ...withAccount(internetGatewayRowTemplate,
+                ['Gateway ID', 'Name', 'VPC', 'State', 'Region']));
+        }
+        
+        if (data.NATGateways) {
+            createTable('NAT Gateways', data.NATGateways, ...withAccount(natGatewayRowTemplate,
+                ['Gateway ID', 'Name', 'VPC', 'Subnet', 'State', 'Connectivity', 'Public IP', 'Private IP']));
+        }
+        
+        if (data.ElasticIPs) {
+            createTable('Elastic IPs', data.ElasticIPs, ...withAccount(elasticIPRowTemplate,
+                ['Public IP', 'Name', 'Allocation ID', 'Associated With', 'Private IP', 'Region']));
+        }
+        
+        if (data.SecurityGroups) {
+            createTable('Security Groups', data.SecurityGroups, ...withAccount(securityGroupRowTemplate,
+                ['Group ID', 'Name', 'VPC', 'Inbound Rules', 'Outbound Rules', 'Region']));
+        }
+        
+        if (data.LoadBalancers) {
+            createTable('Load Balancers', data.LoadBalancers, ...withAccount(loadBalancerRowTemplate,
+                ['Name', 'Type', 'Scheme', 'State', 'VPC', 'Listeners', 'Targets', 'DNS Name', 'Region']));
+        }
+        
+        if (data.EKSClusters) {
+            createTable('EKS Clusters', data.EKSClusters, ...withAccount(eksClusterRowTemplate,
+                ['Name', 'Version', 'Platform Version', 'Status', 'VPC', 'Region', 'Created']));
+        }
+        
+        if (data.EKSNodeGroups) {
+            createTable('EKS Node Groups', data.EKSNodeGroups, ...withAccount(eksNodeGroupRowTemplate,
+                ['Node Group', 'Cluster', 'Status', 'Version', 'Instance Types', 'Capacity', 'AMI Type', 'Desired / Min / Max', 'Region']));
+        }
+        
+        if (data.ECSClusters) {
+            createTable('ECS Clusters', data.ECSClusters, ...withAccount(ecsClusterRowTemplate,
+                ['Name', 'Status', 'Services', 'Running Tasks', 'Pending Tasks', 'Container Instances', 'Capacity Providers', 'Region']));
+        }
+        
+        if (data.ECSServices) {
+            createTable('ECS Services', data.ECSServices, ...withAccount(ecsServiceRowTemplate,
+                ['Service', 'Cluster', 'Status', 'Launch Type', 'Task Definition', 'Desired / Running / Pending', 'Region']));
+        }
+        
+        if (data.ECSTasks) {
+            createTable('ECS Tasks', data.ECSTasks, ...withAccount(ecsTaskRowTemplate,
+                ['Task ID', 'Cluster', 'Group', 'Task Definition', 'Status', 'Launch Type', 'CPU / Memory', 'Started', 'Region']));
+        }
+        
+        if (data.LambdaFunctions) {
+            createTable('Lambda Functions', data.LambdaFunctions, ...withAccount(lambdaFunctionRowTemplate,
+                ['Function', 'Runtime', 'Memory', 'Timeout', 'Architectures', 'Package', 'Code Size', 'Last Modified', 'Region']));
+        }
+        
+        if (data.Regions) {
+            const regions = data.Regions.filter(region =>
+                region.EC2Instances + region.S3Buckets + region.RDSInstances + region.DynamoDBTables + region.VPCs +
+                (region.EBSVolumes || 0) + (region.EKSClusters || 0) + (region.ECSClusters || 0) + (region.LambdaFunctions || 0) > 0);
+            createTable('Regions', regions, ...withAccount(awsRegionRowTemplate,
+                ['Region', 'EC2 Instances', 'S3 Buckets', 'RDS Instances', 'DynamoDB Tables', 'VPCs', 'EBS Volumes', 'EKS Clusters', 'ECS Clusters', 'Lambda Functions']));
+        }
+        
+        if (data.Errors && data.Errors.length > 0) {
+            createTable('Collection Errors', data.Errors, ...withAccount(awsErrorRowTemplate,
+                ['Region', 'Service', 'Error']));
         }
         
         setTimeout(() => {
@@ -39,12 +142,118 @@ registerDataHandler('aws',
     }
 );
 
+function awsAccountCell(item) {
+    const alias = item.AccountAlias ? ` (${item.AccountAlias})` : '';
+    return `<td>${item.AccountID || ''}${alias}</td>`;
+}
+
+function awsAccountRowTemplate(item) {
+    const status = item.Error ? `Failed: ${item.Error}` : 'Collected';
+    return `<td>${item.AccountID}</td><td>${item.AccountAlias || ''}</td><td>${item.Profile || ''}</td><td>${item.Role || ''}</td><td>${status}</td>`;
+}
+
+function awsRegionRowTemplate(item) {
+    return `<td>${item.Region}</td><td>${item.EC2Instances}</td><td>${item.S3Buckets}</td><td>${item.RDSInstances}</td><td>${item.DynamoDBTables}</td><td>${item.VPCs}</td><td>${item.EBSVolumes || 0}</td>` +
+        `<td>${item.EKSClusters || 0}</td><td>${item.ECSClusters || 0}</td><td>${item.LambdaFunctions || 0}</td>`;
+}
+
+function awsErrorRowTemplate(item) {
+    return `<td>${item.Region}</td><td>${item.Service}</td><td>${item.Error}</td>`;
+}
+
 function ec2InstanceRowTemplate(item) {
-    return `<td>${item.Name}</td><td>${item.InstanceID}</td><td>${item.Type}</td><td>${item.State}</td><td>${item.Region}</td>`;
+    const volumes = item.Volumes || [];
+    const totalSize = volumes.reduce((sum, volume) => sum + (volume.SizeGiB || 0), 0);
+    const volumeTitle = volumes.map(volume => `${volume.VolumeID} ${volume.DeviceName || ''} ${volume.SizeGiB || '?'} GiB ${volume.VolumeType || ''}`).join('\n');
+    const volumeSummary = volumes.length > 0 ? `<span title="${volumeTitle}">${volumes.length} (${totalSize} GiB)</span>` : '';
+    const tags = Object.entries(item.Tags || {})
+        .filter(([key]) => key !== 'Name')
+        .map(([key, value]) => `${key}=${value}`)
+        .join(', ');
+    const network = [item.VPCID, item.SubnetID].filter(Boolean).join(' / ');
+    const launched = item.LaunchTime ? new Date(item.LaunchTime).toLocaleString() : '';
+
+    return `<td>${item.Name || ''}</td><td>${item.InstanceID}</td><td>${item.Type}</td><td>${item.State}</td><td>${item.Region}</td>` +
+        `<td>${item.PrivateIP || ''}</td><td>${item.PublicIP || ''}</td><td>${network}</td><td>${item.ImageID || ''}</td>` +
+        `<td>${item.Platform || ''}</td><td>${item.Lifecycle || ''}</td><td>${launched}</td><td>${volumeSummary}</td><td>${tags}</td>`;
+}
+
+function ebsVolumeRowTemplate(item) {
+    const attachedTo = item.Unattached
+        ? '<span class="orphaned-badge">unattached</span>'
+        : (item.AttachedTo || []).join(', ');
+    const throughput = item.ThroughputMiBps ? `${item.ThroughputMiBps} MiB/s` : '';
+    const created = item.CreateTime ? new Date(item.CreateTime).toLocaleString() : '';
+
+    return `<td>${item.Name || ''}</td><td>${item.VolumeID}</td><td>${item.SizeGiB} GiB</td><td>${item.VolumeType}</td>` +
+        `<td>${item.IOPS || ''}</td><td>${throughput}</td><td>${item.Encrypted}</td><td>${item.State}</td>` +
+        `<td>${attachedTo}</td><td>${item.AvailabilityZone}</td><td>${created}</td>`;
+}
+
+function orphanedSnapshotRowTemplate(item) {
+    const started = item.StartTime ? new Date(item.StartTime).toLocaleString() : '';
+    return `<td>${item.SnapshotID}</td><td>${item.VolumeID}</td><td>${item.SizeGiB} GiB</td><td>${started}</td><td>${item.Region}</td><td>${item.Description || ''}</td>`;
+}
+
+function eksClusterRowTemplate(item) {
+    const created = item.CreatedAt ? new Date(item.CreatedAt).toLocaleString() : '';
+    return `<td>${item.Name}</td><td>${item.Version}</td><td>${item.PlatformVersion || ''}</td><td>${item.Status}</td>` +
+        `<td>${item.VPCID || ''}</td><td>${item.Region}</td><td>${created}</td>`;
+}
+
+function eksNodeGroupRowTemplate(item) {
+    return `<td>${item.Name}</td><td>${item.Cluster}</td><td>${item.Status}</td><td>${item.Version || ''}</td>` +
+        `<td>${(item.InstanceTypes || []).join(', ')}</td><td>${item.CapacityType || ''}</td><td>${item.AMIType || ''}</td>` +
+        `<td>${item.DesiredSize} / ${item.MinSize} / ${item.MaxSize}</td><td>${item.Region}</td>`;
+}
+
+function ecsClusterRowTemplate(item) {
+    return `<td>${item.Name}</td><td>${item.Status}</td><td>${item.ActiveServices}</td><td>${item.RunningTasks}</td>` +
+        `<td>${item.PendingTasks}</td><td>${item.RegisteredContainerInstances}</td>` +
+        `<td>${(item.CapacityProviders || []).join(', ')}</td><td>${item.Region}</td>`;
+}
+
+function ecsServiceRowTemplate(item) {
+    return `<td>${item.Name}</td><td>${item.Cluster}</td><td>${item.Status}</td><td>${item.LaunchType || ''}</td>` +
+        `<td>${item.TaskDefinition}</td><td>${item.DesiredCount} / ${item.RunningCount} / ${item.PendingCount}</td><td>${item.Region}</td>`;
+}
+
+function ecsTaskRowTemplate(item) {
+    const started = item.StartedAt ? new Date(item.StartedAt).toLocaleString() : '';
+    return `<td>${item.TaskID}</td><td>${item.Cluster}</td><td>${item.Group || ''}</td><td>${item.TaskDefinition}</td>` +
+        `<td>${item.LastStatus}</td><td>${item.LaunchType || ''}</td><td>${item.CPU || '?'} / ${item.Memory || '?'}</td>` +
+        `<td>${started}</td><td>${item.Region}</td>`;
+}
+
+function lambdaFunctionRowTemplate(item) {
+    const codeSize = item.CodeSizeBytes ? `${(item.CodeSizeBytes / 1024 / 1024).toFixed(1)} MiB` : '';
+    const modified = item.LastModified ? new Date(item.LastModified).toLocaleString() : '';
+    return `<td>${item.Name}</td><td>${item.Runtime || ''}</td><td>${item.MemoryMB} MB</td><td>${item.TimeoutSecs}s</td>` +
+        `<td>${(item.Architectures || []).join(', ')}</td><td>${item.PackageType || ''}</td><td>${codeSize}</td>` +
+        `<td>${modified}</td><td>${item.Region}</td>`;
 }
 
 function s3BucketRowTemplate(item) {
-    return `<td>${item.Name}</td><td>${item.Immutable}</td><td>${item.Region}</td>`;
+    const size = item.StorageClassBytes ? formatBytes(item.SizeBytes) : '';
+    const sizeTitle = Object.entries(item.StorageClassBytes || {})
+        .map(([storageType, bytes]) => `${storageType}: ${formatBytes(bytes)}`)
+        .join('\n');
+    let objectLock = item.Immutable ? 'Enabled' : 'Disabled';
+    if (item.ObjectLockMode) {
+        objectLock += ` (${item.ObjectLockMode}, ${item.ObjectLockRetention})`;
+    }
+    let publicAccess = 'Not blocked';
+    if (item.PublicAccessBlocked) {
+        publicAccess = 'Blocked';
+    } else if (item.PublicAccessBlock) {
+        publicAccess = 'Partially blocked';
+    }
+    const encryption = item.Encryption ? `${item.Encryption}${item.BucketKeyEnabled ? ' (bucket key)' : ''}` : '';
+
+    return `<td>${item.Name}</td><td>${item.Region || 'us-east-1'}</td><td title="${sizeTitle}">${size}</td>` +
+        `<td>${item.StorageClassBytes ? (item.ObjectCount || 0).toLocaleString() : ''}</td><td>${item.Versioning || ''}${item.MFADelete ? ' (MFA delete)' : ''}</td>` +
+        `<td>${objectLock}</td><td title="${item.KMSKeyID || ''}">${encryption}</td><td>${publicAccess}</td>` +
+        `<td>${(item.LifecycleRules || []).join('<br>')}</td><td>${(item.Replication || []).join('<br>')}</td>`;
 }
 
 function rdsInstanceRowTemplate(item) {
@@ -56,7 +265,46 @@ function dynamoDBTableRowTemplate(item) {
 }
 
 function vpcRowTemplate(item) {
-    return `<td>${item.VPCID}</td><td>${item.State}</td><td>${item.Region}</td>`;
+    return `<td>${item.VPCID}</td><td>${item.Name || ''}</td><td>${item.CIDRBlock || ''}</td><td>${item.IsDefault ? 'Yes' : 'No'}</td>` +
+        `<td>${item.State}</td><td>${item.Region}</td>`;
+}
+
+function subnetRowTemplate(item) {
+    return `<td>${item.SubnetID}</td><td>${item.Name || ''}</td><td>${item.VPCID}</td><td>${item.CIDRBlock}</td>` +
+        `<td>${item.AvailabilityZone}</td><td>${item.AvailableIPs}</td><td>${item.MapPublicIP ? 'Yes' : 'No'}</td>`;
+}
+
+function routeTableRowTemplate(item) {
+    return `<td>${item.RouteTableID}</td><td>${item.Name || ''}</td><td>${item.VPCID}</td><td>${item.Main ? 'Yes' : 'No'}</td>` +
+        `<td>${(item.Subnets || []).join('<br>')}</td><td>${(item.Routes || []).join('<br>')}</td>`;
+}
+
+function internetGatewayRowTemplate(item) {
+    return `<td>${item.GatewayID}</td><td>${item.Name || ''}</td><td>${item.VPCID || ''}</td><td>${item.State}</td><td>${item.Region}</td>`;
+}
+
+function natGatewayRowTemplate(item) {
+    return `<td>${item.GatewayID}</td><td>${item.Name || ''}</td><td>${item.VPCID}</td><td>${item.SubnetID}</td><td>${item.State}</td>` +
+        `<td>${item.ConnectivityType}</td><td>${item.PublicIP || ''}</td><td>${item.PrivateIP || ''}</td>`;
+}
+
+function elasticIPRowTemplate(item) {
+    const associatedWith = item.Unassociated
+        ? '<span class="orphaned-badge">unassociated</span>'
+        : (item.InstanceID || item.NetworkInterfaceID || '');
+    return `<td>${item.PublicIP}</td><td>${item.Name || ''}</td><td>${item.AllocationID}</td><td>${associatedWith}</td>` +
+        `<td>${item.PrivateIP || ''}</td><td>${item.Region}</td>`;
+}
+
+function securityGroupRowTemplate(item) {
+    const name = item.OpenToWorld ? `${item.GroupName} <span class="orphaned-badge">open to internet</span>` : item.GroupName;
+    return `<td title="${item.Description || ''}">${item.GroupID}</td><td>${name}</td><td>${item.VPCID || ''}</td>` +
+        `<td>${(item.InboundRules || []).join('<br>')}</td><td>${(item.OutboundRules || []).join('<br>')}</td><td>${item.Region}</td>`;
+}
+
+function loadBalancerRowTemplate(item) {
+    return `<td>${item.Name}</td><td>${item.Type}</td><td>${item.Scheme || ''}</td><td>${item.State || ''}</td><td>${item.VPCID || ''}</td>` +
+        `<td>${(item.Listeners || []).join(', ')}</td><td>${(item.Targets || []).join('<br>')}</td><td>${item.DNSName}</td><td>${item.Region}</td>`;
 }
 
 document.addEventListener('DOMContentLoaded', function() {
@@ -138,10 +386,16 @@ function showAWSCredentialsModal() {
                 <div id="aws-default-config-form" class="source-form" style="margin-top: 12px; margin-left: 25px; padding: 10px; background: rgba(255,255,255,0.05); border-radius: 4px;">
                     <p style="margin-top: 0;">This option uses credentials from your AWS CLI configuration at <code>~/.aws/credentials</code> and <code>~/.aws/config</code>.</p>
                     <div class="form-group" style="margin-top: 15px;">
-                        <label for="aws-profile-selector" style="font-weight: bold; margin-bottom: 5px;">Select AWS Profile:</label>
-                        <select id="aws-profile-selector" style="width: 100%; padding: 8px; background: var(--input-bg-color); color: var(--text-color); border: 1px solid var(--border-color); border-radius: 4px; box-sizing: border-box;">
+                        <label for="aws-profile-selector" style="font-weight: bold; margin-bottom: 5px;">Select AWS Profiles:</label>
+                        <select id="aws-profile-selector" multiple size="4" style="width: 100%; padding: 8px; background: var(--input-bg-color); color: var(--text-color); border: 1px solid var(--border-color); border-radius: 4px; box-sizing: border-box;">
                             <option value="default">default</option>
                         </select>
+                        <p class="tip" style="margin-top: 5px; font-size: 0.85em; color: var(--secondary-text-color); font-style: italic;">Hold Ctrl or Cmd to collect several accounts at once.</p>
+                    </div>
+                    <div class="form-group" style="margin-top: 15px;">
+                        <label for="aws-role" style="font-weight: bold; margin-bottom: 5px;">Organization Role (optional):</label>
+                        <input type="text" id="aws-role" style="width: 100%; padding: 8px; background: var(--input-bg-color); color: var(--text-color); border: 1px solid var(--border-color); border-radius: 4px; box-sizing: border-box;" placeholder="OrganizationAccountAccessRole">
+                        <p class="tip" style="margin-top: 5px; font-size: 0.85em; color: var(--secondary-text-color); font-style: italic;">Assumed into every member account of the AWS Organization of the first profile.</p>
                     </div>
                 </div>
             </div>
@@ -209,9 +463,11 @@ function showAWSCredentialsModal() {
         console.log(`Selected source: ${configSource}`);
         
         if (configSource === 'default') {
-            const profile = document.getElementById('aws-profile-selector').value;
-            console.log(`Using AWS profile: ${profile}`);
-            connectToAWS({ type: 'profile', profile: profile });
+            const profiles = Array.from(document.getElementById('aws-profile-selector').selectedOptions)
+                .map(option => option.value);
+            const role = document.getElementById('aws-role').value.trim();
+            console.log(`Using AWS profiles: ${profiles.join(', ')}`);
+            connectToAWS({ type: 'profile', profiles: profiles, role: role });
         } else {
             const accessKey = document.getElementById('aws-access-key').value;
             const secretKey = document.getElementById('aws-secret-key').value;
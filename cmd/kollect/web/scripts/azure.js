@@ -2,56 +2,179 @@
 
 registerDataHandler('azure', 
     function(data) {
-        return data.AzureVMs || data.AzureResourceGroups || data.AzureStorageAccounts ||
+        return data.ResourceGroups || data.StorageAccounts || data.AKSClusters ||
+               data.AzureVMs || data.AzureResourceGroups || data.AzureStorageAccounts ||
                data.AzureBlobContainers || data.AzureVirtualNetworks || 
-               data.AzureSQLDatabases || data.AzureCosmosDBs;
+               data.AzureSQLDatabases || data.AzureCosmosDBs || data.Resources;
     },
     function(data) {
         console.log("Processing Azure data");
         
-        if (data.AzureResourceGroups) {
-            createTable('Azure Resource Groups', data.AzureResourceGroups, azureResourceGroupRowTemplate, 
-                ['Name', 'Location', 'Tags', 'Provisioning State']);
+        const multiSubscription = data.Subscriptions && data.Subscriptions.length > 1;
+        const withSubscription = (template, headers) => multiSubscription
+            ? [item => azureSubscriptionCell(item) + template(item), ['Subscription', ...headers]]
+            : [template, headers];
+
+        if (multiSubscription) {
+            createTable('Azure Subscriptions', data.Subscriptions, azureSubscriptionRowTemplate,
+                ['Subscription ID', 'Name', 'Tenant ID', 'Status']);
+        }
+
+        if (data.ResourceGroups) {
+            createTable('Azure Resource Groups', data.ResourceGroups, ...withSubscription(azureResourceGroupInfoRowTemplate,
+                ['Name', 'Location', 'Tags', 'Provisioning State']));
+        } else if (data.AzureResourceGroups) {
+            createTable('Azure Resource Groups', data.AzureResourceGroups, ...withSubscription(azureResourceGroupRowTemplate, 
+                ['Name', 'Location', 'Tags', 'Provisioning State']));
+        }
+        
+        if (data.VirtualMachines) {
+            createTable('Azure VMs', data.VirtualMachines, ...withSubscription(azureVMInfoRowTemplate,
+                ['Name', 'Resource Group', 'Location', 'Size', 'Power State', 'OS', 'Disks', 'IP Addresses', 'Backup', 'Tags']));
+        } else if (data.AzureVMs) {
+            createTable('Azure VMs', data.AzureVMs, ...withSubscription(azureVMRowTemplate, 
+                ['Name', 'Location', 'VM Size']));
+        }
+        
+        if (data.Disks) {
+            createTable('Azure Managed Disks', data.Disks, ...withSubscription(azureDiskRowTemplate,
+                ['Name', 'Resource Group', 'Location', 'SKU', 'Size', 'Tier', 'State', 'Attached To', 'Zone', 'Encryption', 'Tags']));
+        }
+        
+        if (data.OrphanedSnapshots && data.OrphanedSnapshots.length > 0) {
+            createTable('Orphaned Azure Disk Snapshots', data.OrphanedSnapshots, ...withSubscription(azureOrphanedSnapshotRowTemplate,
+                ['Name', 'Resource Group', 'Deleted Disk', 'Size', 'Incremental', 'Created', 'Location']));
+        }
+        
+        if (data.ScaleSets) {
+            createTable('Azure VM Scale Sets', data.ScaleSets, ...withSubscription(azureScaleSetInfoRowTemplate,
+                ['Name', 'Resource Group', 'Location', 'SKU', 'Capacity', 'Orchestration']));
+        } else if (data.AzureVMSS) {
+            createTable('Azure VM Scale Sets', data.AzureVMSS, ...withSubscription(azureVMSSRowTemplate, 
+                ['Name', 'Location', 'Capacity']));
+        }
+        
+        if (data.AKSClusters) {
+            createTable('Azure AKS Clusters', data.AKSClusters, ...withSubscription(azureAKSClusterInfoRowTemplate,
+                ['Name', 'Resource Group', 'Location', 'K8s Version', 'Power State', 'Node Pools', 'Node Count']));
+        } else if (data.AzureAKSClusters) {
+            createTable('Azure AKS Clusters', data.AzureAKSClusters, ...withSubscription(azureAKSClusterRowTemplate, 
+                ['Name', 'Location', 'K8s Version', 'Node Count']));
+        }
+        
+        if (data.StorageAccounts) {
+            createTable('Azure Storage Accounts', data.StorageAccounts, ...withSubscription(azureStorageAccountInfoRowTemplate,
+                ['Name', 'Resource Group', 'Location', 'Kind', 'SKU', 'Access Tier', 'HTTPS Only', 'File Share Backups']));
+        } else if (data.AzureStorageAccounts) {
+            createTable('Azure Storage Accounts', data.AzureStorageAccounts, ...withSubscription(azureStorageAccountRowTemplate, 
+                ['Name', 'Location', 'Kind']));
+        }
+        
+        if (data.BlobContainers) {
+            createTable('Azure Blob Containers', data.BlobContainers, ...withSubscription(azureBlobContainerInfoRowTemplate,
+                ['Name', 'Storage Account', 'Public Access', 'Immutable', 'Legal Hold']));
+        } else if (data.AzureBlobContainers) {
+            createTable('Azure Blob Containers', data.AzureBlobContainers, ...withSubscription(azureBlobContainerRowTemplate, 
+                ['Name', 'Immutable', 'ID']));
+        }
+        
+        if (data.VirtualNetworks) {
+            createTable('Azure Virtual Networks', data.VirtualNetworks, ...withSubscription(azureVirtualNetworkInfoRowTemplate,
+                ['Name', 'Resource Group', 'Location', 'Address Space', 'Subnets']));
+        } else if (data.AzureVirtualNetworks) {
+            createTable('Azure Virtual Networks', data.AzureVirtualNetworks, ...withSubscription(azureVirtualNetworkRowTemplate, 
+                ['Name', 'Location']));
+        }
+
+        if (data.Subnets) {
+            createTable('Azure Subnets', data.Subnets, ...withSubscription(azureSubnetRowTemplate,
+                ['Name', 'Virtual Network', 'Resource Group', 'Address Prefixes', 'NSG', 'Route Table', 'NAT Gateway', 'Delegations', 'NICs', 'Private Endpoints']));
+        }
+
+        if (data.VNetPeerings) {
+            createTable('Azure VNet Peerings', data.VNetPeerings, ...withSubscription(azureVNetPeeringRowTemplate,
+                ['Name', 'Virtual Network', 'Remote Virtual Network', 'Remote Address Space', 'State', 'Forwarded Traffic', 'Gateway Transit', 'Remote Gateways']));
+        }
+
+        if (data.NetworkInterfaces) {
+            createTable('Azure Network Interfaces', data.NetworkInterfaces, ...withSubscription(azureNetworkInterfaceRowTemplate,
+                ['Name', 'Resource Group', 'Location', 'Attached To', 'Private IPs', 'Public IPs', 'Subnets', 'NSG', 'Accelerated Networking']));
+        }
+
+        if (data.NetworkSecurityGroups) {
+            createTable('Azure Network Security Groups', data.NetworkSecurityGroups, ...withSubscription(azureNetworkSecurityGroupRowTemplate,
+                ['Name', 'Resource Group', 'Location', 'Inbound Rules', 'Outbound Rules', 'Subnets', 'NICs']));
+        }
+
+        if (data.PublicIPs) {
+            createTable('Azure Public IP Addresses', data.PublicIPs, ...withSubscription(azurePublicIPRowTemplate,
+                ['Name', 'Resource Group', 'Location', 'IP Address', 'SKU', 'Allocation', 'Version', 'FQDN', 'Attached To']));
+        }
+
+        if (data.LoadBalancers) {
+            createTable('Azure Load Balancers', data.LoadBalancers, ...withSubscription(azureLoadBalancerRowTemplate,
+                ['Name', 'Resource Group', 'Location', 'SKU', 'Frontends', 'Backend Pools', 'Rules', 'VMs']));
+        }
+
+        if (data.ApplicationGateways) {
+            createTable('Azure Application Gateways', data.ApplicationGateways, ...withSubscription(azureApplicationGatewayRowTemplate,
+                ['Name', 'Resource Group', 'Location', 'SKU', 'State', 'Frontends', 'Listeners', 'Backend Pools', 'WAF', 'VMs']));
+        }
+
+        if (data.PrivateEndpoints) {
+            createTable('Azure Private Endpoints', data.PrivateEndpoints, ...withSubscription(azurePrivateEndpointRowTemplate,
+                ['Name', 'Resource Group', 'Location', 'Subnet', 'Private IPs', 'Target', 'Sub-resources', 'Connection State']));
+        }
+        
+        if (data.SQLServers) {
+            createTable('Azure SQL Servers', data.SQLServers, ...withSubscription(azureSQLServerInfoRowTemplate,
+                ['Name', 'Resource Group', 'Location', 'Version', 'State', 'Public Network Access', 'Minimal TLS', 'Databases']));
         }
         
-        if (data.AzureVMs) {
-            createTable('Azure VMs', data.AzureVMs, azureVMRowTemplate, 
-                ['Name', 'Location', 'VM Size']);
+        if (data.SQLManagedInstances) {
+            createTable('Azure SQL Managed Instances', data.SQLManagedInstances, ...withSubscription(azureSQLManagedInstanceInfoRowTemplate,
+                ['Name', 'Resource Group', 'Location', 'SKU', 'vCores', 'Storage', 'License', 'State', 'Backup Redundancy', 'Databases']));
         }
         
-        if (data.AzureVMSS) {
-            createTable('Azure VM Scale Sets', data.AzureVMSS, azureVMSSRowTemplate, 
-                ['Name', 'Location', 'Capacity']);
+        if (data.SQLDatabases) {
+            createTable('Azure SQL Databases', data.SQLDatabases, ...withSubscription(azureSQLDatabaseInfoRowTemplate,
+                ['Name', 'Server', 'Resource Group', 'Location', 'SKU', 'Status', 'Max Size', 'Backup Redundancy', 'Long-Term Retention', 'Geo-Replicas', 'Vault Backup']));
+        } else if (data.AzureSQLDatabases) {
+            createTable('Azure SQL Databases', data.AzureSQLDatabases, ...withSubscription(azureSQLDatabaseRowTemplate, 
+                ['Name', 'Location']));
         }
         
-        if (data.AzureAKSClusters) {
-            createTable('Azure AKS Clusters', data.AzureAKSClusters, azureAKSClusterRowTemplate, 
-                ['Name', 'Location', 'K8s Version', 'Node Count']);
+        if (data.CosmosDBAccounts) {
+            createTable('Azure CosmosDB Accounts', data.CosmosDBAccounts, ...withSubscription(azureCosmosDBInfoRowTemplate,
+                ['Name', 'Resource Group', 'Location', 'Kind', 'Consistency', 'Locations']));
+        } else if (data.AzureCosmosDBs) {
+            createTable('Azure CosmosDB Accounts', data.AzureCosmosDBs, ...withSubscription(azureCosmosDBRowTemplate, 
+                ['Name', 'Location']));
         }
         
-        if (data.AzureStorageAccounts) {
-            createTable('Azure Storage Accounts', data.AzureStorageAccounts, azureStorageAccountRowTemplate, 
-                ['Name', 'Location', 'Kind']);
+        if (data.RecoveryServicesVaults) {
+            createTable('Azure Recovery Services Vaults', data.RecoveryServicesVaults, ...withSubscription(azureVaultRowTemplate,
+                ['Name', 'Resource Group', 'Location', 'Redundancy', 'Cross Region Restore', 'Soft Delete', 'Immutability', 'Protected Items']));
         }
         
-        if (data.AzureBlobContainers) {
-            createTable('Azure Blob Containers', data.AzureBlobContainers, azureBlobContainerRowTemplate, 
-                ['Name', 'Immutable', 'ID']);
+        if (data.BackupVaults) {
+            createTable('Azure Backup Vaults', data.BackupVaults, ...withSubscription(azureVaultRowTemplate,
+                ['Name', 'Resource Group', 'Location', 'Redundancy', 'Cross Region Restore', 'Soft Delete', 'Immutability', 'Protected Items']));
         }
         
-        if (data.AzureVirtualNetworks) {
-            createTable('Azure Virtual Networks', data.AzureVirtualNetworks, azureVirtualNetworkRowTemplate, 
-                ['Name', 'Location']);
+        if (data.BackupPolicies) {
+            createTable('Azure Backup Policies', data.BackupPolicies, ...withSubscription(azureBackupPolicyRowTemplate,
+                ['Name', 'Vault', 'Workload', 'Schedule', 'Retention', 'Protected Items']));
         }
         
-        if (data.AzureSQLDatabases) {
-            createTable('Azure SQL Databases', data.AzureSQLDatabases, azureSQLDatabaseRowTemplate, 
-                ['Name', 'Location']);
+        if (data.ProtectedItems) {
+            createTable('Azure Backup Protected Items', data.ProtectedItems, ...withSubscription(azureProtectedItemRowTemplate,
+                ['Name', 'Workload', 'Vault', 'Policy', 'Protection State', 'Health', 'Last Backup', 'Last Backup Time']));
         }
         
-        if (data.AzureCosmosDBs) {
-            createTable('Azure CosmosDB Accounts', data.AzureCosmosDBs, azureCosmosDBRowTemplate, 
-                ['Name', 'Location']);
+        if (data.Resources) {
+            createTable('Azure Resources', data.Resources, ...withSubscription(azureResourceRowTemplate,
+                ['Name', 'Type', 'Resource Group', 'Location', 'SKU', 'Tags']));
         }
         
         setTimeout(() => {
@@ -60,6 +183,221 @@ registerDataHandler('azure',
     }
 );
 
+function azureSubscriptionCell(item) {
+    const id = item.subscriptionId || item.SubscriptionID || '';
+    const name = item.subscriptionName || item.SubscriptionName;
+    return `<td>${id}${name ? ` (${name})` : ''}</td>`;
+}
+
+function azureSubscriptionRowTemplate(item) {
+    const status = item.Error ? `Failed: ${item.Error}`
+        : (item.DiskError ? `Collected, managed disks incomplete: ${item.DiskError}` : 'Collected');
+    return `<td>${item.SubscriptionID}</td><td>${item.SubscriptionName || ''}</td><td>${item.TenantID || ''}</td><td>${status}</td>`;
+}
+
+function azureTags(tags) {
+    return tags ? Object.entries(tags).map(([key, value]) => `${key}: ${value}`).join(', ') : '';
+}
+
+function azureResourceGroupInfoRowTemplate(item) {
+    return `<td title="${item.ID}">${item.Name}</td><td>${item.Location || ''}</td><td>${azureTags(item.Tags) || 'No tags'}</td>` +
+        `<td>${item.ProvisioningState || ''}</td>`;
+}
+
+function azureVMInfoRowTemplate(item) {
+    const disks = (item.Disks || []).map(disk =>
+        `${disk.Name}${disk.OSDisk ? ' (OS)' : ''}: ${disk.SizeGB} GB${disk.StorageType ? ` ${disk.StorageType}` : ''}`).join('<br>');
+    return `<td title="${item.ID}">${item.Name}</td><td>${item.ResourceGroup}</td><td>${item.Location || ''}</td>` +
+        `<td>${item.Size || 'N/A'}</td><td>${item.PowerState || 'unknown'}</td><td title="${item.Image || ''}">${item.OSName || item.OSType || ''}</td>` +
+        `<td>${disks}</td><td title="${azureVMNetworkTitle(item)}">${azureVMAddresses(item)}</td>` +
+        `<td>${[azureBackupCell(item.Backup), ...azureWorkloadBackups(item)].join('<br>')}</td><td>${azureTags(item.Tags)}</td>`;
+}
+
+function azureVMAddresses(item) {
+    return [...(item.PrivateIPs || []), ...(item.PublicIPs || []).map(ip => `${ip} (public)`)].join('<br>');
+}
+
+function azureVMNetworkTitle(item) {
+    const subnets = (item.Subnets || []).join(', ');
+    const nsgs = (item.NetworkSecurityGroups || []).join(', ');
+    return [subnets && `Subnets: ${subnets}`, nsgs && `NSGs: ${nsgs}`].filter(Boolean).join('; ');
+}
+
+function azureWorkloadBackups(item) {
+    return (item.WorkloadBackups || []).map(backup => `${backup.Item} (${backup.WorkloadType}): ${azureBackupCell(backup)}`);
+}
+
+function azureBackupCell(backup) {
+    if (!backup) {
+        return '<span class="orphaned-badge">not backed up</span>';
+    }
+    const status = backup.LastBackupStatus ? ` (${backup.LastBackupStatus})` : '';
+    return `<span title="${backup.Policy || ''}">${backup.Vault}${status}</span>`;
+}
+
+function azureDiskRowTemplate(item) {
+    const attachedTo = item.Unattached
+        ? '<span class="orphaned-badge">unattached</span>'
+        : (item.AttachedTo || []).join(', ');
+    const encryption = item.DiskEncryptionSet ? `${item.Encryption} (${item.DiskEncryptionSet})` : (item.Encryption || '');
+    return `<td title="${item.ID}">${item.Name}</td><td>${item.ResourceGroup}</td><td>${item.Location || ''}</td>` +
+        `<td>${item.SKU}</td><td>${item.SizeGB} GB</td><td>${item.Tier || ''}</td><td>${item.DiskState}</td><td>${attachedTo}</td>` +
+        `<td>${(item.Zones || []).join(', ')}</td><td>${encryption}</td><td>${azureTags(item.Tags)}</td>`;
+}
+
+function azureOrphanedSnapshotRowTemplate(item) {
+    const created = item.CreatedAt ? new Date(item.CreatedAt).toLocaleString() : '';
+    return `<td title="${item.ID}">${item.Name}</td><td>${item.ResourceGroup}</td><td title="${item.SourceDiskID}">${item.SourceDiskID.split('/').pop()}</td>` +
+        `<td>${item.SizeGB} GB</td><td>${item.Incremental}</td><td>${created}</td><td>${item.Location || ''}</td>`;
+}
+
+function azureScaleSetInfoRowTemplate(item) {
+    return `<td title="${item.ID}">${item.Name}</td><td>${item.ResourceGroup}</td><td>${item.Location || ''}</td>` +
+        `<td>${item.SKU || 'N/A'}</td><td>${item.Capacity}</td><td>${item.OrchestrationMode || ''}</td>`;
+}
+
+function azureAKSClusterInfoRowTemplate(item) {
+    const pools = (item.NodePools || []).map(pool => `${pool.Name} (${pool.Mode}): ${pool.Count} x ${pool.VMSize}`).join('<br>');
+    return `<td title="${item.ID}">${item.Name}</td><td>${item.ResourceGroup}</td><td>${item.Location || ''}</td>` +
+        `<td>${item.KubernetesVersion || 'N/A'}</td><td>${item.PowerState || ''}</td><td>${pools}</td><td>${item.NodeCount}</td>`;
+}
+
+function azureStorageAccountInfoRowTemplate(item) {
+    return `<td title="${item.ID}">${item.Name}</td><td>${item.ResourceGroup}</td><td>${item.Location || ''}</td>` +
+        `<td>${item.Kind}</td><td>${item.SKU}</td><td>${item.AccessTier || ''}</td><td>${item.HTTPSOnly}</td>` +
+        `<td>${(item.FileShareBackups || []).map(backup => `${backup.Item}: ${azureBackupCell(backup)}`).join('<br>')}</td>`;
+}
+
+function azureBlobContainerInfoRowTemplate(item) {
+    return `<td title="${item.ID}">${item.Name}</td><td>${item.StorageAccount}</td><td>${item.PublicAccess || 'None'}</td>` +
+        `<td>${item.ImmutableStorage || item.HasImmutabilityPolicy}</td><td>${item.HasLegalHold}</td>`;
+}
+
+function azureVirtualNetworkInfoRowTemplate(item) {
+    return `<td title="${item.ID}">${item.Name}</td><td>${item.ResourceGroup}</td><td>${item.Location || ''}</td>` +
+        `<td>${(item.AddressSpace || []).join(', ')}</td><td>${(item.Subnets || []).join(', ')}</td>`;
+}
+
+function azureResourceLink(id) {
+    return id ? `<span title="${id}">${id.split('/').pop()}</span>` : '';
+}
+
+function azureSubnetRowTemplate(item) {
+    return `<td title="${item.ID}">${item.Name}</td><td>${item.VirtualNetwork}</td><td>${item.ResourceGroup}</td>` +
+        `<td>${(item.AddressPrefixes || []).join(', ')}</td><td>${item.NetworkSecurityGroup || ''}</td><td>${item.RouteTable || ''}</td>` +
+        `<td>${item.NATGateway || ''}</td><td>${(item.Delegations || []).join(', ')}</td>` +
+        `<td>${(item.NetworkInterfaces || []).length}</td><td>${(item.PrivateEndpoints || []).length}</td>`;
+}
+
+function azureVNetPeeringRowTemplate(item) {
+    return `<td title="${item.ID}">${item.Name}</td><td>${item.VirtualNetwork}</td><td>${azureResourceLink(item.RemoteVirtualNetwork)}</td>` +
+        `<td>${(item.RemoteAddressSpace || []).join(', ')}</td><td>${item.State || ''}</td><td>${item.AllowForwardedTraffic}</td>` +
+        `<td>${item.AllowGatewayTransit}</td><td>${item.UseRemoteGateways}</td>`;
+}
+
+function azureNetworkInterfaceRowTemplate(item) {
+    const attachedTo = item.VirtualMachine || (item.PrivateEndpoint ? `${item.PrivateEndpoint} (private endpoint)` : '');
+    return `<td title="${item.ID}">${item.Name}</td><td>${item.ResourceGroup}</td><td>${item.Location || ''}</td><td>${attachedTo}</td>` +
+        `<td>${(item.PrivateIPs || []).join('<br>')}</td><td>${(item.PublicIPs || []).join('<br>')}</td>` +
+        `<td>${(item.Subnets || []).join('<br>')}</td><td>${item.NetworkSecurityGroup || ''}</td><td>${item.AcceleratedNetworking}</td>`;
+}
+
+function azureNetworkSecurityGroupRowTemplate(item) {
+    const name = item.OpenToInternet ? `${item.Name} <span class="orphaned-badge">open to internet</span>` : item.Name;
+    return `<td title="${item.ID}">${name}</td><td>${item.ResourceGroup}</td><td>${item.Location || ''}</td>` +
+        `<td>${(item.InboundRules || []).join('<br>')}</td><td>${(item.OutboundRules || []).join('<br>')}</td>` +
+        `<td>${(item.Subnets || []).join('<br>')}</td><td>${(item.NetworkInterfaces || []).map(azureResourceLink).join('<br>')}</td>`;
+}
+
+function azurePublicIPRowTemplate(item) {
+    const attachedTo = item.Unassociated
+        ? '<span class="orphaned-badge">unassociated</span>'
+        : (item.VirtualMachine ? `${item.VirtualMachine} (VM)` : azureResourceLink(item.AttachedTo));
+    return `<td title="${item.ID}">${item.Name}</td><td>${item.ResourceGroup}</td><td>${item.Location || ''}</td>` +
+        `<td>${item.IPAddress || ''}</td><td>${item.SKU || ''}</td><td>${item.AllocationMethod || ''}</td><td>${item.Version || ''}</td>` +
+        `<td>${item.FQDN || ''}</td><td>${attachedTo}</td>`;
+}
+
+function azureLoadBalancerRowTemplate(item) {
+    return `<td title="${item.ID}">${item.Name}</td><td>${item.ResourceGroup}</td><td>${item.Location || ''}</td><td>${item.SKU || ''}</td>` +
+        `<td>${(item.Frontends || []).join('<br>')}</td><td>${(item.BackendPools || []).join(', ')}</td>` +
+        `<td>${(item.Rules || []).join('<br>')}</td><td>${(item.VirtualMachines || []).join(', ')}</td>`;
+}
+
+function azureApplicationGatewayRowTemplate(item) {
+    const sku = item.Capacity ? `${item.SKU} x ${item.Capacity}` : item.SKU;
+    const waf = item.WAF || (item.FirewallPolicy ? azureResourceLink(item.FirewallPolicy) : '');
+    return `<td title="${item.ID}">${item.Name}</td><td>${item.ResourceGroup}</td><td>${item.Location || ''}</td>` +
+        `<td title="${item.Tier || ''}">${sku || ''}</td><td>${item.OperationalState || ''}</td><td>${(item.Frontends || []).join('<br>')}</td>` +
+        `<td>${(item.Listeners || []).join('<br>')}</td><td>${(item.BackendPools || []).join(', ')}</td><td>${waf}</td>` +
+        `<td>${(item.VirtualMachines || []).join(', ')}</td>`;
+}
+
+function azurePrivateEndpointRowTemplate(item) {
+    return `<td title="${item.ID}">${item.Name}</td><td>${item.ResourceGroup}</td><td>${item.Location || ''}</td><td>${item.Subnet}</td>` +
+        `<td>${(item.PrivateIPs || []).join(', ')}</td><td title="${item.TargetID}">${item.Target}</td>` +
+        `<td>${(item.GroupIDs || []).join(', ')}</td><td>${item.ConnectionState || ''}</td>`;
+}
+
+function azureSQLServerInfoRowTemplate(item) {
+    return `<td title="${item.FQDN || item.ID}">${item.Name}</td><td>${item.ResourceGroup}</td><td>${item.Location || ''}</td>` +
+        `<td>${item.Version || ''}</td><td>${item.State || ''}</td><td>${item.PublicNetworkAccess || ''}</td>` +
+        `<td>${item.MinimalTLSVersion || ''}</td><td>${item.Databases}</td>`;
+}
+
+function azureSQLManagedInstanceInfoRowTemplate(item) {
+    const sku = item.Tier && item.Tier !== item.SKU ? `${item.SKU} (${item.Tier})` : item.SKU;
+    return `<td title="${item.ID}">${item.Name}</td><td>${item.ResourceGroup}</td><td>${item.Location || ''}</td>` +
+        `<td>${sku || ''}</td><td>${item.VCores}</td><td>${item.StorageSizeGB} GB</td><td>${item.LicenseType || ''}</td>` +
+        `<td>${item.State || ''}</td><td>${item.BackupStorageRedundancy || ''}</td><td>${item.Databases}</td>`;
+}
+
+function azureSQLDatabaseInfoRowTemplate(item) {
+    const sku = item.Tier && item.Tier !== item.SKU ? `${item.SKU} (${item.Tier})` : item.SKU;
+    const server = item.ManagedInstance ? `${item.Server} (Managed Instance)` : item.Server;
+    const maxSize = item.MaxSizeBytes ? formatBytes(item.MaxSizeBytes) : '';
+    const ltr = item.LongTermRetention;
+    const retention = ltr
+        ? [['Weekly', ltr.WeeklyRetention], ['Monthly', ltr.MonthlyRetention], ['Yearly', ltr.YearlyRetention]]
+            .filter(([, duration]) => duration).map(([period, duration]) => `${period}: ${duration}`).join('<br>')
+        : (item.Name === 'master' ? '' : '<span class="orphaned-badge">none</span>');
+    const replicas = (item.GeoReplicas || []).map(replica =>
+        `${replica.PartnerServer}/${replica.PartnerDatabase} (${replica.PartnerLocation || ''}, ${replica.PartnerRole || ''})`).join('<br>');
+    return `<td title="${item.ID}">${item.Name}</td><td>${server}</td><td>${item.ResourceGroup}</td><td>${item.Location || ''}</td>` +
+        `<td>${sku || ''}</td><td>${item.Status || ''}</td><td>${maxSize}</td><td>${item.BackupStorageRedundancy || ''}</td>` +
+        `<td>${retention}</td><td>${replicas}</td><td>${item.Backup ? azureBackupCell(item.Backup) : ''}</td>`;
+}
+
+function azureCosmosDBInfoRowTemplate(item) {
+    return `<td title="${item.ID}">${item.Name}</td><td>${item.ResourceGroup}</td><td>${item.Location || ''}</td>` +
+        `<td>${item.Kind}</td><td>${item.ConsistencyLevel || ''}</td><td>${(item.Locations || []).join(', ')}</td>`;
+}
+
+function azureSoftDelete(item) {
+    if (!item.SoftDelete) {
+        return '';
+    }
+    return item.SoftDeleteRetentionDays ? `${item.SoftDelete} (${item.SoftDeleteRetentionDays} days)` : item.SoftDelete;
+}
+
+function azureVaultRowTemplate(item) {
+    return `<td title="${item.ID}">${item.Name}</td><td>${item.ResourceGroup}</td><td>${item.Location || ''}</td>` +
+        `<td>${item.Redundancy || ''}</td><td>${item.CrossRegionRestore}</td><td>${azureSoftDelete(item)}</td>` +
+        `<td>${item.Immutability || ''}</td><td>${item.ProtectedItems}</td>`;
+}
+
+function azureBackupPolicyRowTemplate(item) {
+    return `<td title="${item.ID}">${item.Name}</td><td>${item.Vault}</td><td>${item.WorkloadType || ''}</td>` +
+        `<td>${item.Schedule || ''}</td><td>${item.Retention || ''}</td><td>${item.ProtectedItems}</td>`;
+}
+
+function azureProtectedItemRowTemplate(item) {
+    const name = item.SoftDeleted ? `${item.Name} <span class="orphaned-badge">soft deleted</span>` : item.Name;
+    return `<td title="${item.SourceResourceID}">${name}</td><td>${item.WorkloadType}</td><td>${item.Vault}</td>` +
+        `<td>${item.Policy || ''}</td><td>${item.ProtectionState || ''}</td><td>${item.HealthStatus || ''}</td>` +
+        `<td>${item.LastBackupStatus || ''}</td><td>${item.LastBackupTime || ''}</td>`;
+}
+
 function azureResourceGroupRowTemplate(item) {
     const tags = item.tags ? Object.entries(item.tags).map(([key, value]) => 
         `${key}: ${value}`).join(', ') : 'No tags';
@@ -127,6 +465,11 @@ function azureCosmosDBRowTemplate(item) {
     return `<td>${item.name}</td><td>${item.location}</td>`;
 }
 
+function azureResourceRowTemplate(item) {
+    return `<td title="${item.ID}">${item.Name}</td><td>${item.Type}</td><td>${item.ResourceGroup}</td><td>${item.Location}</td>` +
+        `<td>${item.SKU || ''}</td><td>${azureTags(item.Tags)}</td>`;
+}
+
 document.addEventListener('DOMContentLoaded', function() {
     console.log("DOM loaded - Azure module setting up event listener");
     
@@ -201,10 +544,10 @@ function showAzureCredentialsModal() {
             <div class="source-option" style="background: var(--background-color); border-radius: 6px; padding: 12px; margin-bottom: 15px;">
                 <input type="radio" id="azure-default-config" name="azure-config-source" value="default" checked>
                 <label for="azure-default-config" style="font-weight: bold; font-size: 1.1em;">
-                    <i class="fas fa-cog"></i> Use Azure CLI Configuration
+                    <i class="fas fa-cog"></i> Use Default Azure Credentials
                 </label>
                 <div id="azure-default-config-form" class="source-form" style="margin-top: 12px; margin-left: 25px; padding: 10px; background: rgba(255,255,255,0.05); border-radius: 4px;">
-                    <p style="margin-top: 0;">This option uses credentials and settings from your Azure CLI.</p>
+                    <p style="margin-top: 0;">This option uses environment variables, managed identity or your Azure CLI login.</p>
                     <div class="form-group" style="margin-top: 15px;">
                         <label for="azure-subscription-selector" style="font-weight: bold; margin-bottom: 5px;">Select Azure Subscription:</label>
                         <select id="azure-subscription-selector" style="width: 100%; padding: 8px; background: var(--input-bg-color); color: var(--text-color); border: 1px solid var(--border-color); border-radius: 4px; box-sizing: border-box;">
@@ -233,8 +576,8 @@ function showAzureCredentialsModal() {
                         <input type="password" id="azure-client-secret" style="width: 100%; padding: 8px; background: var(--input-bg-color); color: var(--text-color); border: 1px solid var(--border-color); border-radius: 4px; box-sizing: border-box;" placeholder="Your client secret">
                     </div>
                     <div class="form-group" style="margin-top: 15px;">
-                        <label for="azure-subscription-id" style="font-weight: bold; margin-bottom: 5px;">Subscription ID:</label>
-                        <input type="text" id="azure-subscription-id" style="width: 100%; padding: 8px; background: var(--input-bg-color); color: var(--text-color); border: 1px solid var(--border-color); border-radius: 4px; box-sizing: border-box;" placeholder="00000000-0000-0000-0000-000000000000">
+                        <label for="azure-subscription-id" style="font-weight: bold; margin-bottom: 5px;">Subscription IDs (optional):</label>
+                        <input type="text" id="azure-subscription-id" style="width: 100%; padding: 8px; background: var(--input-bg-color); color: var(--text-color); border: 1px solid var(--border-color); border-radius: 4px; box-sizing: border-box;" placeholder="Comma separated, or empty for all subscriptions">
                     </div>
                     <p class="tip" style="margin-top: 15px; font-size: 0.85em; color: var(--secondary-text-color); font-style: italic;">
                         Note: Credentials are used for the current session only and are never stored.
@@ -294,7 +637,7 @@ function showAzureCredentialsModal() {
             const clientSecret = document.getElementById('azure-client-secret').value;
             const subscriptionId = document.getElementById('azure-subscription-id').value;
             
-            if (!tenantId || !clientId || !clientSecret || !subscriptionId) {
+            if (!tenantId || !clientId || !clientSecret) {
                 alert('Please provide all required Azure service principal credentials');
                 return;
             }
@@ -330,6 +673,11 @@ function showAzureCredentialsModal() {
             subscriptionSelector.innerHTML = '';
             
             if (data.subscriptions && data.subscriptions.length > 0) {
+                const allOption = document.createElement('option');
+                allOption.value = "";
+                allOption.textContent = `All subscriptions (${data.subscriptions.length})`;
+                subscriptionSelector.appendChild(allOption);
+                
                 data.subscriptions.forEach(subscription => {
                     console.log("Processing subscription:", subscription);
                     
@@ -348,7 +696,7 @@ function showAzureCredentialsModal() {
             } else {
                 const option = document.createElement('option');
                 option.value = "";
-                option.textContent = "No subscriptions found - CLI may not be properly detected";
+                option.textContent = "No subscriptions found for the current credentials";
                 subscriptionSelector.appendChild(option);
                 
                 const noteDiv = document.createElement('div');
@@ -357,17 +705,14 @@ function showAzureCredentialsModal() {
                 noteDiv.style.backgroundColor = 'rgba(255, 165, 0, 0.1)';
                 noteDiv.style.borderLeft = '4px solid #FFA500';
                 
-                let subscriberCount = data.subscriptionsCount || 'multiple';
                 noteDiv.innerHTML = `
                     <p style="margin: 0; font-size: 0.9em; color: var(--text-color);">
-                        <i class="fas fa-info-circle"></i> <strong>Note:</strong> Your Azure CLI appears to be configured with ${subscriberCount} subscription(s), but we couldn't display them properly.
+                        <i class="fas fa-info-circle"></i> <strong>Note:</strong> No enabled subscriptions are visible to the current Azure credentials.
                         <br><br>
                         Try one of these options:
                         <ul style="margin-top: 5px; margin-bottom: 0; padding-left: 20px;">
-                            <li>Click Connect to try with your default subscription (<code>Subscription Name</code>)</li>
+                            <li>Sign in with <code>az login</code>, or set <code>AZURE_TENANT_ID</code>, <code>AZURE_CLIENT_ID</code> and <code>AZURE_CLIENT_SECRET</code></li>
                             <li>Use service principal credentials instead (select option above)</li>
-                            <li>If using Connect fails, you can manually set your default subscription in terminal with:<br>
-                            <code>az account set --subscription "Subscription Name"</code></li>
                         </ul>
                     </p>
                 `;
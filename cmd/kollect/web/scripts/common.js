@@ -205,6 +205,62 @@ function processWithHandler(data) {
     }, 200);
 }
 
+// Multi-source documents are registered here, ahead of the platform scripts,
+// so they are matched before any single platform handler.
+registerDataHandler('inventory',
+    function(data) {
+        return data.sources && typeof data.sources === 'object';
+    },
+    function(data) {
+        console.log("Processing multi-source inventory:", Object.keys(data.sources));
+
+        const content = document.getElementById('content');
+        const names = Object.keys(data.sources).sort();
+
+        names.forEach(name => {
+            const source = data.sources[name];
+
+            const header = document.createElement('div');
+            header.className = `source-header ${source.status}`;
+            const identity = Object.entries(source.identity || {})
+                .filter(([, value]) => value)
+                .map(([key, value]) => `<span class="source-identity-item">${key}: ${value}</span>`)
+                .join('');
+            header.innerHTML = `
+                <h2>${name}${source.platform && source.platform !== name ? ` <span class="source-platform">${source.platform}</span>` : ''}</h2>
+                <span class="source-status">${source.status}</span>
+                <span class="source-duration">${(source.durationSeconds || 0).toFixed(1)}s</span>
+                ${source.startedAt && !source.startedAt.startsWith('0001') ? `<span class="source-duration">${new Date(source.startedAt).toLocaleString()}</span>` : ''}
+                ${identity ? `<div class="source-identity">${identity}</div>` : ''}
+                ${source.error ? `<p class="source-error">${source.error}</p>` : ''}
+            `;
+            content.appendChild(header);
+
+            if (source.status !== 'success' || !source.data) {
+                return;
+            }
+
+            let handlerFound = false;
+            for (const [id, handler] of Object.entries(window.dataHandlers)) {
+                if (id === 'inventory') continue;
+                if (handler.test(source.data)) {
+                    console.log(`Processing ${name} with ${id} handler`);
+                    handler.handler(source.data);
+                    handlerFound = true;
+                    break;
+                }
+            }
+
+            if (!handlerFound) {
+                const empty = document.createElement('p');
+                empty.className = 'source-empty';
+                empty.textContent = 'No resources found';
+                content.appendChild(empty);
+            }
+        });
+    }
+);
+
 function displayUnknownDataFormat(data) {
     const content = document.getElementById('content');
     content.innerHTML = `
@@ -509,13 +565,16 @@ function setupExportButton() {
                 
                 let exportFilename = 'kollect_data.json';
                 
-                if (data.serverInfo && data.secretEngines) {
+                if (data.sources) {
+                    const names = Object.keys(data.sources);
+                    exportFilename = names.length === 1 ? `kollect_${names[0]}_data.json` : 'kollect_inventory_data.json';
+                } else if (data.serverInfo && data.secretEngines) {
                     exportFilename = 'kollect_vault_data.json';
                 } else if (data.Nodes || data.Namespaces) {
                     exportFilename = 'kollect_kubernetes_data.json';
                 } else if (data.EC2Instances) {
                     exportFilename = 'kollect_aws_data.json';
-                } else if (data.AzureResourceGroups) {
+                } else if (data.ResourceGroups || data.AzureResourceGroups) {
                     exportFilename = 'kollect_azure_data.json';
                 } else if (data.ComputeInstances) {
                     exportFilename = 'kollect_gcp_data.json';
@@ -558,21 +617,39 @@ document.getElementById('import-file')?.addEventListener('change', (event) => {
         showLoadingIndicator();
         const reader = new FileReader();
         reader.onload = (e) => {
-            try {
-                const data = JSON.parse(e.target.result);
-                console.log("Imported data:", Object.keys(data));
+            fetch('/api/import', {
+                method: 'POST',
+                headers: {
+                    'Content-Type': 'application/json'
+                },
+                body: e.target.result
+            })
+            .then(response => {
+                if (!response.ok) {
+                    return response.text().then(text => { throw new Error(text); });
+                }
+                return response.json();
+            })
+            .then(result => {
+                console.log(`Imported data (schema version ${result.migratedFrom} -> ${result.schemaVersion})`);
+                return fetch('/api/data');
+            })
+            .then(response => response.json())
+            .then(data => {
                 processWithHandler(data);
-                hideLoadingIndicator();
-            } catch (error) {
-                console.error("Error parsing imported data:", error);
+            })
+            .catch(error => {
+                console.error("Error importing data:", error);
                 document.getElementById('content').innerHTML = `
                     <div class="error-message">
                         <h2>Error Importing Data</h2>
                         <p>${error.message}</p>
                     </div>
                 `;
+            })
+            .finally(() => {
                 hideLoadingIndicator();
-            }
+            });
         };
         reader.readAsText(file);
     }
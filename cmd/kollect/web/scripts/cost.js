@@ -166,7 +166,7 @@ function processPlatformCosts(platform, costData) {
             });
             
             createTable(`${platform} EBS Snapshot Costs`, costData.EBSSnapshotCosts, 
-                item => `<td>${item.SnapshotId}</td><td>${item.VolumeId || 'N/A'}</td><td>${item.SizeGB} GB</td><td>${item.Region}</td><td>$${item.PricePerGBMonth.toFixed(3)}</td><td>${item.MonthlyCostUSD}</td>`,
+                item => `<td>${item.SnapshotId}</td><td>${item.VolumeId || 'N/A'}${item.VolumeDeleted ? ' <span class="orphaned-badge">volume deleted</span>' : ''}</td><td>${item.SizeGB} GB</td><td>${item.Region}</td><td>$${item.PricePerGBMonth.toFixed(3)}</td><td>${item.MonthlyCostUSD}</td>`,
                 ['Snapshot ID', 'Volume ID', 'Size', 'Region', 'Price per GB/Month', 'Monthly Cost']);
         } else {
             console.log("No EBS snapshot costs found");
@@ -207,10 +207,20 @@ function processPlatformCosts(platform, costData) {
             
             console.log(`Found ${costData.S3Costs.length} S3 bucket costs`);
             createTable(`${platform} S3 Bucket Costs`, costData.S3Costs, 
-                item => `<td>${item.Name}</td><td>${item.SizeGB} GB</td><td>${item.Region}</td><td>${item.StorageClass}</td><td>$${item.PricePerGB.toFixed(4)}/GB</td><td>$${item.MonthlyCost.toFixed(2)}</td>`,
+                item => `<td>${item.Name}</td><td>${item.SizeMeasured ? item.SizeGB.toFixed(2) : item.SizeGB} GB${item.SizeMeasured ? '' : ' <span class="orphaned-badge">estimated</span>'}</td><td>${item.Region}</td><td>${item.StorageClass}</td><td>$${item.PricePerGB.toFixed(4)}/GB</td><td>$${item.MonthlyCost.toFixed(2)}</td>`,
                 ['Bucket Name', 'Size', 'Region', 'Storage Class', 'Price per GB', 'Monthly Cost']);
         }
         
+        if (costData.UnattachedVolumeCosts && costData.UnattachedVolumeCosts.length > 0) {
+            costData.UnattachedVolumeCosts.forEach(item => {
+                storageCost += item.MonthlyCost || 0;
+            });
+            
+            createTable(`${platform} Unattached EBS Volume Costs`, costData.UnattachedVolumeCosts, 
+                item => `<td>${item.VolumeId}</td><td>${item.Name || ''}</td><td>${item.SizeGB} GB</td><td>${item.VolumeType}</td><td>${item.Region}</td><td>$${item.MonthlyCost.toFixed(2)}</td>`,
+                ['Volume ID', 'Name', 'Size', 'Type', 'Region', 'Monthly Cost']);
+        }
+        
         if (costData.RDSInstanceCosts && costData.RDSInstanceCosts.length > 0) {
             costData.RDSInstanceCosts.forEach(item => {
                 dataServicesCost += item.MonthlyCost || 0;
@@ -233,10 +243,20 @@ function processPlatformCosts(platform, costData) {
             });
             
             createTable(`${platform} Disk Snapshot Costs`, costData.DiskSnapshotCosts, 
-                item => `<td>${item.Name}</td><td>${item.SizeGB} GB</td><td>${item.Location}</td><td>${item.State || 'N/A'}</td><td>$${item.PricePerGBMonth.toFixed(3)}</td><td>${item.MonthlyCostUSD}</td>`,
+                item => `<td>${item.Name}${item.DiskDeleted ? ' <span class="orphaned-badge">disk deleted</span>' : ''}</td><td>${item.SizeGB} GB</td><td>${item.Location}</td><td>${item.State || 'N/A'}</td><td>$${item.PricePerGBMonth.toFixed(3)}</td><td>${item.MonthlyCostUSD}</td>`,
                 ['Name', 'Size', 'Region', 'State', 'Price per GB/Month', 'Monthly Cost']);
         }
         
+        if (costData.UnattachedDiskCosts && costData.UnattachedDiskCosts.length > 0) {
+            costData.UnattachedDiskCosts.forEach(item => {
+                storageCost += item.MonthlyCost || 0;
+            });
+            
+            createTable(`${platform} Unattached Managed Disk Costs`, costData.UnattachedDiskCosts, 
+                item => `<td>${item.Name}</td><td>${item.ResourceGroup || ''}</td><td>${item.SizeGB} GB</td><td>${item.SKU}</td><td>${item.Location}</td><td>$${item.MonthlyCost.toFixed(2)}</td>`,
+                ['Name', 'Resource Group', 'Size', 'SKU', 'Location', 'Monthly Cost']);
+        }
+        
         if (costData.VMCosts && costData.VMCosts.length > 0) {
             costData.VMCosts.forEach(item => {
                 computeCost += item.MonthlyCost || 0;
@@ -322,6 +342,8 @@ function processPlatformCosts(platform, costData) {
         costData.Summary.TotalMonthlyCost = correctedTotal;
     }
         
+        const orphanedCost = (costData.Summary.OrphanedVolumeCost || 0) + (costData.Summary.OrphanedSnapshotCost || 0);
+        
         const summaryDiv = document.createElement('div');
         summaryDiv.className = 'cost-summary';
         summaryDiv.innerHTML = `
@@ -359,6 +381,13 @@ function processPlatformCosts(platform, costData) {
                     </div>
                     ` : ''}
                     
+                    ${orphanedCost > 0 ? `
+                    <div class="summary-item" style="margin-bottom: 10px; min-width: 180px;">
+                        <div style="font-size: 0.9em; color: var(--secondary-text-color);">Orphaned Storage Cost</div>
+                        <div style="font-size: 1.5em; font-weight: bold; color: #e67e22;">$${orphanedCost.toFixed(2)}</div>
+                    </div>
+                    ` : ''}
+                    
                     <div class="summary-item" style="margin-bottom: 10px; min-width: 180px; border-top: 1px dashed var(--border-color); padding-top: 10px; margin-top: 5px;">
                         <div style="font-size: 0.9em; color: var(--secondary-text-color);">Estimated Total Monthly Cost</div>
                         <div style="font-size: 1.5em; font-weight: bold; color: var(--accent-color);">$${costData.Summary.TotalMonthlyCost.toFixed(2)}</div>
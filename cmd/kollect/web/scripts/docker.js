@@ -1542,7 +1542,7 @@ document.addEventListener('DOMContentLoaded', function() {
         fetch('/api/data')
             .then(response => response.json())
             .then(data => {
-                window.currentData = data;
+                window.currentData = data.sources && data.sources.docker ? data.sources.docker.data : data;
             })
             .catch(error => {
                 console.error('Error fetching current data:', error);
@@ -0,0 +1,176 @@
+// history.js - Browse stored collections and resource trends
+
+console.log("Loading History module");
+
+function showHistory() {
+    showLoadingIndicator();
+
+    Promise.all([
+        fetch('/api/history').then(response => response.json()),
+        fetch('/api/history/counts').then(response => response.ok ? response.json() : {})
+    ])
+        .then(([history, counts]) => {
+            const content = document.getElementById('content');
+            content.innerHTML = '';
+            const chartsContainer = document.getElementById('charts-container');
+            if (chartsContainer) {
+                chartsContainer.style.display = 'none';
+            }
+
+            if (!history.enabled) {
+                content.innerHTML = `
+                    <div class="unknown-data">
+                        <h2>History Not Enabled</h2>
+                        <p>Start kollect with <code>--history kollect-history.db</code> to store every collection and browse it here.</p>
+                    </div>
+                `;
+                return;
+            }
+
+            const runs = (history.runs || []).slice().reverse();
+            if (runs.length === 0) {
+                content.innerHTML = `
+                    <div class="unknown-data">
+                        <h2>No History Yet</h2>
+                        <p>Collected inventories will appear here.</p>
+                    </div>
+                `;
+                return;
+            }
+
+            createHistoryChart(counts);
+
+            createTable('Collection History', runs, historyRunRowTemplate,
+                ['Started', 'Duration', 'Sources', 'Failed', 'Resources', '']);
+
+            const table = document.getElementById('table-container-collection-history');
+            if (table) {
+                table.querySelector('.table-header').classList.remove('collapsed');
+                table.querySelector('.table-content').classList.remove('collapsed');
+            }
+            updateResourceNav();
+        })
+        .catch(error => {
+            console.error("Error loading history:", error);
+            document.getElementById('content').innerHTML = `
+                <div class="error-message">
+                    <h2>Error Loading History</h2>
+                    <p>${error.message}</p>
+                </div>
+            `;
+        })
+        .finally(() => {
+            hideLoadingIndicator();
+        });
+}
+
+function historyRunRowTemplate(run) {
+    const started = new Date(run.startedAt);
+    const finished = new Date(run.finishedAt);
+    const duration = run.finishedAt && !run.finishedAt.startsWith('0001')
+        ? `${((finished - started) / 1000).toFixed(1)}s` : '';
+    const total = Object.values(run.counts || {})
+        .reduce((sum, collections) => sum + Object.values(collections).reduce((a, b) => a + b, 0), 0);
+
+    return `
+        <td>${started.toLocaleString()}</td>
+        <td>${duration}</td>
+        <td>${(run.sources || []).join(', ')}</td>
+        <td>${(run.failed || []).join(', ')}</td>
+        <td>${total}</td>
+        <td><button class="history-load-button" onclick="loadHistoryRun('${run.id}')">Load</button></td>
+    `;
+}
+
+function loadHistoryRun(id) {
+    showLoadingIndicator();
+
+    fetch(`/api/history/load?id=${encodeURIComponent(id)}`)
+        .then(response => {
+            if (!response.ok) {
+                return response.text().then(text => { throw new Error(text); });
+            }
+            return fetch('/api/data');
+        })
+        .then(response => response.json())
+        .then(data => {
+            processWithHandler(data);
+        })
+        .catch(error => {
+            console.error("Error loading run:", error);
+            document.getElementById('content').innerHTML = `
+                <div class="error-message">
+                    <h2>Error Loading Run</h2>
+                    <p>${error.message}</p>
+                </div>
+            `;
+        })
+        .finally(() => {
+            hideLoadingIndicator();
+        });
+}
+
+function createHistoryChart(counts) {
+    const sources = Object.keys(counts || {}).filter(source => counts[source] && counts[source].length > 0);
+    if (sources.length === 0) return;
+
+    const wrapper = document.createElement('div');
+    wrapper.className = 'chart-wrapper history-chart';
+    const canvas = document.createElement('canvas');
+    wrapper.appendChild(canvas);
+    document.getElementById('content').appendChild(wrapper);
+
+    const textColor = getComputedStyle(document.documentElement).getPropertyValue('--text-color').trim();
+    const colors = ['#36A2EB', '#FF6384', '#4BC0C0', '#FF9F40', '#9966FF', '#FFCD56', '#C9CBCF', '#2ED573'];
+
+    const runIds = [...new Set(sources.flatMap(source => counts[source].map(point => point.runId)))].sort();
+    const startedAt = {};
+    sources.forEach(source => counts[source].forEach(point => { startedAt[point.runId] = point.startedAt; }));
+
+    new Chart(canvas.getContext('2d'), {
+        type: 'line',
+        data: {
+            labels: runIds.map(id => new Date(startedAt[id]).toLocaleString()),
+            datasets: sources.map((source, i) => {
+                const totals = {};
+                counts[source].forEach(point => { totals[point.runId] = point.total; });
+                return {
+                    label: source,
+                    data: runIds.map(id => id in totals ? totals[id] : null),
+                    borderColor: colors[i % colors.length],
+                    backgroundColor: colors[i % colors.length],
+                    spanGaps: true,
+                    tension: 0.2
+                };
+            })
+        },
+        options: {
+            responsive: true,
+            maintainAspectRatio: false,
+            scales: {
+                x: { ticks: { color: textColor } },
+                y: { beginAtZero: true, ticks: { color: textColor, precision: 0 } }
+            },
+            plugins: {
+                title: {
+                    display: true,
+                    text: 'Resources Over Time',
+                    color: textColor
+                },
+                legend: {
+                    labels: { color: textColor }
+                }
+            }
+        }
+    });
+}
+
+document.addEventListener('DOMContentLoaded', function() {
+    const historyButton = document.getElementById('history-button');
+    if (historyButton) {
+        historyButton.addEventListener('click', function(event) {
+            event.preventDefault();
+            showHistory();
+        });
+    }
+});
@@ -28,6 +28,31 @@ registerDataHandler('snapshot',
                 ['Snapshot ID', 'DB Instance', 'Type', 'Status', 'Engine', 'Size', 'Creation Time', 'Encrypted']);
         }
         
+        if (data.aws && data.aws.BackupVaults && data.aws.BackupVaults.length > 0) {
+            createTable('AWS Backup Vaults', data.aws.BackupVaults, awsBackupVaultRowTemplate,
+                ['Vault', 'Region', 'Lock', 'Retention', 'Recovery Points', 'Encryption Key', 'Creation Time']);
+        }
+        
+        if (data.aws && data.aws.RecoveryPoints && data.aws.RecoveryPoints.length > 0) {
+            createTable('AWS Backup Recovery Points', data.aws.RecoveryPoints, awsRecoveryPointRowTemplate,
+                ['Resource', 'Type', 'Vault', 'Status', 'Size', 'Creation Time', 'Cold Storage', 'Expires', 'Encrypted']);
+        }
+        
+        if (data.aws && data.aws.BackupPlans && data.aws.BackupPlans.length > 0) {
+            createTable('AWS Backup Plans', data.aws.BackupPlans, awsBackupPlanRowTemplate,
+                ['Plan', 'Region', 'Rules', 'Last Run']);
+        }
+        
+        if (data.aws && data.aws.BackupSelections && data.aws.BackupSelections.length > 0) {
+            createTable('AWS Backup Selections', data.aws.BackupSelections, awsBackupSelectionRowTemplate,
+                ['Selection', 'Plan', 'Resources', 'Excluded', 'Tags', 'Region']);
+        }
+        
+        if (data.aws && data.aws.ProtectedResources && data.aws.ProtectedResources.length > 0) {
+            createTable('AWS Backup Protected Resources', data.aws.ProtectedResources, awsProtectedResourceRowTemplate,
+                ['Resource', 'Type', 'Region', 'Last Backup', 'Vault']);
+        }
+        
         if (data.azure) {
             console.log("Azure data found:", data.azure);
             if (data.azure.DiskSnapshots) {
@@ -48,6 +73,21 @@ registerDataHandler('snapshot',
                 ['Name', 'Location', 'Size', 'State', 'Creation Time']);
          }
         
+        if (data.azure && data.azure.BackupVaults && data.azure.BackupVaults.length > 0) {
+            createTable('Azure Backup Vaults', data.azure.BackupVaults, azureBackupVaultRowTemplate,
+                ['Vault', 'Type', 'Location', 'Redundancy', 'Soft Delete', 'Immutability', 'Protected Items']);
+        }
+        
+        if (data.azure && data.azure.ProtectedItems && data.azure.ProtectedItems.length > 0) {
+            createTable('Azure Backup Protected Items', data.azure.ProtectedItems, azureProtectedItemRowTemplate,
+                ['Item', 'Workload', 'Vault', 'Policy', 'Protection State', 'Last Backup', 'Last Backup Time']);
+        }
+        
+        if (data.azure && data.azure.RecoveryPoints && data.azure.RecoveryPoints.length > 0) {
+            createTable('Azure Backup Recovery Points', data.azure.RecoveryPoints, azureRecoveryPointRowTemplate,
+                ['Protected Item', 'Workload', 'Vault', 'Type', 'Tier', 'Creation Time']);
+        }
+        
         if (data.gcp && data.gcp.DiskSnapshots && data.gcp.DiskSnapshots.length > 0) {
             createTable('GCP Disk Snapshots', data.gcp.DiskSnapshots, gcpDiskSnapshotRowTemplate, 
                 ['Name', 'Source Disk', 'Size', 'Status', 'Creation Time']);
@@ -57,7 +97,11 @@ registerDataHandler('snapshot',
             !data.kubernetes?.VolumeSnapshotContents?.length && 
             !data.aws?.EBSSnapshots?.length && 
             !data.aws?.RDSSnapshots?.length && 
+            !data.aws?.RecoveryPoints?.length && 
+            !data.aws?.BackupVaults?.length && 
             !data.azure?.DiskSnapshots?.length && 
+            !data.azure?.RecoveryPoints?.length && 
+            !data.azure?.BackupVaults?.length && 
             !data.gcp?.DiskSnapshots?.length) {
             document.getElementById('content').innerHTML = `
                 <div class="empty-state">
@@ -111,10 +155,43 @@ function awsRdsSnapshotRowTemplate(item) {
     return `<td>${item.SnapshotId}</td><td>${item.DBInstanceId}</td><td>${item.SnapshotType}</td><td>${item.Status}</td><td>${item.Engine}</td><td>${item.AllocatedStorage}</td><td>${item.CreationTime}</td><td>${encrypted}</td>`;
 }
 
+function awsBackupVaultRowTemplate(item) {
+    const lockIcon = item.Locked === "true" ? '<i class="fas fa-lock"></i> ' : '<i class="fas fa-unlock"></i> ';
+    const retention = item.MinRetentionDays || item.MaxRetentionDays
+        ? `${item.MinRetentionDays || "-"} to ${item.MaxRetentionDays || "-"} days`
+        : "-";
+    return `<td>${item.VaultName}</td><td>${item.Region}</td><td>${lockIcon}${item.LockState}</td><td>${retention}</td>` +
+        `<td>${item.RecoveryPoints}</td><td>${item.EncryptionKeyArn || "-"}</td><td>${item.CreationDate || "-"}</td>`;
+}
+
+function awsRecoveryPointRowTemplate(item) {
+    let encrypted = item.Encrypted === "true" ? '<i class="fas fa-lock" title="Encrypted"></i>' : '<i class="fas fa-unlock" title="Not encrypted"></i>';
+    const coldStorage = item.MoveToColdStorageAt || (item.MoveToColdStorageAfterDays ? `after ${item.MoveToColdStorageAfterDays} days` : "-");
+    const expires = item.DeleteAt || (item.DeleteAfterDays ? `after ${item.DeleteAfterDays} days` : "never");
+    return `<td title="${item.ResourceArn}">${item.ResourceName || item.ResourceArn}</td><td>${item.ResourceType}</td><td>${item.VaultName}</td>` +
+        `<td>${item.Status}</td><td>${item.BackupSize || "-"}</td><td>${item.CreationTime || "-"}</td><td>${coldStorage}</td><td>${expires}</td><td>${encrypted}</td>`;
+}
+
+function awsBackupPlanRowTemplate(item) {
+    return `<td>${item.BackupPlanName}</td><td>${item.Region}</td><td>${item.Rules || "-"}</td><td>${item.LastExecutionDate || "-"}</td>`;
+}
+
+function awsBackupSelectionRowTemplate(item) {
+    return `<td>${item.SelectionName}</td><td>${item.BackupPlanName}</td><td>${item.Resources || "-"}</td>` +
+        `<td>${item.NotResources || "-"}</td><td>${item.Tags || "-"}</td><td>${item.Region}</td>`;
+}
+
+function awsProtectedResourceRowTemplate(item) {
+    return `<td title="${item.ResourceArn}">${item.ResourceName || item.ResourceArn}</td><td>${item.ResourceType}</td><td>${item.Region}</td>` +
+        `<td>${item.LastBackupTime || "-"}</td><td>${item.LastBackupVault || "-"}</td>`;
+}
+
 function azureDiskSnapshotRowTemplate(item) {
     console.log("Azure snapshot item:", item);
     
-    const name = item.Name || "Unknown";
+    const name = item.DiskDeleted === "true"
+        ? `${item.Name || "Unknown"} <span class="orphaned-badge">disk deleted</span>`
+        : item.Name || "Unknown";
     const location = item.Location || "-";
     
     let sizeDisplay = "-";
@@ -138,6 +215,25 @@ function azureDiskSnapshotRowTemplate(item) {
     return `<td>${name}</td><td>${location}</td><td>${sizeDisplay}</td><td>${state}</td><td>${creationTime}</td>`;
 }
 
+function azureBackupVaultRowTemplate(item) {
+    const vaultType = item.VaultType === "BackupVault" ? "Backup vault" : "Recovery Services vault";
+    return `<td>${item.Name}</td><td>${vaultType}</td><td>${item.Location || "-"}</td><td>${item.Redundancy || "-"}</td>` +
+        `<td>${item.SoftDelete || "-"}</td><td>${item.Immutability || "-"}</td><td>${item.ProtectedItems}</td>`;
+}
+
+function azureProtectedItemRowTemplate(item) {
+    const name = item.SoftDeleted === "true"
+        ? `${item.Name} <span class="orphaned-badge">soft deleted</span>`
+        : item.Name;
+    return `<td title="${item.SourceResourceId || ""}">${name}</td><td>${item.WorkloadType || "-"}</td><td>${item.Vault}</td>` +
+        `<td>${item.Policy || "-"}</td><td>${item.ProtectionState || "-"}</td><td>${item.LastBackupStatus || "-"}</td><td>${item.LastBackupTime || "-"}</td>`;
+}
+
+function azureRecoveryPointRowTemplate(item) {
+    return `<td title="${item.SourceResourceId || ""}">${item.ProtectedItem}</td><td>${item.WorkloadType || "-"}</td><td>${item.Vault}</td>` +
+        `<td>${item.RecoveryPointType || "-"}</td><td>${item.Tier || "-"}</td><td>${item.CreationTime || "-"}</td>`;
+}
+
 function gcpDiskSnapshotRowTemplate(item) {
     return `<td>${item.Name}</td><td>${item.SourceDiskName || "-"}</td><td>${item.DiskSizeGB} GB</td><td>${item.Status}</td><td>${item.CreationTime}</td>`;
 }
@@ -384,7 +480,7 @@ function collectSnapshots(platforms) {
             
             document.getElementById('content').innerHTML = '';
             
-            if (targetPlatform === 'all') {
+            if (targetPlatform === 'all' || data.sources) {
                 console.log("Processing multi-platform data");
                 processWithHandler(data);
             } else {
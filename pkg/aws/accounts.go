@@ -0,0 +1,364 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	orgtypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// Account is an AWS account to collect from, with the SDK options that reach
+// it: either a shared config profile or a role assumed from one.
+type Account struct {
+	ID      string
+	Alias   string
+	Profile string
+	Role    string
+	optFns  []func(*config.LoadOptions) error
+}
+
+// AccountInfo records an account in the collected data and whether it could
+// be collected.
+type AccountInfo struct {
+	AccountID    string
+	AccountAlias string
+	Profile      string
+	Role         string
+	Error        string `json:",omitempty"`
+}
+
+// Accounts resolves the accounts to collect from. Each profile is one
+// account, and no profiles means the default credential chain. When role is
+// set, every active member account of the AWS Organization visible from the
+// first profile is added by assuming that role into it.
+func Accounts(ctx context.Context, profiles []string, role string) ([]Account, error) {
+	if len(profiles) == 0 {
+		profiles = []string{""}
+	}
+
+	var accounts []Account
+	var errs []string
+	seen := map[string]bool{}
+	for _, profile := range profiles {
+		var optFns []func(*config.LoadOptions) error
+		if profile != "" {
+			optFns = append(optFns, config.WithSharedConfigProfile(profile))
+		}
+
+		account, err := resolveAccount(ctx, optFns)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("profile %s: %v", profileName(profile), err))
+			continue
+		}
+		if seen[account.ID] {
+			log.Printf("Warning: Profile %s is for account %s, which is already being collected", profileName(profile), account.ID)
+			continue
+		}
+		seen[account.ID] = true
+		account.Profile = profile
+		accounts = append(accounts, account)
+	}
+
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("unable to resolve any AWS account: %s", strings.Join(errs, "; "))
+	}
+	for _, e := range errs {
+		log.Printf("Warning: Skipping AWS %s", e)
+	}
+
+	if role == "" {
+		return accounts, nil
+	}
+
+	members, err := memberAccounts(ctx, accounts[0], role, seen)
+	if err != nil {
+		return nil, err
+	}
+	return append(accounts, members...), nil
+}
+
+// memberAccounts lists the active accounts of the Organization that
+// management belongs to and returns those not already in seen, reached by
+// assuming role.
+func memberAccounts(ctx context.Context, management Account, role string, seen map[string]bool) ([]Account, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, management.optFns...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load SDK config, %v", err)
+	}
+
+	stsClient := sts.NewFromConfig(cfg)
+	identity, err := stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get caller identity, %v", err)
+	}
+	partition := "aws"
+	if parts := strings.SplitN(aws.ToString(identity.Arn), ":", 3); len(parts) == 3 {
+		partition = parts[1]
+	}
+
+	var accounts []Account
+	paginator := organizations.NewListAccountsPaginator(organizations.NewFromConfig(cfg), &organizations.ListAccountsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list organization accounts, %v", err)
+		}
+
+		for _, member := range page.Accounts {
+			id := aws.ToString(member.Id)
+			if member.Status != orgtypes.AccountStatusActive || seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			roleARN := fmt.Sprintf("arn:%s:iam::%s:role/%s", partition, id, role)
+			provider := stscreds.NewAssumeRoleProvider(stsClient, roleARN, func(o *stscreds.AssumeRoleOptions) {
+				o.RoleSessionName = "kollect"
+			})
+
+			account := Account{
+				ID:      id,
+				Alias:   aws.ToString(member.Name),
+				Profile: management.Profile,
+				Role:    roleARN,
+				optFns:  withCredentials(management.optFns, aws.NewCredentialsCache(provider)),
+			}
+			if alias := accountAlias(ctx, account.optFns); alias != "" {
+				account.Alias = alias
+			}
+			accounts = append(accounts, account)
+		}
+	}
+
+	log.Printf("Found %d AWS Organization member accounts to collect with role %s", len(accounts), role)
+	return accounts, nil
+}
+
+func resolveAccount(ctx context.Context, optFns []func(*config.LoadOptions) error) (Account, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, optFns...)
+	if err != nil {
+		return Account{}, fmt.Errorf("unable to load SDK config, %v", err)
+	}
+
+	identity, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return Account{}, fmt.Errorf("unable to get caller identity, %v", err)
+	}
+
+	return Account{
+		ID:     aws.ToString(identity.Account),
+		Alias:  accountAlias(ctx, optFns),
+		optFns: optFns,
+	}, nil
+}
+
+// accountAlias returns the IAM account alias, or "" if the account has none
+// or the credentials may not read it.
+func accountAlias(ctx context.Context, optFns []func(*config.LoadOptions) error) string {
+	cfg, err := config.LoadDefaultConfig(ctx, optFns...)
+	if err != nil {
+		return ""
+	}
+	result, err := iam.NewFromConfig(cfg).ListAccountAliases(ctx, &iam.ListAccountAliasesInput{})
+	if err != nil || len(result.AccountAliases) == 0 {
+		return ""
+	}
+	return result.AccountAliases[0]
+}
+
+func profileName(profile string) string {
+	if profile == "" {
+		return "default"
+	}
+	return profile
+}
+
+func (a Account) info(err error) AccountInfo {
+	info := AccountInfo{
+		AccountID:    a.ID,
+		AccountAlias: a.Alias,
+		Profile:      profileName(a.Profile),
+		Role:         a.Role,
+	}
+	if err != nil {
+		info.Error = err.Error()
+	}
+	return info
+}
+
+// setAccount tags every resource and error in d with the account it belongs
+// to.
+func (d *AWSData) setAccount(a Account) {
+	for i := range d.EC2Instances {
+		d.EC2Instances[i].AccountID, d.EC2Instances[i].AccountAlias = a.ID, a.Alias
+	}
+	for i := range d.S3Buckets {
+		d.S3Buckets[i].AccountID, d.S3Buckets[i].AccountAlias = a.ID, a.Alias
+	}
+	for i := range d.RDSInstances {
+		d.RDSInstances[i].AccountID, d.RDSInstances[i].AccountAlias = a.ID, a.Alias
+	}
+	for i := range d.DynamoDBTables {
+		d.DynamoDBTables[i].AccountID, d.DynamoDBTables[i].AccountAlias = a.ID, a.Alias
+	}
+	for i := range d.VPCs {
+		d.VPCs[i].AccountID, d.VPCs[i].AccountAlias = a.ID, a.Alias
+	}
+	for i := range d.Subnets {
+		d.Subnets[i].AccountID, d.Subnets[i].AccountAlias = a.ID, a.Alias
+	}
+	for i := range d.RouteTables {
+		d.RouteTables[i].AccountID, d.RouteTables[i].AccountAlias = a.ID, a.Alias
+	}
+	for i := range d.InternetGateways {
+		d.InternetGateways[i].AccountID, d.InternetGateways[i].AccountAlias = a.ID, a.Alias
+	}
+	for i := range d.NATGateways {
+		d.NATGateways[i].AccountID, d.NATGateways[i].AccountAlias = a.ID, a.Alias
+	}
+	for i := range d.ElasticIPs {
+		d.ElasticIPs[i].AccountID, d.ElasticIPs[i].AccountAlias = a.ID, a.Alias
+	}
+	for i := range d.SecurityGroups {
+		d.SecurityGroups[i].AccountID, d.SecurityGroups[i].AccountAlias = a.ID, a.Alias
+	}
+	for i := range d.LoadBalancers {
+		d.LoadBalancers[i].AccountID, d.LoadBalancers[i].AccountAlias = a.ID, a.Alias
+	}
+	for i := range d.EBSVolumes {
+		d.EBSVolumes[i].AccountID, d.EBSVolumes[i].AccountAlias = a.ID, a.Alias
+	}
+	for i := range d.EKSClusters {
+		d.EKSClusters[i].AccountID, d.EKSClusters[i].AccountAlias = a.ID, a.Alias
+	}
+	for i := range d.EKSNodeGroups {
+		d.EKSNodeGroups[i].AccountID, d.EKSNodeGroups[i].AccountAlias = a.ID, a.Alias
+	}
+	for i := range d.ECSClusters {
+		d.ECSClusters[i].AccountID, d.ECSClusters[i].AccountAlias = a.ID, a.Alias
+	}
+	for i := range d.ECSServices {
+		d.ECSServices[i].AccountID, d.ECSServices[i].AccountAlias = a.ID, a.Alias
+	}
+	for i := range d.ECSTasks {
+		d.ECSTasks[i].AccountID, d.ECSTasks[i].AccountAlias = a.ID, a.Alias
+	}
+	for i := range d.LambdaFunctions {
+		d.LambdaFunctions[i].AccountID, d.LambdaFunctions[i].AccountAlias = a.ID, a.Alias
+	}
+	for i := range d.OrphanedSnapshots {
+		d.OrphanedSnapshots[i].AccountID, d.OrphanedSnapshots[i].AccountAlias = a.ID, a.Alias
+	}
+	for i := range d.Regions {
+		d.Regions[i].AccountID = a.ID
+	}
+	for i := range d.Errors {
+		d.Errors[i].AccountID = a.ID
+	}
+}
+
+// CollectAccountsData collects every account into one AWSData with each
+// resource tagged by account. An account that fails is recorded in Accounts
+// and skipped, unless every account fails.
+func CollectAccountsData(ctx context.Context, accounts []Account) (AWSData, error) {
+	var data AWSData
+	var lastErr error
+	for _, account := range accounts {
+		log.Printf("Collecting AWS account %s (%s)", account.ID, account.Alias)
+		accountData, err := CollectAWSData(ctx, account.optFns...)
+		data.Accounts = append(data.Accounts, account.info(err))
+		if err != nil {
+			log.Printf("Warning: Failed to collect AWS account %s: %v", account.ID, err)
+			lastErr = err
+			continue
+		}
+
+		accountData.setAccount(account)
+		data.EC2Instances = append(data.EC2Instances, accountData.EC2Instances...)
+		data.S3Buckets = append(data.S3Buckets, accountData.S3Buckets...)
+		data.RDSInstances = append(data.RDSInstances, accountData.RDSInstances...)
+		data.DynamoDBTables = append(data.DynamoDBTables, accountData.DynamoDBTables...)
+		data.VPCs = append(data.VPCs, accountData.VPCs...)
+		data.Subnets = append(data.Subnets, accountData.Subnets...)
+		data.RouteTables = append(data.RouteTables, accountData.RouteTables...)
+		data.InternetGateways = append(data.InternetGateways, accountData.InternetGateways...)
+		data.NATGateways = append(data.NATGateways, accountData.NATGateways...)
+		data.ElasticIPs = append(data.ElasticIPs, accountData.ElasticIPs...)
+		data.SecurityGroups = append(data.SecurityGroups, accountData.SecurityGroups...)
+		data.LoadBalancers = append(data.LoadBalancers, accountData.LoadBalancers...)
+		data.EBSVolumes = append(data.EBSVolumes, accountData.EBSVolumes...)
+		data.EKSClusters = append(data.EKSClusters, accountData.EKSClusters...)
+		data.EKSNodeGroups = append(data.EKSNodeGroups, accountData.EKSNodeGroups...)
+		data.ECSClusters = append(data.ECSClusters, accountData.ECSClusters...)
+		data.ECSServices = append(data.ECSServices, accountData.ECSServices...)
+		data.ECSTasks = append(data.ECSTasks, accountData.ECSTasks...)
+		data.LambdaFunctions = append(data.LambdaFunctions, accountData.LambdaFunctions...)
+		data.OrphanedSnapshots = append(data.OrphanedSnapshots, accountData.OrphanedSnapshots...)
+		data.Regions = append(data.Regions, accountData.Regions...)
+		data.Errors = append(data.Errors, accountData.Errors...)
+	}
+
+	if failed := countFailed(data.Accounts); failed == len(accounts) {
+		if failed == 1 {
+			return AWSData{}, lastErr
+		}
+		return AWSData{}, fmt.Errorf("all %d AWS accounts failed, last error: %v", failed, lastErr)
+	}
+	return data, nil
+}
+
+// CollectAccountsSnapshotData collects snapshots from every account, tagging
+// each with AccountId and AccountAlias.
+func CollectAccountsSnapshotData(ctx context.Context, accounts []Account) (map[string]interface{}, error) {
+	merged := map[string][]map[string]string{}
+	failed := 0
+	var lastErr error
+	for _, account := range accounts {
+		snapshots, err := CollectSnapshotData(ctx, account.optFns...)
+		if err != nil {
+			log.Printf("Warning: Failed to collect AWS snapshots from account %s: %v", account.ID, err)
+			failed++
+			lastErr = err
+			continue
+		}
+		for kind, list := range snapshots {
+			items, ok := list.([]map[string]string)
+			if !ok {
+				continue
+			}
+			for _, item := range items {
+				item["AccountId"] = account.ID
+				item["AccountAlias"] = account.Alias
+			}
+			merged[kind] = append(merged[kind], items...)
+		}
+	}
+
+	if failed == len(accounts) {
+		return nil, lastErr
+	}
+
+	snapshots := map[string]interface{}{}
+	for kind, items := range merged {
+		snapshots[kind] = items
+	}
+	return snapshots, nil
+}
+
+func countFailed(accounts []AccountInfo) int {
+	failed := 0
+	for _, account := range accounts {
+		if account.Error != "" {
+			failed++
+		}
+	}
+	return failed
+}
@@ -0,0 +1,299 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	backuptypes "github.com/aws/aws-sdk-go-v2/service/backup/types"
+)
+
+// collectBackups returns the AWS Backup vaults, recovery points, plans,
+// selections and protected resources in a region, keyed like the snapshot
+// lists returned by CollectSnapshotData. Vaults, plans or selections that
+// cannot be read are logged and skipped.
+func collectBackups(ctx context.Context, cfg aws.Config) (map[string][]map[string]string, error) {
+	svc := backup.NewFromConfig(cfg)
+	backups := map[string][]map[string]string{}
+
+	vaults := backup.NewListBackupVaultsPaginator(svc, &backup.ListBackupVaultsInput{})
+	for vaults.HasMorePages() {
+		page, err := vaults.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list backup vaults, %v", err)
+		}
+		for _, vault := range page.BackupVaultList {
+			backups["BackupVaults"] = append(backups["BackupVaults"], backupVaultInfo(vault))
+
+			recoveryPoints, err := collectRecoveryPoints(ctx, svc, aws.ToString(vault.BackupVaultName))
+			if err != nil {
+				log.Printf("Warning: Unable to list AWS Backup recovery points of vault %s in %s: %v", aws.ToString(vault.BackupVaultName), cfg.Region, err)
+			}
+			backups["RecoveryPoints"] = append(backups["RecoveryPoints"], recoveryPoints...)
+		}
+	}
+
+	plans := backup.NewListBackupPlansPaginator(svc, &backup.ListBackupPlansInput{})
+	for plans.HasMorePages() {
+		page, err := plans.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list backup plans, %v", err)
+		}
+		for _, plan := range page.BackupPlansList {
+			planInfo, err := backupPlanInfo(ctx, svc, plan)
+			if err != nil {
+				log.Printf("Warning: Unable to get AWS Backup plan %s in %s: %v", aws.ToString(plan.BackupPlanName), cfg.Region, err)
+			}
+			backups["BackupPlans"] = append(backups["BackupPlans"], planInfo)
+
+			selections, err := collectBackupSelections(ctx, svc, plan)
+			if err != nil {
+				log.Printf("Warning: Unable to list AWS Backup selections of plan %s in %s: %v", aws.ToString(plan.BackupPlanName), cfg.Region, err)
+			}
+			backups["BackupSelections"] = append(backups["BackupSelections"], selections...)
+		}
+	}
+
+	protected := backup.NewListProtectedResourcesPaginator(svc, &backup.ListProtectedResourcesInput{})
+	for protected.HasMorePages() {
+		page, err := protected.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list protected resources, %v", err)
+		}
+		for _, resource := range page.Results {
+			backups["ProtectedResources"] = append(backups["ProtectedResources"], protectedResourceInfo(resource))
+		}
+	}
+
+	for _, items := range backups {
+		for _, item := range items {
+			item["Region"] = cfg.Region
+		}
+	}
+	return backups, nil
+}
+
+func backupVaultInfo(vault backuptypes.BackupVaultListMember) map[string]string {
+	vaultInfo := map[string]string{
+		"VaultName":        aws.ToString(vault.BackupVaultName),
+		"VaultType":        string(vault.VaultType),
+		"EncryptionKeyArn": aws.ToString(vault.EncryptionKeyArn),
+		"RecoveryPoints":   strconv.FormatInt(vault.NumberOfRecoveryPoints, 10),
+		"Locked":           strconv.FormatBool(aws.ToBool(vault.Locked)),
+		"LockState":        vaultLockState(vault, time.Now()),
+	}
+
+	if vault.MinRetentionDays != nil {
+		vaultInfo["MinRetentionDays"] = strconv.FormatInt(*vault.MinRetentionDays, 10)
+	}
+
+	if vault.MaxRetentionDays != nil {
+		vaultInfo["MaxRetentionDays"] = strconv.FormatInt(*vault.MaxRetentionDays, 10)
+	}
+
+	if vault.LockDate != nil {
+		vaultInfo["LockDate"] = vault.LockDate.Format(time.RFC3339)
+	}
+
+	if vault.CreationDate != nil {
+		vaultInfo["CreationDate"] = vault.CreationDate.Format(time.RFC3339)
+	}
+
+	return vaultInfo
+}
+
+// vaultLockState describes a vault lock. A lock without a lock date is in
+// governance mode and can be removed; one with a lock date is in compliance
+// mode and becomes immutable once that date has passed.
+func vaultLockState(vault backuptypes.BackupVaultListMember, now time.Time) string {
+	switch {
+	case !aws.ToBool(vault.Locked) && vault.LockDate == nil:
+		return "unlocked"
+	case vault.LockDate == nil:
+		return "governance"
+	case now.Before(*vault.LockDate):
+		return "compliance (grace period)"
+	default:
+		return "compliance"
+	}
+}
+
+func collectRecoveryPoints(ctx context.Context, svc *backup.Client, vaultName string) ([]map[string]string, error) {
+	paginator := backup.NewListRecoveryPointsByBackupVaultPaginator(svc, &backup.ListRecoveryPointsByBackupVaultInput{
+		BackupVaultName: aws.String(vaultName),
+	})
+
+	var recoveryPoints []map[string]string
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return recoveryPoints, err
+		}
+		for _, recoveryPoint := range page.RecoveryPoints {
+			recoveryPoints = append(recoveryPoints, recoveryPointInfo(recoveryPoint))
+		}
+	}
+
+	return recoveryPoints, nil
+}
+
+func recoveryPointInfo(recoveryPoint backuptypes.RecoveryPointByBackupVault) map[string]string {
+	recoveryPointInfo := map[string]string{
+		"RecoveryPointArn": aws.ToString(recoveryPoint.RecoveryPointArn),
+		"VaultName":        aws.ToString(recoveryPoint.BackupVaultName),
+		"ResourceArn":      aws.ToString(recoveryPoint.ResourceArn),
+		"ResourceType":     aws.ToString(recoveryPoint.ResourceType),
+		"ResourceName":     aws.ToString(recoveryPoint.ResourceName),
+		"Status":           string(recoveryPoint.Status),
+		"Encrypted":        strconv.FormatBool(recoveryPoint.IsEncrypted),
+	}
+
+	if recoveryPoint.BackupSizeInBytes != nil {
+		recoveryPointInfo["BackupSizeBytes"] = strconv.FormatInt(*recoveryPoint.BackupSizeInBytes, 10)
+		recoveryPointInfo["BackupSize"] = fmt.Sprintf("%.2f GiB", float64(*recoveryPoint.BackupSizeInBytes)/(1<<30))
+	}
+
+	if recoveryPoint.CreationDate != nil {
+		recoveryPointInfo["CreationTime"] = recoveryPoint.CreationDate.Format(time.RFC3339)
+	}
+
+	if recoveryPoint.CreatedBy != nil {
+		recoveryPointInfo["BackupPlanId"] = aws.ToString(recoveryPoint.CreatedBy.BackupPlanId)
+	}
+
+	if lifecycle := recoveryPoint.Lifecycle; lifecycle != nil {
+		if lifecycle.MoveToColdStorageAfterDays != nil {
+			recoveryPointInfo["MoveToColdStorageAfterDays"] = strconv.FormatInt(*lifecycle.MoveToColdStorageAfterDays, 10)
+		}
+		if lifecycle.DeleteAfterDays != nil {
+			recoveryPointInfo["DeleteAfterDays"] = strconv.FormatInt(*lifecycle.DeleteAfterDays, 10)
+		}
+	}
+
+	if lifecycle := recoveryPoint.CalculatedLifecycle; lifecycle != nil {
+		if lifecycle.MoveToColdStorageAt != nil {
+			recoveryPointInfo["MoveToColdStorageAt"] = lifecycle.MoveToColdStorageAt.Format(time.RFC3339)
+		}
+		if lifecycle.DeleteAt != nil {
+			recoveryPointInfo["DeleteAt"] = lifecycle.DeleteAt.Format(time.RFC3339)
+		}
+	}
+
+	return recoveryPointInfo
+}
+
+// backupPlanInfo describes a plan and its rules. If the rules cannot be read
+// the plan is still returned, along with the error.
+func backupPlanInfo(ctx context.Context, svc *backup.Client, plan backuptypes.BackupPlansListMember) (map[string]string, error) {
+	planInfo := map[string]string{
+		"BackupPlanId":   aws.ToString(plan.BackupPlanId),
+		"BackupPlanName": aws.ToString(plan.BackupPlanName),
+		"VersionId":      aws.ToString(plan.VersionId),
+	}
+
+	if plan.CreationDate != nil {
+		planInfo["CreationDate"] = plan.CreationDate.Format(time.RFC3339)
+	}
+
+	if plan.LastExecutionDate != nil {
+		planInfo["LastExecutionDate"] = plan.LastExecutionDate.Format(time.RFC3339)
+	}
+
+	result, err := svc.GetBackupPlan(ctx, &backup.GetBackupPlanInput{BackupPlanId: plan.BackupPlanId})
+	if err != nil {
+		return planInfo, err
+	}
+
+	var rules []string
+	for _, rule := range result.BackupPlan.Rules {
+		rules = append(rules, backupRuleSummary(rule))
+	}
+	planInfo["Rules"] = strings.Join(rules, "; ")
+
+	return planInfo, nil
+}
+
+// backupRuleSummary describes a rule as, for example,
+// "daily: cron(0 5 ? * * *) to Default, cold after 30d, delete after 365d".
+func backupRuleSummary(rule backuptypes.BackupRule) string {
+	summary := fmt.Sprintf("%s: %s to %s", aws.ToString(rule.RuleName), aws.ToString(rule.ScheduleExpression), aws.ToString(rule.TargetBackupVaultName))
+	if aws.ToBool(rule.EnableContinuousBackup) {
+		summary += ", continuous"
+	}
+	if lifecycle := rule.Lifecycle; lifecycle != nil {
+		if lifecycle.MoveToColdStorageAfterDays != nil {
+			summary += fmt.Sprintf(", cold after %dd", *lifecycle.MoveToColdStorageAfterDays)
+		}
+		if lifecycle.DeleteAfterDays != nil {
+			summary += fmt.Sprintf(", delete after %dd", *lifecycle.DeleteAfterDays)
+		}
+	}
+	return summary
+}
+
+func collectBackupSelections(ctx context.Context, svc *backup.Client, plan backuptypes.BackupPlansListMember) ([]map[string]string, error) {
+	paginator := backup.NewListBackupSelectionsPaginator(svc, &backup.ListBackupSelectionsInput{
+		BackupPlanId: plan.BackupPlanId,
+	})
+
+	var selections []map[string]string
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return selections, err
+		}
+		for _, selection := range page.BackupSelectionsList {
+			selectionInfo := map[string]string{
+				"SelectionId":    aws.ToString(selection.SelectionId),
+				"SelectionName":  aws.ToString(selection.SelectionName),
+				"BackupPlanId":   aws.ToString(plan.BackupPlanId),
+				"BackupPlanName": aws.ToString(plan.BackupPlanName),
+				"IamRoleArn":     aws.ToString(selection.IamRoleArn),
+			}
+
+			result, err := svc.GetBackupSelection(ctx, &backup.GetBackupSelectionInput{
+				BackupPlanId: plan.BackupPlanId,
+				SelectionId:  selection.SelectionId,
+			})
+			if err != nil {
+				return append(selections, selectionInfo), err
+			}
+			if details := result.BackupSelection; details != nil {
+				selectionInfo["Resources"] = strings.Join(details.Resources, ", ")
+				selectionInfo["NotResources"] = strings.Join(details.NotResources, ", ")
+				var tags []string
+				for _, condition := range details.ListOfTags {
+					tags = append(tags, fmt.Sprintf("%s=%s", aws.ToString(condition.ConditionKey), aws.ToString(condition.ConditionValue)))
+				}
+				selectionInfo["Tags"] = strings.Join(tags, ", ")
+			}
+			selections = append(selections, selectionInfo)
+		}
+	}
+
+	return selections, nil
+}
+
+func protectedResourceInfo(resource backuptypes.ProtectedResource) map[string]string {
+	resourceInfo := map[string]string{
+		"ResourceArn":          aws.ToString(resource.ResourceArn),
+		"ResourceType":         aws.ToString(resource.ResourceType),
+		"ResourceName":         aws.ToString(resource.ResourceName),
+		"LastRecoveryPointArn": aws.ToString(resource.LastRecoveryPointArn),
+	}
+
+	if vaultARN := aws.ToString(resource.LastBackupVaultArn); vaultARN != "" {
+		resourceInfo["LastBackupVault"] = vaultARN[strings.LastIndex(vaultARN, ":")+1:]
+	}
+
+	if resource.LastBackupTime != nil {
+		resourceInfo["LastBackupTime"] = resource.LastBackupTime.Format(time.RFC3339)
+	}
+
+	return resourceInfo
+}
@@ -0,0 +1,89 @@
+package aws
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/michaelcade/kollect/pkg/collector"
+)
+
+type awsCollector struct{}
+
+func init() {
+	collector.Register(awsCollector{})
+}
+
+// profiles returns the profiles listed in opts, which may be a comma
+// separated list.
+func profiles(opts collector.Options) []string {
+	var names []string
+	for _, name := range strings.Split(opts.AWSProfile, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// loadOptions returns the SDK config options for the first profile in opts,
+// if any.
+func loadOptions(opts collector.Options) []func(*config.LoadOptions) error {
+	var optFns []func(*config.LoadOptions) error
+	if names := profiles(opts); len(names) > 0 {
+		optFns = append(optFns, config.WithSharedConfigProfile(names[0]))
+	}
+	return optFns
+}
+
+func (awsCollector) Name() string {
+	return "aws"
+}
+
+func (awsCollector) CheckCredentials(ctx context.Context, opts collector.Options) (bool, error) {
+	return CheckCredentials(ctx, loadOptions(opts)...)
+}
+
+func (awsCollector) Collect(ctx context.Context, opts collector.Options) (interface{}, error) {
+	accounts, err := Accounts(ctx, profiles(opts), opts.AWSRole)
+	if err != nil {
+		return nil, err
+	}
+	return CollectAccountsData(ctx, accounts)
+}
+
+func (awsCollector) CollectSnapshots(ctx context.Context, opts collector.Options) (map[string]interface{}, error) {
+	accounts, err := Accounts(ctx, profiles(opts), opts.AWSRole)
+	if err != nil {
+		return nil, err
+	}
+	return CollectAccountsSnapshotData(ctx, accounts)
+}
+
+func (awsCollector) Identity(ctx context.Context, opts collector.Options, data interface{}) (map[string]string, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, loadOptions(opts)...)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]string{
+		"accountId": aws.ToString(identity.Account),
+		"arn":       aws.ToString(identity.Arn),
+		"region":    cfg.Region,
+	}
+	if awsData, ok := data.(AWSData); ok && len(awsData.Accounts) > 1 {
+		ids := make([]string, 0, len(awsData.Accounts))
+		for _, account := range awsData.Accounts {
+			ids = append(ids, account.AccountID)
+		}
+		result["accounts"] = strings.Join(ids, ",")
+	}
+	return result, nil
+}
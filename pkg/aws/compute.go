@@ -0,0 +1,394 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+)
+
+type EKSClusterInfo struct {
+	Name            string
+	Version         string
+	PlatformVersion string
+	Status          string
+	Endpoint        string
+	VPCID           string
+	CreatedAt       string
+	Region          string
+	Tags            map[string]string `json:",omitempty"`
+	AccountID       string
+	AccountAlias    string
+}
+
+type EKSNodeGroupInfo struct {
+	Name          string
+	Cluster       string
+	Status        string
+	Version       string
+	InstanceTypes []string
+	CapacityType  string
+	AMIType       string
+	DesiredSize   int32
+	MinSize       int32
+	MaxSize       int32
+	Region        string
+	AccountID     string
+	AccountAlias  string
+}
+
+type ECSClusterInfo struct {
+	Name                         string
+	Status                       string
+	ActiveServices               int32
+	RunningTasks                 int32
+	PendingTasks                 int32
+	RegisteredContainerInstances int32
+	CapacityProviders            []string
+	Region                       string
+	AccountID                    string
+	AccountAlias                 string
+}
+
+type ECSServiceInfo struct {
+	Name           string
+	Cluster        string
+	Status         string
+	LaunchType     string
+	TaskDefinition string
+	DesiredCount   int32
+	RunningCount   int32
+	PendingCount   int32
+	CreatedAt      string
+	Region         string
+	AccountID      string
+	AccountAlias   string
+}
+
+type ECSTaskInfo struct {
+	TaskID         string
+	Cluster        string
+	Group          string
+	TaskDefinition string
+	LastStatus     string
+	DesiredStatus  string
+	LaunchType     string
+	CPU            string
+	Memory         string
+	StartedAt      string
+	Region         string
+	AccountID      string
+	AccountAlias   string
+}
+
+type LambdaFunctionInfo struct {
+	Name          string
+	Runtime       string
+	Handler       string
+	PackageType   string
+	Architectures []string
+	MemoryMB      int32
+	TimeoutSecs   int32
+	CodeSizeBytes int64
+	LastModified  string
+	Region        string
+	AccountID     string
+	AccountAlias  string
+}
+
+// fetchEKSClusters lists the EKS clusters in a region and their managed node
+// groups. A cluster that cannot be described is left out and its error
+// returned alongside the others.
+func fetchEKSClusters(ctx context.Context, cfg aws.Config) ([]EKSClusterInfo, []EKSNodeGroupInfo, []error, error) {
+	svc := eks.NewFromConfig(cfg)
+
+	var clusters []EKSClusterInfo
+	var nodeGroups []EKSNodeGroupInfo
+	var errs []error
+	paginator := eks.NewListClustersPaginator(svc, &eks.ListClustersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("unable to list EKS clusters, %v", err)
+		}
+		for _, name := range page.Clusters {
+			result, err := svc.DescribeCluster(ctx, &eks.DescribeClusterInput{Name: aws.String(name)})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("unable to describe EKS cluster %s, %v", name, err))
+				continue
+			}
+			cluster := result.Cluster
+			info := EKSClusterInfo{
+				Name:            name,
+				Version:         aws.ToString(cluster.Version),
+				PlatformVersion: aws.ToString(cluster.PlatformVersion),
+				Status:          string(cluster.Status),
+				Endpoint:        aws.ToString(cluster.Endpoint),
+				CreatedAt:       formatTime(cluster.CreatedAt),
+				Region:          cfg.Region,
+				Tags:            cluster.Tags,
+			}
+			if cluster.ResourcesVpcConfig != nil {
+				info.VPCID = aws.ToString(cluster.ResourcesVpcConfig.VpcId)
+			}
+			clusters = append(clusters, info)
+
+			groups, err := fetchEKSNodeGroups(ctx, svc, name, cfg.Region)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			nodeGroups = append(nodeGroups, groups...)
+		}
+	}
+	return clusters, nodeGroups, errs, nil
+}
+
+func fetchEKSNodeGroups(ctx context.Context, svc *eks.Client, cluster, region string) ([]EKSNodeGroupInfo, error) {
+	var nodeGroups []EKSNodeGroupInfo
+	paginator := eks.NewListNodegroupsPaginator(svc, &eks.ListNodegroupsInput{ClusterName: aws.String(cluster)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nodeGroups, fmt.Errorf("unable to list node groups of EKS cluster %s, %v", cluster, err)
+		}
+		for _, name := range page.Nodegroups {
+			result, err := svc.DescribeNodegroup(ctx, &eks.DescribeNodegroupInput{
+				ClusterName:   aws.String(cluster),
+				NodegroupName: aws.String(name),
+			})
+			if err != nil {
+				return nodeGroups, fmt.Errorf("unable to describe node group %s of EKS cluster %s, %v", name, cluster, err)
+			}
+			group := result.Nodegroup
+			info := EKSNodeGroupInfo{
+				Name:          name,
+				Cluster:       cluster,
+				Status:        string(group.Status),
+				Version:       aws.ToString(group.Version),
+				InstanceTypes: group.InstanceTypes,
+				CapacityType:  string(group.CapacityType),
+				AMIType:       string(group.AmiType),
+				Region:        region,
+			}
+			if scaling := group.ScalingConfig; scaling != nil {
+				info.DesiredSize = aws.ToInt32(scaling.DesiredSize)
+				info.MinSize = aws.ToInt32(scaling.MinSize)
+				info.MaxSize = aws.ToInt32(scaling.MaxSize)
+			}
+			nodeGroups = append(nodeGroups, info)
+		}
+	}
+	return nodeGroups, nil
+}
+
+// ECS describe calls accept a limited number of ARNs per request.
+const (
+	ecsDescribeClustersLimit = 100
+	ecsDescribeServicesLimit = 10
+	ecsDescribeTasksLimit    = 100
+)
+
+// fetchECSClusters lists the ECS clusters in a region with their services and
+// running tasks. A cluster whose services or tasks cannot be read keeps what
+// was read and its error is returned alongside the others.
+func fetchECSClusters(ctx context.Context, cfg aws.Config) ([]ECSClusterInfo, []ECSServiceInfo, []ECSTaskInfo, []error, error) {
+	svc := ecs.NewFromConfig(cfg)
+
+	var arns []string
+	paginator := ecs.NewListClustersPaginator(svc, &ecs.ListClustersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, nil, nil, nil, fmt.Errorf("unable to list ECS clusters, %v", err)
+		}
+		arns = append(arns, page.ClusterArns...)
+	}
+
+	var clusters []ECSClusterInfo
+	var services []ECSServiceInfo
+	var tasks []ECSTaskInfo
+	var errs []error
+	for _, batch := range chunk(arns, ecsDescribeClustersLimit) {
+		result, err := svc.DescribeClusters(ctx, &ecs.DescribeClustersInput{Clusters: batch})
+		if err != nil {
+			return nil, nil, nil, nil, fmt.Errorf("unable to describe ECS clusters, %v", err)
+		}
+		for _, cluster := range result.Clusters {
+			name := aws.ToString(cluster.ClusterName)
+			clusters = append(clusters, ECSClusterInfo{
+				Name:                         name,
+				Status:                       aws.ToString(cluster.Status),
+				ActiveServices:               cluster.ActiveServicesCount,
+				RunningTasks:                 cluster.RunningTasksCount,
+				PendingTasks:                 cluster.PendingTasksCount,
+				RegisteredContainerInstances: cluster.RegisteredContainerInstancesCount,
+				CapacityProviders:            cluster.CapacityProviders,
+				Region:                       cfg.Region,
+			})
+
+			clusterServices, err := fetchECSServices(ctx, svc, cluster.ClusterArn, name, cfg.Region)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			services = append(services, clusterServices...)
+
+			clusterTasks, err := fetchECSTasks(ctx, svc, cluster.ClusterArn, name, cfg.Region)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			tasks = append(tasks, clusterTasks...)
+		}
+	}
+	return clusters, services, tasks, errs, nil
+}
+
+func fetchECSServices(ctx context.Context, svc *ecs.Client, clusterARN *string, cluster, region string) ([]ECSServiceInfo, error) {
+	var arns []string
+	paginator := ecs.NewListServicesPaginator(svc, &ecs.ListServicesInput{Cluster: clusterARN})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list services of ECS cluster %s, %v", cluster, err)
+		}
+		arns = append(arns, page.ServiceArns...)
+	}
+
+	var services []ECSServiceInfo
+	for _, batch := range chunk(arns, ecsDescribeServicesLimit) {
+		result, err := svc.DescribeServices(ctx, &ecs.DescribeServicesInput{Cluster: clusterARN, Services: batch})
+		if err != nil {
+			return services, fmt.Errorf("unable to describe services of ECS cluster %s, %v", cluster, err)
+		}
+		for _, service := range result.Services {
+			services = append(services, ECSServiceInfo{
+				Name:           aws.ToString(service.ServiceName),
+				Cluster:        cluster,
+				Status:         aws.ToString(service.Status),
+				LaunchType:     string(service.LaunchType),
+				TaskDefinition: arnResource(aws.ToString(service.TaskDefinition)),
+				DesiredCount:   service.DesiredCount,
+				RunningCount:   service.RunningCount,
+				PendingCount:   service.PendingCount,
+				CreatedAt:      formatTime(service.CreatedAt),
+				Region:         region,
+			})
+		}
+	}
+	return services, nil
+}
+
+func fetchECSTasks(ctx context.Context, svc *ecs.Client, clusterARN *string, cluster, region string) ([]ECSTaskInfo, error) {
+	var arns []string
+	paginator := ecs.NewListTasksPaginator(svc, &ecs.ListTasksInput{Cluster: clusterARN})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list tasks of ECS cluster %s, %v", cluster, err)
+		}
+		arns = append(arns, page.TaskArns...)
+	}
+
+	var tasks []ECSTaskInfo
+	for _, batch := range chunk(arns, ecsDescribeTasksLimit) {
+		result, err := svc.DescribeTasks(ctx, &ecs.DescribeTasksInput{Cluster: clusterARN, Tasks: batch})
+		if err != nil {
+			return tasks, fmt.Errorf("unable to describe tasks of ECS cluster %s, %v", cluster, err)
+		}
+		for _, task := range result.Tasks {
+			tasks = append(tasks, ecsTaskInfo(task, cluster, region))
+		}
+	}
+	return tasks, nil
+}
+
+func ecsTaskInfo(task ecstypes.Task, cluster, region string) ECSTaskInfo {
+	taskID := aws.ToString(task.TaskArn)
+	if i := strings.LastIndex(taskID, "/"); i >= 0 {
+		taskID = taskID[i+1:]
+	}
+	return ECSTaskInfo{
+		TaskID:         taskID,
+		Cluster:        cluster,
+		Group:          aws.ToString(task.Group),
+		TaskDefinition: arnResource(aws.ToString(task.TaskDefinitionArn)),
+		LastStatus:     aws.ToString(task.LastStatus),
+		DesiredStatus:  aws.ToString(task.DesiredStatus),
+		LaunchType:     string(task.LaunchType),
+		CPU:            aws.ToString(task.Cpu),
+		Memory:         aws.ToString(task.Memory),
+		StartedAt:      formatTime(task.StartedAt),
+		Region:         region,
+	}
+}
+
+func fetchLambdaFunctions(ctx context.Context, cfg aws.Config) ([]LambdaFunctionInfo, error) {
+	var functions []LambdaFunctionInfo
+	paginator := lambda.NewListFunctionsPaginator(lambda.NewFromConfig(cfg), &lambda.ListFunctionsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list Lambda functions, %v", err)
+		}
+		for _, function := range page.Functions {
+			info := LambdaFunctionInfo{
+				Name:          aws.ToString(function.FunctionName),
+				Runtime:       string(function.Runtime),
+				Handler:       aws.ToString(function.Handler),
+				PackageType:   string(function.PackageType),
+				MemoryMB:      aws.ToInt32(function.MemorySize),
+				TimeoutSecs:   aws.ToInt32(function.Timeout),
+				CodeSizeBytes: function.CodeSize,
+				LastModified:  lambdaTime(aws.ToString(function.LastModified)),
+				Region:        cfg.Region,
+			}
+			for _, arch := range function.Architectures {
+				info.Architectures = append(info.Architectures, string(arch))
+			}
+			functions = append(functions, info)
+		}
+	}
+	return functions, nil
+}
+
+// arnResource returns the resource part of an ARN after the first "/", such
+// as "web:12" for a task definition, or the ARN unchanged if it has none.
+func arnResource(arn string) string {
+	if i := strings.Index(arn, "/"); i >= 0 {
+		return arn[i+1:]
+	}
+	return arn
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// lambdaTime converts Lambda's LastModified, such as
+// "2024-05-01T10:00:00.000+0000", to RFC 3339.
+func lambdaTime(value string) string {
+	t, err := time.Parse("2006-01-02T15:04:05.999-0700", value)
+	if err != nil {
+		return value
+	}
+	return t.Format(time.RFC3339)
+}
+
+func chunk(items []string, size int) [][]string {
+	var chunks [][]string
+	for size < len(items) {
+		items, chunks = items[size:], append(chunks, items[:size])
+	}
+	if len(items) > 0 {
+		chunks = append(chunks, items)
+	}
+	return chunks
+}
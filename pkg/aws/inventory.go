@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -11,53 +14,149 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
 )
 
 type EC2InstanceInfo struct {
-	Name       string
-	InstanceID string
-	Type       string
-	State      string
-	Region     string
+	Name             string
+	InstanceID       string
+	Type             string
+	State            string
+	Region           string
+	AvailabilityZone string
+	PrivateIP        string
+	PublicIP         string
+	VPCID            string
+	SubnetID         string
+	ImageID          string
+	LaunchTime       string
+	Platform         string
+	Lifecycle        string
+	KeyName          string
+	SecurityGroups   []string
+	Volumes          []AttachedVolumeInfo
+	Tags             map[string]string
+	AccountID        string
+	AccountAlias     string
 }
 
-type S3BucketInfo struct {
-	Name      string
-	Immutable bool
-	Region    string
+// AttachedVolumeInfo is an EBS volume attached to an EC2 instance.
+type AttachedVolumeInfo struct {
+	VolumeID            string
+	DeviceName          string
+	SizeGiB             int32
+	VolumeType          string
+	IOPS                int32
+	Encrypted           bool
+	DeleteOnTermination bool
+}
+
+// EBSVolumeInfo is an EBS volume. Unattached volumes are still billed and
+// are usually left over from deleted instances.
+type EBSVolumeInfo struct {
+	VolumeID         string
+	Name             string
+	SizeGiB          int32
+	VolumeType       string
+	IOPS             int32
+	ThroughputMiBps  int32
+	Encrypted        bool
+	State            string
+	Unattached       bool
+	AttachedTo       []string
+	AvailabilityZone string
+	Region           string
+	SnapshotID       string
+	CreateTime       string
+	Tags             map[string]string
+	AccountID        string
+	AccountAlias     string
+}
+
+// OrphanedSnapshotInfo is an EBS snapshot whose source volume no longer
+// exists.
+type OrphanedSnapshotInfo struct {
+	SnapshotID   string
+	VolumeID     string
+	SizeGiB      int32
+	StartTime    string
+	Description  string
+	Region       string
+	AccountID    string
+	AccountAlias string
 }
 
 type RDSInstanceInfo struct {
-	InstanceID string
-	Engine     string
-	Status     string
-	Region     string
+	InstanceID   string
+	Engine       string
+	Status       string
+	Region       string
+	AccountID    string
+	AccountAlias string
 }
 
 type DynamoDBTableInfo struct {
-	TableName string
-	Status    string
-	Region    string
+	TableName    string
+	Status       string
+	Region       string
+	AccountID    string
+	AccountAlias string
 }
 
-type VPCInfo struct {
-	VPCID  string
-	State  string
-	Region string
+type AWSData struct {
+	EC2Instances      []EC2InstanceInfo
+	S3Buckets         []S3BucketInfo
+	RDSInstances      []RDSInstanceInfo
+	DynamoDBTables    []DynamoDBTableInfo
+	VPCs              []VPCInfo
+	Subnets           []SubnetInfo          `json:",omitempty"`
+	RouteTables       []RouteTableInfo      `json:",omitempty"`
+	InternetGateways  []InternetGatewayInfo `json:",omitempty"`
+	NATGateways       []NATGatewayInfo      `json:",omitempty"`
+	ElasticIPs        []ElasticIPInfo       `json:",omitempty"`
+	SecurityGroups    []SecurityGroupInfo   `json:",omitempty"`
+	LoadBalancers     []LoadBalancerInfo    `json:",omitempty"`
+	EBSVolumes        []EBSVolumeInfo
+	EKSClusters       []EKSClusterInfo       `json:",omitempty"`
+	EKSNodeGroups     []EKSNodeGroupInfo     `json:",omitempty"`
+	ECSClusters       []ECSClusterInfo       `json:",omitempty"`
+	ECSServices       []ECSServiceInfo       `json:",omitempty"`
+	ECSTasks          []ECSTaskInfo          `json:",omitempty"`
+	LambdaFunctions   []LambdaFunctionInfo   `json:",omitempty"`
+	OrphanedSnapshots []OrphanedSnapshotInfo `json:",omitempty"`
+	Regions           []RegionSummary        `json:",omitempty"`
+	Accounts          []AccountInfo          `json:",omitempty"`
+	Errors            []CollectionError      `json:",omitempty"`
 }
 
-type AWSData struct {
-	EC2Instances   []EC2InstanceInfo
-	S3Buckets      []S3BucketInfo
-	RDSInstances   []RDSInstanceInfo
-	DynamoDBTables []DynamoDBTableInfo
-	VPCs           []VPCInfo
+// RegionSummary counts the resources collected in one region, so a
+// complete inventory can be checked against the console.
+type RegionSummary struct {
+	AccountID       string `json:",omitempty"`
+	Region          string
+	EC2Instances    int
+	S3Buckets       int
+	RDSInstances    int
+	DynamoDBTables  int
+	VPCs            int
+	EBSVolumes      int
+	EKSClusters     int
+	ECSClusters     int
+	LambdaFunctions int
 }
 
-func CheckCredentials(ctx context.Context) (bool, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
+// withCredentials returns optFns with the credentials provider overridden,
+// without modifying the caller's slice.
+func withCredentials(optFns []func(*config.LoadOptions) error, provider aws.CredentialsProvider) []func(*config.LoadOptions) error {
+	credOptFns := make([]func(*config.LoadOptions) error, 0, len(optFns)+1)
+	credOptFns = append(credOptFns, optFns...)
+	return append(credOptFns, config.WithCredentialsProvider(provider))
+}
+
+func CheckCredentials(ctx context.Context, optFns ...func(*config.LoadOptions) error) (bool, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, optFns...)
 	if err != nil {
 		return false, err
 	}
@@ -68,291 +167,680 @@ func CheckCredentials(ctx context.Context) (bool, error) {
 	return err == nil, err
 }
 
-func FetchEC2Instances(ctx context.Context) ([]EC2InstanceInfo, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("unable to load SDK config, %v", err)
-	}
+// maxConcurrency bounds how many region and service calls run at once.
+const maxConcurrency = 8
 
-	ec2Client := ec2.NewFromConfig(cfg)
-	regionsOutput, err := ec2Client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
+// CollectionError records a region and service that could not be collected.
+// S3 is listed under the "global" region.
+type CollectionError struct {
+	AccountID string `json:",omitempty"`
+	Region    string
+	Service   string
+	Error     string
+}
+
+// enabledRegions returns the regions enabled for the account, which leaves
+// out opt-in regions that have not been enabled.
+func enabledRegions(ctx context.Context, cfg aws.Config) ([]string, error) {
+	result, err := ec2.NewFromConfig(cfg).DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
 	if err != nil {
 		return nil, fmt.Errorf("unable to describe regions, %v", err)
 	}
 
-	var allInstances []EC2InstanceInfo
-	for _, region := range regionsOutput.Regions {
-		regionCfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(*region.RegionName))
-		if err != nil {
-			return nil, fmt.Errorf("unable to load SDK config for region %s, %v", *region.RegionName, err)
-		}
+	regions := make([]string, 0, len(result.Regions))
+	for _, region := range result.Regions {
+		regions = append(regions, aws.ToString(region.RegionName))
+	}
+	sort.Strings(regions)
+	return regions, nil
+}
+
+// fetchEC2Instances lists the instances in a region with their attached
+// volumes. If the volumes cannot be described, the instances are still
+// returned with volume IDs only, along with the error.
+func fetchEC2Instances(ctx context.Context, cfg aws.Config) ([]EC2InstanceInfo, []error, error) {
+	client := ec2.NewFromConfig(cfg)
 
-		regionEc2Client := ec2.NewFromConfig(regionCfg)
-		result, err := regionEc2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{})
+	var instances []EC2InstanceInfo
+	paginator := ec2.NewDescribeInstancesPaginator(client, &ec2.DescribeInstancesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("unable to describe instances in region %s, %v", *region.RegionName, err)
+			return nil, nil, fmt.Errorf("unable to describe instances, %v", err)
 		}
-
-		for _, reservation := range result.Reservations {
+		for _, reservation := range page.Reservations {
 			for _, instance := range reservation.Instances {
-				allInstances = append(allInstances, EC2InstanceInfo{
-					Name:       aws.ToString(instance.KeyName),
-					InstanceID: aws.ToString(instance.InstanceId),
-					Type:       string(instance.InstanceType),
-					State:      string(instance.State.Name),
-					Region:     *region.RegionName,
-				})
+				info := ec2InstanceInfo(instance)
+				info.Region = cfg.Region
+				instances = append(instances, info)
 			}
 		}
 	}
 
-	return allInstances, nil
-}
+	if len(instances) == 0 {
+		return instances, nil, nil
+	}
 
-func FetchS3Buckets(ctx context.Context) ([]S3BucketInfo, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
+	volumes, err := fetchAttachedVolumes(ctx, client)
 	if err != nil {
-		return nil, fmt.Errorf("unable to load SDK config, %v", err)
+		return instances, []error{err}, nil
 	}
+	for i := range instances {
+		for j, attached := range instances[i].Volumes {
+			volume, ok := volumes[attached.VolumeID]
+			if !ok {
+				continue
+			}
+			attached.SizeGiB = aws.ToInt32(volume.Size)
+			attached.VolumeType = string(volume.VolumeType)
+			attached.IOPS = aws.ToInt32(volume.Iops)
+			attached.Encrypted = aws.ToBool(volume.Encrypted)
+			instances[i].Volumes[j] = attached
+		}
+	}
+	return instances, nil, nil
+}
 
-	svc := s3.NewFromConfig(cfg)
-	result, err := svc.ListBuckets(ctx, &s3.ListBucketsInput{})
-	if err != nil {
-		return nil, fmt.Errorf("unable to list buckets, %v", err)
+func ec2InstanceInfo(instance ec2types.Instance) EC2InstanceInfo {
+	info := EC2InstanceInfo{
+		InstanceID: aws.ToString(instance.InstanceId),
+		Type:       string(instance.InstanceType),
+		PrivateIP:  aws.ToString(instance.PrivateIpAddress),
+		PublicIP:   aws.ToString(instance.PublicIpAddress),
+		VPCID:      aws.ToString(instance.VpcId),
+		SubnetID:   aws.ToString(instance.SubnetId),
+		ImageID:    aws.ToString(instance.ImageId),
+		Platform:   aws.ToString(instance.PlatformDetails),
+		Lifecycle:  "on-demand",
+		KeyName:    aws.ToString(instance.KeyName),
+		Tags:       map[string]string{},
 	}
 
-	var buckets []S3BucketInfo
-	for _, bucket := range result.Buckets {
-		region, err := svc.GetBucketLocation(ctx, &s3.GetBucketLocationInput{
-			Bucket: bucket.Name,
+	if instance.State != nil {
+		info.State = string(instance.State.Name)
+	}
+	if instance.Placement != nil {
+		info.AvailabilityZone = aws.ToString(instance.Placement.AvailabilityZone)
+	}
+	if instance.LaunchTime != nil {
+		info.LaunchTime = instance.LaunchTime.Format(time.RFC3339)
+	}
+	if instance.InstanceLifecycle != "" {
+		info.Lifecycle = string(instance.InstanceLifecycle)
+	}
+
+	for _, tag := range instance.Tags {
+		info.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	info.Name = info.Tags["Name"]
+
+	for _, group := range instance.SecurityGroups {
+		info.SecurityGroups = append(info.SecurityGroups, aws.ToString(group.GroupId))
+	}
+
+	for _, mapping := range instance.BlockDeviceMappings {
+		if mapping.Ebs == nil {
+			continue
+		}
+		info.Volumes = append(info.Volumes, AttachedVolumeInfo{
+			VolumeID:            aws.ToString(mapping.Ebs.VolumeId),
+			DeviceName:          aws.ToString(mapping.DeviceName),
+			DeleteOnTermination: aws.ToBool(mapping.Ebs.DeleteOnTermination),
 		})
+	}
+
+	return info
+}
+
+// fetchAttachedVolumes returns every attached EBS volume in a region by ID.
+func fetchAttachedVolumes(ctx context.Context, client *ec2.Client) (map[string]ec2types.Volume, error) {
+	volumes := map[string]ec2types.Volume{}
+	paginator := ec2.NewDescribeVolumesPaginator(client, &ec2.DescribeVolumesInput{
+		Filters: []ec2types.Filter{{Name: aws.String("attachment.status"), Values: []string{"attached"}}},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("unable to get bucket location for %s, %v", aws.ToString(bucket.Name), err)
+			return nil, fmt.Errorf("unable to describe volumes, %v", err)
 		}
-		objectLockConfig, err := svc.GetObjectLockConfiguration(ctx, &s3.GetObjectLockConfigurationInput{
-			Bucket: bucket.Name,
-		})
-		immutable := false
-		if err == nil && objectLockConfig.ObjectLockConfiguration != nil {
-			immutable = objectLockConfig.ObjectLockConfiguration.ObjectLockEnabled == "Enabled"
+		for _, volume := range page.Volumes {
+			volumes[aws.ToString(volume.VolumeId)] = volume
 		}
-		buckets = append(buckets, S3BucketInfo{
-			Name:      aws.ToString(bucket.Name),
-			Region:    string(region.LocationConstraint),
-			Immutable: immutable,
-		})
 	}
-
-	return buckets, nil
+	return volumes, nil
 }
 
-func FetchRDSInstances(ctx context.Context) ([]RDSInstanceInfo, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("unable to load SDK config, %v", err)
+// fetchEBSVolumes lists every volume in a region and the snapshots whose
+// source volume has been deleted. Snapshots that cannot be listed are
+// returned as an error alongside the volumes.
+func fetchEBSVolumes(ctx context.Context, cfg aws.Config) ([]EBSVolumeInfo, []OrphanedSnapshotInfo, []error, error) {
+	var volumes []EBSVolumeInfo
+	exists := map[string]bool{}
+	paginator := ec2.NewDescribeVolumesPaginator(ec2.NewFromConfig(cfg), &ec2.DescribeVolumesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("unable to describe volumes, %v", err)
+		}
+		for _, volume := range page.Volumes {
+			info := ebsVolumeInfo(volume)
+			info.Region = cfg.Region
+			exists[info.VolumeID] = true
+			volumes = append(volumes, info)
+		}
 	}
 
-	ec2Client := ec2.NewFromConfig(cfg)
-	regionsOutput, err := ec2Client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
+	snapshots, err := collectEBSSnapshots(ctx, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("unable to describe regions, %v", err)
+		return volumes, nil, []error{fmt.Errorf("unable to describe snapshots, %v", err)}, nil
 	}
 
-	var allInstances []RDSInstanceInfo
-	for _, region := range regionsOutput.Regions {
-		regionCfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(*region.RegionName))
-		if err != nil {
-			return nil, fmt.Errorf("unable to load SDK config for region %s, %v", *region.RegionName, err)
+	var orphaned []OrphanedSnapshotInfo
+	for _, snapshot := range snapshots {
+		if !volumeDeleted(snapshot["VolumeId"], exists) {
+			continue
 		}
+		size, _ := strconv.Atoi(strings.TrimSuffix(snapshot["VolumeSize"], " GiB"))
+		orphaned = append(orphaned, OrphanedSnapshotInfo{
+			SnapshotID:  snapshot["SnapshotId"],
+			VolumeID:    snapshot["VolumeId"],
+			SizeGiB:     int32(size),
+			StartTime:   snapshot["StartTime"],
+			Description: snapshot["Description"],
+			Region:      cfg.Region,
+		})
+	}
+	return volumes, orphaned, nil, nil
+}
+
+// volumeDeleted reports whether a snapshot's source volume is gone. Copied
+// and imported snapshots use the placeholder vol-ffffffff and are never
+// orphaned.
+func volumeDeleted(volumeID string, exists map[string]bool) bool {
+	return volumeID != "" && volumeID != "vol-ffffffff" && !exists[volumeID]
+}
 
-		regionRdsClient := rds.NewFromConfig(regionCfg)
-		result, err := regionRdsClient.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{})
+// volumeIDs returns the IDs of every EBS volume in a region.
+func volumeIDs(ctx context.Context, cfg aws.Config) (map[string]bool, error) {
+	exists := map[string]bool{}
+	paginator := ec2.NewDescribeVolumesPaginator(ec2.NewFromConfig(cfg), &ec2.DescribeVolumesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("unable to describe DB instances in region %s, %v", *region.RegionName, err)
+			return nil, err
 		}
-
-		for _, instance := range result.DBInstances {
-			allInstances = append(allInstances, RDSInstanceInfo{
-				InstanceID: aws.ToString(instance.DBInstanceIdentifier),
-				Engine:     aws.ToString(instance.Engine),
-				Status:     aws.ToString(instance.DBInstanceStatus),
-				Region:     *region.RegionName,
-			})
+		for _, volume := range page.Volumes {
+			exists[aws.ToString(volume.VolumeId)] = true
 		}
 	}
-
-	return allInstances, nil
+	return exists, nil
 }
 
-func FetchDynamoDBTables(ctx context.Context) ([]DynamoDBTableInfo, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("unable to load SDK config, %v", err)
+func ebsVolumeInfo(volume ec2types.Volume) EBSVolumeInfo {
+	info := EBSVolumeInfo{
+		VolumeID:         aws.ToString(volume.VolumeId),
+		SizeGiB:          aws.ToInt32(volume.Size),
+		VolumeType:       string(volume.VolumeType),
+		IOPS:             aws.ToInt32(volume.Iops),
+		ThroughputMiBps:  aws.ToInt32(volume.Throughput),
+		Encrypted:        aws.ToBool(volume.Encrypted),
+		State:            string(volume.State),
+		Unattached:       volume.State == ec2types.VolumeStateAvailable,
+		AvailabilityZone: aws.ToString(volume.AvailabilityZone),
+		SnapshotID:       aws.ToString(volume.SnapshotId),
+		Tags:             map[string]string{},
 	}
-
-	ec2Client := ec2.NewFromConfig(cfg)
-	regionsOutput, err := ec2Client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
-	if err != nil {
-		return nil, fmt.Errorf("unable to describe regions, %v", err)
+	if volume.CreateTime != nil {
+		info.CreateTime = volume.CreateTime.Format(time.RFC3339)
+	}
+	for _, attachment := range volume.Attachments {
+		info.AttachedTo = append(info.AttachedTo, aws.ToString(attachment.InstanceId))
 	}
+	for _, tag := range volume.Tags {
+		info.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	info.Name = info.Tags["Name"]
+	return info
+}
 
-	var allTables []DynamoDBTableInfo
-	for _, region := range regionsOutput.Regions {
-		regionCfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(*region.RegionName))
+func fetchRDSInstances(ctx context.Context, cfg aws.Config) ([]RDSInstanceInfo, error) {
+	var instances []RDSInstanceInfo
+	paginator := rds.NewDescribeDBInstancesPaginator(rds.NewFromConfig(cfg), &rds.DescribeDBInstancesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("unable to load SDK config for region %s, %v", *region.RegionName, err)
+			return nil, fmt.Errorf("unable to describe DB instances, %v", err)
+		}
+		for _, instance := range page.DBInstances {
+			instances = append(instances, RDSInstanceInfo{
+				InstanceID: aws.ToString(instance.DBInstanceIdentifier),
+				Engine:     aws.ToString(instance.Engine),
+				Status:     aws.ToString(instance.DBInstanceStatus),
+				Region:     cfg.Region,
+			})
 		}
+	}
+	return instances, nil
+}
 
-		svc := dynamodb.NewFromConfig(regionCfg)
-		result, err := svc.ListTables(ctx, &dynamodb.ListTablesInput{})
+// fetchDynamoDBTables lists the tables in a region. A table that cannot be
+// described is left out and its error returned alongside the others.
+func fetchDynamoDBTables(ctx context.Context, cfg aws.Config) ([]DynamoDBTableInfo, []error, error) {
+	svc := dynamodb.NewFromConfig(cfg)
+
+	var tables []DynamoDBTableInfo
+	var errs []error
+	paginator := dynamodb.NewListTablesPaginator(svc, &dynamodb.ListTablesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("unable to list tables in region %s, %v", *region.RegionName, err)
+			return nil, nil, fmt.Errorf("unable to list tables, %v", err)
 		}
-
-		for _, tableName := range result.TableNames {
+		for _, tableName := range page.TableNames {
 			describeResult, err := svc.DescribeTable(ctx, &dynamodb.DescribeTableInput{
-				TableName: &tableName,
+				TableName: aws.String(tableName),
 			})
 			if err != nil {
-				return nil, fmt.Errorf("unable to describe table %s in region %s, %v", tableName, *region.RegionName, err)
+				errs = append(errs, fmt.Errorf("unable to describe table %s, %v", tableName, err))
+				continue
 			}
-			allTables = append(allTables, DynamoDBTableInfo{
+			tables = append(tables, DynamoDBTableInfo{
 				TableName: tableName,
 				Status:    string(describeResult.Table.TableStatus),
-				Region:    *region.RegionName,
+				Region:    cfg.Region,
 			})
 		}
 	}
-
-	return allTables, nil
+	return tables, errs, nil
 }
 
-func FetchVPCs(ctx context.Context) ([]VPCInfo, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
+// CollectAWSData collects every service in every enabled region, running up
+// to maxConcurrency calls at once. A region or service that fails, for
+// example because of an SCP deny, is recorded in Errors and the rest are
+// still returned. It only fails when nothing could be collected.
+func CollectAWSData(ctx context.Context, optFns ...func(*config.LoadOptions) error) (AWSData, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, optFns...)
 	if err != nil {
-		return nil, fmt.Errorf("unable to load SDK config, %v", err)
+		return AWSData{}, fmt.Errorf("unable to load SDK config, %v", err)
 	}
 
-	ec2Client := ec2.NewFromConfig(cfg)
-	regionsOutput, err := ec2Client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
+	regions, err := enabledRegions(ctx, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("unable to describe regions, %v", err)
+		return AWSData{}, err
 	}
 
-	var allVPCs []VPCInfo
-	for _, region := range regionsOutput.Regions {
-		regionCfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(*region.RegionName))
-		if err != nil {
-			return nil, fmt.Errorf("unable to load SDK config for region %s, %v", *region.RegionName, err)
-		}
+	var data AWSData
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrency)
+	tasks, failed := 0, 0
 
-		regionEc2Client := ec2.NewFromConfig(regionCfg)
-		result, err := regionEc2Client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{})
-		if err != nil {
-			return nil, fmt.Errorf("unable to describe VPCs in region %s, %v", *region.RegionName, err)
-		}
+	record := func(region, service string, err error) {
+		log.Printf("Warning: Unable to collect AWS %s in %s: %v", service, region, err)
+		data.Errors = append(data.Errors, CollectionError{Region: region, Service: service, Error: err.Error()})
+	}
 
-		for _, vpc := range result.Vpcs {
-			allVPCs = append(allVPCs, VPCInfo{
-				VPCID:  aws.ToString(vpc.VpcId),
-				State:  string(vpc.State),
-				Region: *region.RegionName,
-			})
-		}
+	// run calls fetch in the background. fetch returns a function that adds
+	// its results to data, which is called with the mutex held.
+	run := func(region, service string, fetch func() (func(), error)) {
+		tasks++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			add, err := fetch()
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				failed++
+				record(region, service, err)
+				return
+			}
+			add()
+		}()
 	}
 
-	return allVPCs, nil
-}
+	run("global", "s3", func() (func(), error) {
+		buckets, errs, err := fetchS3Buckets(ctx, cfg)
+		return func() {
+			data.S3Buckets = buckets
+			for _, err := range errs {
+				record("global", "s3", err)
+			}
+		}, err
+	})
 
-func CollectAWSData(ctx context.Context) (AWSData, error) {
-	var data AWSData
-	var err error
+	for _, region := range regions {
+		regionCfg := cfg.Copy()
+		regionCfg.Region = region
 
-	data.EC2Instances, err = FetchEC2Instances(ctx)
-	if err != nil {
-		return AWSData{}, err
+		run(region, "ec2", func() (func(), error) {
+			instances, errs, err := fetchEC2Instances(ctx, regionCfg)
+			return func() {
+				data.EC2Instances = append(data.EC2Instances, instances...)
+				for _, err := range errs {
+					record(regionCfg.Region, "ec2", err)
+				}
+			}, err
+		})
+		run(region, "rds", func() (func(), error) {
+			instances, err := fetchRDSInstances(ctx, regionCfg)
+			return func() { data.RDSInstances = append(data.RDSInstances, instances...) }, err
+		})
+		run(region, "dynamodb", func() (func(), error) {
+			tables, errs, err := fetchDynamoDBTables(ctx, regionCfg)
+			return func() {
+				data.DynamoDBTables = append(data.DynamoDBTables, tables...)
+				for _, err := range errs {
+					record(regionCfg.Region, "dynamodb", err)
+				}
+			}, err
+		})
+		run(region, "ebs", func() (func(), error) {
+			volumes, orphaned, errs, err := fetchEBSVolumes(ctx, regionCfg)
+			return func() {
+				data.EBSVolumes = append(data.EBSVolumes, volumes...)
+				data.OrphanedSnapshots = append(data.OrphanedSnapshots, orphaned...)
+				for _, err := range errs {
+					record(regionCfg.Region, "ebs", err)
+				}
+			}, err
+		})
+		run(region, "vpc", func() (func(), error) {
+			network, errs, err := fetchNetwork(ctx, regionCfg)
+			return func() {
+				data.VPCs = append(data.VPCs, network.vpcs...)
+				data.Subnets = append(data.Subnets, network.subnets...)
+				data.RouteTables = append(data.RouteTables, network.routeTables...)
+				data.InternetGateways = append(data.InternetGateways, network.internetGateways...)
+				data.NATGateways = append(data.NATGateways, network.natGateways...)
+				data.ElasticIPs = append(data.ElasticIPs, network.elasticIPs...)
+				data.SecurityGroups = append(data.SecurityGroups, network.securityGroups...)
+				for _, err := range errs {
+					record(regionCfg.Region, "vpc", err)
+				}
+			}, err
+		})
+		run(region, "elb", func() (func(), error) {
+			loadBalancers, errs, err := fetchLoadBalancers(ctx, regionCfg)
+			return func() {
+				data.LoadBalancers = append(data.LoadBalancers, loadBalancers...)
+				for _, err := range errs {
+					record(regionCfg.Region, "elb", err)
+				}
+			}, err
+		})
+		run(region, "eks", func() (func(), error) {
+			clusters, nodeGroups, errs, err := fetchEKSClusters(ctx, regionCfg)
+			return func() {
+				data.EKSClusters = append(data.EKSClusters, clusters...)
+				data.EKSNodeGroups = append(data.EKSNodeGroups, nodeGroups...)
+				for _, err := range errs {
+					record(regionCfg.Region, "eks", err)
+				}
+			}, err
+		})
+		run(region, "ecs", func() (func(), error) {
+			clusters, services, tasks, errs, err := fetchECSClusters(ctx, regionCfg)
+			return func() {
+				data.ECSClusters = append(data.ECSClusters, clusters...)
+				data.ECSServices = append(data.ECSServices, services...)
+				data.ECSTasks = append(data.ECSTasks, tasks...)
+				for _, err := range errs {
+					record(regionCfg.Region, "ecs", err)
+				}
+			}, err
+		})
+		run(region, "lambda", func() (func(), error) {
+			functions, err := fetchLambdaFunctions(ctx, regionCfg)
+			return func() { data.LambdaFunctions = append(data.LambdaFunctions, functions...) }, err
+		})
 	}
 
-	data.S3Buckets, err = FetchS3Buckets(ctx)
-	if err != nil {
-		return AWSData{}, err
+	wg.Wait()
+
+	if failed == tasks {
+		return AWSData{}, fmt.Errorf("unable to collect any AWS region or service, last error: %s", data.Errors[len(data.Errors)-1].Error)
 	}
 
-	data.RDSInstances, err = FetchRDSInstances(ctx)
-	if err != nil {
-		return AWSData{}, err
+	data.sort()
+	data.Regions = summarizeRegions(regions, data)
+	for _, summary := range data.Regions {
+		if summary.total() > 0 {
+			log.Printf("Found %d EC2 instances, %d S3 buckets, %d RDS instances, %d DynamoDB tables, %d VPCs, %d EBS volumes, %d EKS clusters, %d ECS clusters and %d Lambda functions in %s",
+				summary.EC2Instances, summary.S3Buckets, summary.RDSInstances, summary.DynamoDBTables, summary.VPCs, summary.EBSVolumes,
+				summary.EKSClusters, summary.ECSClusters, summary.LambdaFunctions, summary.Region)
+		}
 	}
+	return data, nil
+}
 
-	data.DynamoDBTables, err = FetchDynamoDBTables(ctx)
-	if err != nil {
-		return AWSData{}, err
+// summarizeRegions counts the resources in data by region. Buckets without a
+// location constraint are in us-east-1.
+func summarizeRegions(regions []string, data AWSData) []RegionSummary {
+	summaries := make([]RegionSummary, len(regions))
+	index := map[string]int{}
+	for i, region := range regions {
+		summaries[i].Region = region
+		index[region] = i
+	}
+	summary := func(region string) *RegionSummary {
+		i, ok := index[region]
+		if !ok {
+			i = len(summaries)
+			index[region] = i
+			summaries = append(summaries, RegionSummary{Region: region})
+		}
+		return &summaries[i]
 	}
 
-	data.VPCs, err = FetchVPCs(ctx)
-	if err != nil {
-		return AWSData{}, err
+	for _, instance := range data.EC2Instances {
+		summary(instance.Region).EC2Instances++
+	}
+	for _, bucket := range data.S3Buckets {
+		region := bucket.Region
+		if region == "" {
+			region = "us-east-1"
+		}
+		summary(region).S3Buckets++
+	}
+	for _, instance := range data.RDSInstances {
+		summary(instance.Region).RDSInstances++
+	}
+	for _, table := range data.DynamoDBTables {
+		summary(table.Region).DynamoDBTables++
+	}
+	for _, vpc := range data.VPCs {
+		summary(vpc.Region).VPCs++
+	}
+	for _, volume := range data.EBSVolumes {
+		summary(volume.Region).EBSVolumes++
+	}
+	for _, cluster := range data.EKSClusters {
+		summary(cluster.Region).EKSClusters++
+	}
+	for _, cluster := range data.ECSClusters {
+		summary(cluster.Region).ECSClusters++
+	}
+	for _, function := range data.LambdaFunctions {
+		summary(function.Region).LambdaFunctions++
 	}
 
-	return data, nil
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].Region < summaries[j].Region
+	})
+	return summaries
+}
+
+func (s RegionSummary) total() int {
+	return s.EC2Instances + s.S3Buckets + s.RDSInstances + s.DynamoDBTables + s.VPCs + s.EBSVolumes +
+		s.EKSClusters + s.ECSClusters + s.LambdaFunctions
+}
+
+// sort orders resources by region and ID, and errors by region and service,
+// so the output does not depend on which call finished first.
+func (d *AWSData) sort() {
+	sort.SliceStable(d.EC2Instances, func(i, j int) bool {
+		a, b := d.EC2Instances[i], d.EC2Instances[j]
+		return a.Region < b.Region || (a.Region == b.Region && a.InstanceID < b.InstanceID)
+	})
+	sort.SliceStable(d.RDSInstances, func(i, j int) bool {
+		a, b := d.RDSInstances[i], d.RDSInstances[j]
+		return a.Region < b.Region || (a.Region == b.Region && a.InstanceID < b.InstanceID)
+	})
+	sort.SliceStable(d.DynamoDBTables, func(i, j int) bool {
+		a, b := d.DynamoDBTables[i], d.DynamoDBTables[j]
+		return a.Region < b.Region || (a.Region == b.Region && a.TableName < b.TableName)
+	})
+	sort.SliceStable(d.VPCs, func(i, j int) bool {
+		a, b := d.VPCs[i], d.VPCs[j]
+		return a.Region < b.Region || (a.Region == b.Region && a.VPCID < b.VPCID)
+	})
+	sort.SliceStable(d.Subnets, func(i, j int) bool {
+		a, b := d.Subnets[i], d.Subnets[j]
+		return a.Region < b.Region || (a.Region == b.Region && a.SubnetID < b.SubnetID)
+	})
+	sort.SliceStable(d.RouteTables, func(i, j int) bool {
+		a, b := d.RouteTables[i], d.RouteTables[j]
+		return a.Region < b.Region || (a.Region == b.Region && a.RouteTableID < b.RouteTableID)
+	})
+	sort.SliceStable(d.InternetGateways, func(i, j int) bool {
+		a, b := d.InternetGateways[i], d.InternetGateways[j]
+		return a.Region < b.Region || (a.Region == b.Region && a.GatewayID < b.GatewayID)
+	})
+	sort.SliceStable(d.NATGateways, func(i, j int) bool {
+		a, b := d.NATGateways[i], d.NATGateways[j]
+		return a.Region < b.Region || (a.Region == b.Region && a.GatewayID < b.GatewayID)
+	})
+	sort.SliceStable(d.ElasticIPs, func(i, j int) bool {
+		a, b := d.ElasticIPs[i], d.ElasticIPs[j]
+		return a.Region < b.Region || (a.Region == b.Region && a.PublicIP < b.PublicIP)
+	})
+	sort.SliceStable(d.SecurityGroups, func(i, j int) bool {
+		a, b := d.SecurityGroups[i], d.SecurityGroups[j]
+		return a.Region < b.Region || (a.Region == b.Region && a.GroupID < b.GroupID)
+	})
+	sort.SliceStable(d.LoadBalancers, func(i, j int) bool {
+		a, b := d.LoadBalancers[i], d.LoadBalancers[j]
+		return a.Region < b.Region || (a.Region == b.Region && a.Name < b.Name)
+	})
+	sort.SliceStable(d.EBSVolumes, func(i, j int) bool {
+		a, b := d.EBSVolumes[i], d.EBSVolumes[j]
+		return a.Region < b.Region || (a.Region == b.Region && a.VolumeID < b.VolumeID)
+	})
+	sort.SliceStable(d.EKSClusters, func(i, j int) bool {
+		a, b := d.EKSClusters[i], d.EKSClusters[j]
+		return a.Region < b.Region || (a.Region == b.Region && a.Name < b.Name)
+	})
+	sort.SliceStable(d.EKSNodeGroups, func(i, j int) bool {
+		a, b := d.EKSNodeGroups[i], d.EKSNodeGroups[j]
+		return a.Region < b.Region || (a.Region == b.Region && (a.Cluster < b.Cluster || (a.Cluster == b.Cluster && a.Name < b.Name)))
+	})
+	sort.SliceStable(d.ECSClusters, func(i, j int) bool {
+		a, b := d.ECSClusters[i], d.ECSClusters[j]
+		return a.Region < b.Region || (a.Region == b.Region && a.Name < b.Name)
+	})
+	sort.SliceStable(d.ECSServices, func(i, j int) bool {
+		a, b := d.ECSServices[i], d.ECSServices[j]
+		return a.Region < b.Region || (a.Region == b.Region && (a.Cluster < b.Cluster || (a.Cluster == b.Cluster && a.Name < b.Name)))
+	})
+	sort.SliceStable(d.ECSTasks, func(i, j int) bool {
+		a, b := d.ECSTasks[i], d.ECSTasks[j]
+		return a.Region < b.Region || (a.Region == b.Region && (a.Cluster < b.Cluster || (a.Cluster == b.Cluster && a.TaskID < b.TaskID)))
+	})
+	sort.SliceStable(d.LambdaFunctions, func(i, j int) bool {
+		a, b := d.LambdaFunctions[i], d.LambdaFunctions[j]
+		return a.Region < b.Region || (a.Region == b.Region && a.Name < b.Name)
+	})
+	sort.SliceStable(d.OrphanedSnapshots, func(i, j int) bool {
+		a, b := d.OrphanedSnapshots[i], d.OrphanedSnapshots[j]
+		return a.Region < b.Region || (a.Region == b.Region && a.SnapshotID < b.SnapshotID)
+	})
+	sort.SliceStable(d.Errors, func(i, j int) bool {
+		a, b := d.Errors[i], d.Errors[j]
+		return a.Region < b.Region || (a.Region == b.Region && a.Service < b.Service)
+	})
 }
 
-func CollectSnapshotData(ctx context.Context) (map[string]interface{}, error) {
+func CollectSnapshotData(ctx context.Context, optFns ...func(*config.LoadOptions) error) (map[string]interface{}, error) {
 	snapshots := map[string]interface{}{}
 
-	cfg, err := config.LoadDefaultConfig(ctx)
+	cfg, err := config.LoadDefaultConfig(ctx, optFns...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %v", err)
 	}
 
-	ec2Client := ec2.NewFromConfig(cfg)
-	regionsOutput, err := ec2Client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
+	regions, err := enabledRegions(ctx, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("unable to describe AWS regions: %v", err)
+		return nil, err
 	}
 
-	log.Printf("Collecting AWS snapshots from %d regions...", len(regionsOutput.Regions))
+	log.Printf("Collecting AWS snapshots from %d regions...", len(regions))
 
 	var allEBSSnapshots []map[string]string
 	var allRDSSnapshots []map[string]string
+	allBackups := map[string][]map[string]string{}
 
 	var wg sync.WaitGroup
 	var mutex sync.Mutex
+	sem := make(chan struct{}, maxConcurrency)
 
-	for _, region := range regionsOutput.Regions {
-		regionName := *region.RegionName
+	for _, region := range regions {
+		regionCfg := cfg.Copy()
+		regionCfg.Region = region
 
 		wg.Add(1)
-		go func(regionName string) {
+		go func() {
 			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
 
-			regionCfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(regionName))
+			ebsSnapshots, err := collectEBSSnapshots(ctx, regionCfg)
 			if err != nil {
-				log.Printf("Warning: Failed to load config for region %s: %v", regionName, err)
-				return
+				log.Printf("Warning: Unable to collect AWS EBS snapshots in %s: %v", region, err)
 			}
-
-			ebsSnapshots, err := collectEBSSnapshots(ctx, regionCfg)
-			if err == nil && len(ebsSnapshots) > 0 {
-				for i := range ebsSnapshots {
-					ebsSnapshots[i]["Region"] = regionName
+			for i := range ebsSnapshots {
+				ebsSnapshots[i]["Region"] = region
+			}
+			if len(ebsSnapshots) > 0 {
+				if exists, err := volumeIDs(ctx, regionCfg); err != nil {
+					log.Printf("Warning: Unable to check AWS EBS snapshot volumes in %s: %v", region, err)
+				} else {
+					for i := range ebsSnapshots {
+						ebsSnapshots[i]["VolumeDeleted"] = strconv.FormatBool(volumeDeleted(ebsSnapshots[i]["VolumeId"], exists))
+					}
 				}
-
-				mutex.Lock()
-				allEBSSnapshots = append(allEBSSnapshots, ebsSnapshots...)
-				mutex.Unlock()
 			}
 
 			rdsSnapshots, err := collectRDSSnapshots(ctx, regionCfg)
-			if err == nil && len(rdsSnapshots) > 0 {
-				for i := range rdsSnapshots {
-					rdsSnapshots[i]["Region"] = regionName
-				}
+			if err != nil {
+				log.Printf("Warning: Unable to collect AWS RDS snapshots in %s: %v", region, err)
+			}
+			for i := range rdsSnapshots {
+				rdsSnapshots[i]["Region"] = region
+			}
 
-				mutex.Lock()
-				allRDSSnapshots = append(allRDSSnapshots, rdsSnapshots...)
-				mutex.Unlock()
+			backups, err := collectBackups(ctx, regionCfg)
+			if err != nil {
+				log.Printf("Warning: Unable to collect AWS Backup in %s: %v", region, err)
+			}
+
+			if len(ebsSnapshots) > 0 || len(rdsSnapshots) > 0 || len(backups["RecoveryPoints"]) > 0 {
+				log.Printf("Found %d EBS snapshots, %d RDS snapshots and %d AWS Backup recovery points in %s",
+					len(ebsSnapshots), len(rdsSnapshots), len(backups["RecoveryPoints"]), region)
 			}
-		}(regionName)
+
+			mutex.Lock()
+			allEBSSnapshots = append(allEBSSnapshots, ebsSnapshots...)
+			allRDSSnapshots = append(allRDSSnapshots, rdsSnapshots...)
+			for kind, items := range backups {
+				allBackups[kind] = append(allBackups[kind], items...)
+			}
+			mutex.Unlock()
+		}()
 	}
 
 	wg.Wait()
@@ -371,92 +859,112 @@ func CollectSnapshotData(ctx context.Context) (map[string]interface{}, error) {
 		log.Printf("No AWS RDS snapshots found in any region")
 	}
 
+	for kind, items := range allBackups {
+		if len(items) > 0 {
+			snapshots[kind] = items
+		}
+	}
+	if len(allBackups["BackupVaults"]) > 0 {
+		log.Printf("Found %d AWS Backup vaults with %d recovery points, %d backup plans and %d protected resources",
+			len(allBackups["BackupVaults"]), len(allBackups["RecoveryPoints"]), len(allBackups["BackupPlans"]), len(allBackups["ProtectedResources"]))
+	}
+
 	return snapshots, nil
 }
 
 func collectEBSSnapshots(ctx context.Context, cfg aws.Config) ([]map[string]string, error) {
-	client := ec2.NewFromConfig(cfg)
-
-	resp, err := client.DescribeSnapshots(ctx, &ec2.DescribeSnapshotsInput{
+	paginator := ec2.NewDescribeSnapshotsPaginator(ec2.NewFromConfig(cfg), &ec2.DescribeSnapshotsInput{
 		OwnerIds: []string{"self"},
 	})
-	if err != nil {
-		return nil, err
-	}
 
 	var snapshots []map[string]string
-	for _, snapshot := range resp.Snapshots {
-		snapshotInfo := map[string]string{
-			"SnapshotId": *snapshot.SnapshotId,
-			"VolumeId":   *snapshot.VolumeId,
-			"State":      string(snapshot.State),
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
 		}
-
-		if snapshot.VolumeSize != nil {
-			snapshotInfo["VolumeSize"] = fmt.Sprintf("%d GiB", *snapshot.VolumeSize)
+		for _, snapshot := range page.Snapshots {
+			snapshots = append(snapshots, ebsSnapshotInfo(snapshot))
 		}
+	}
 
-		if snapshot.StartTime != nil {
-			snapshotInfo["StartTime"] = snapshot.StartTime.Format(time.RFC3339)
-		}
+	return snapshots, nil
+}
 
-		if snapshot.Description != nil {
-			snapshotInfo["Description"] = *snapshot.Description
-		}
+func ebsSnapshotInfo(snapshot ec2types.Snapshot) map[string]string {
+	snapshotInfo := map[string]string{
+		"SnapshotId": *snapshot.SnapshotId,
+		"VolumeId":   *snapshot.VolumeId,
+		"State":      string(snapshot.State),
+	}
 
-		if snapshot.Encrypted != nil {
-			if *snapshot.Encrypted {
-				snapshotInfo["Encrypted"] = "true"
-			} else {
-				snapshotInfo["Encrypted"] = "false"
-			}
-		}
+	if snapshot.VolumeSize != nil {
+		snapshotInfo["VolumeSize"] = fmt.Sprintf("%d GiB", *snapshot.VolumeSize)
+	}
 
-		snapshots = append(snapshots, snapshotInfo)
+	if snapshot.StartTime != nil {
+		snapshotInfo["StartTime"] = snapshot.StartTime.Format(time.RFC3339)
 	}
 
-	return snapshots, nil
+	if snapshot.Description != nil {
+		snapshotInfo["Description"] = *snapshot.Description
+	}
+
+	if snapshot.Encrypted != nil {
+		if *snapshot.Encrypted {
+			snapshotInfo["Encrypted"] = "true"
+		} else {
+			snapshotInfo["Encrypted"] = "false"
+		}
+	}
+
+	return snapshotInfo
 }
 
 func collectRDSSnapshots(ctx context.Context, cfg aws.Config) ([]map[string]string, error) {
-	client := rds.NewFromConfig(cfg)
-
-	resp, err := client.DescribeDBSnapshots(ctx, &rds.DescribeDBSnapshotsInput{})
-	if err != nil {
-		return nil, err
-	}
+	paginator := rds.NewDescribeDBSnapshotsPaginator(rds.NewFromConfig(cfg), &rds.DescribeDBSnapshotsInput{})
 
 	var snapshots []map[string]string
-	for _, snapshot := range resp.DBSnapshots {
-		snapshotInfo := map[string]string{
-			"SnapshotId":   aws.ToString(snapshot.DBSnapshotIdentifier),
-			"DBInstanceId": aws.ToString(snapshot.DBInstanceIdentifier),
-			"SnapshotType": aws.ToString(snapshot.SnapshotType),
-			"Status":       aws.ToString(snapshot.Status),
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
 		}
-
-		if snapshot.Engine != nil {
-			snapshotInfo["Engine"] = *snapshot.Engine
+		for _, snapshot := range page.DBSnapshots {
+			snapshots = append(snapshots, rdsSnapshotInfo(snapshot))
 		}
+	}
 
-		if snapshot.AllocatedStorage != nil && *snapshot.AllocatedStorage != 0 {
-			snapshotInfo["AllocatedStorage"] = fmt.Sprintf("%d GiB", *snapshot.AllocatedStorage)
-		}
+	return snapshots, nil
+}
 
-		if snapshot.SnapshotCreateTime != nil {
-			snapshotInfo["CreationTime"] = snapshot.SnapshotCreateTime.Format(time.RFC3339)
-		}
+func rdsSnapshotInfo(snapshot rdstypes.DBSnapshot) map[string]string {
+	snapshotInfo := map[string]string{
+		"SnapshotId":   aws.ToString(snapshot.DBSnapshotIdentifier),
+		"DBInstanceId": aws.ToString(snapshot.DBInstanceIdentifier),
+		"SnapshotType": aws.ToString(snapshot.SnapshotType),
+		"Status":       aws.ToString(snapshot.Status),
+	}
 
-		if snapshot.Encrypted != nil {
-			if *snapshot.Encrypted {
-				snapshotInfo["Encrypted"] = "true"
-			} else {
-				snapshotInfo["Encrypted"] = "false"
-			}
-		}
+	if snapshot.Engine != nil {
+		snapshotInfo["Engine"] = *snapshot.Engine
+	}
 
-		snapshots = append(snapshots, snapshotInfo)
+	if snapshot.AllocatedStorage != nil && *snapshot.AllocatedStorage != 0 {
+		snapshotInfo["AllocatedStorage"] = fmt.Sprintf("%d GiB", *snapshot.AllocatedStorage)
 	}
 
-	return snapshots, nil
+	if snapshot.SnapshotCreateTime != nil {
+		snapshotInfo["CreationTime"] = snapshot.SnapshotCreateTime.Format(time.RFC3339)
+	}
+
+	if snapshot.Encrypted != nil {
+		if *snapshot.Encrypted {
+			snapshotInfo["Encrypted"] = "true"
+		} else {
+			snapshotInfo["Encrypted"] = "false"
+		}
+	}
+
+	return snapshotInfo
 }
@@ -0,0 +1,539 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	elb "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
+	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbv2types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+)
+
+type VPCInfo struct {
+	VPCID        string
+	Name         string
+	CIDRBlock    string
+	IsDefault    bool
+	State        string
+	Region       string
+	AccountID    string
+	AccountAlias string
+}
+
+type SubnetInfo struct {
+	SubnetID         string
+	Name             string
+	VPCID            string
+	CIDRBlock        string
+	AvailabilityZone string
+	AvailableIPs     int32
+	MapPublicIP      bool
+	Region           string
+	AccountID        string
+	AccountAlias     string
+}
+
+// RouteTableInfo is a route table, with each route summarised as
+// "destination -> target".
+type RouteTableInfo struct {
+	RouteTableID string
+	Name         string
+	VPCID        string
+	Main         bool
+	Subnets      []string
+	Routes       []string
+	Region       string
+	AccountID    string
+	AccountAlias string
+}
+
+type InternetGatewayInfo struct {
+	GatewayID    string
+	Name         string
+	VPCID        string
+	State        string
+	Region       string
+	AccountID    string
+	AccountAlias string
+}
+
+type NATGatewayInfo struct {
+	GatewayID        string
+	Name             string
+	VPCID            string
+	SubnetID         string
+	State            string
+	ConnectivityType string
+	PublicIP         string
+	PrivateIP        string
+	Region           string
+	AccountID        string
+	AccountAlias     string
+}
+
+// ElasticIPInfo is an Elastic IP address. Unassociated addresses are still
+// billed.
+type ElasticIPInfo struct {
+	AllocationID       string
+	Name               string
+	PublicIP           string
+	Domain             string
+	InstanceID         string
+	NetworkInterfaceID string
+	PrivateIP          string
+	Unassociated       bool
+	Region             string
+	AccountID          string
+	AccountAlias       string
+}
+
+// SecurityGroupInfo is a security group, with each rule summarised as
+// "protocol port from source". OpenToWorld is set when any inbound rule
+// allows 0.0.0.0/0 or ::/0.
+type SecurityGroupInfo struct {
+	GroupID       string
+	GroupName     string
+	Description   string
+	VPCID         string
+	InboundRules  []string
+	OutboundRules []string
+	OpenToWorld   bool
+	Region        string
+	AccountID     string
+	AccountAlias  string
+}
+
+// LoadBalancerInfo is an application, network, gateway or classic load
+// balancer. Targets are summarised as "target-group/id:port (state)", or
+// "id (state)" for classic load balancers.
+type LoadBalancerInfo struct {
+	Name              string
+	Type              string
+	Scheme            string
+	State             string
+	DNSName           string
+	VPCID             string
+	AvailabilityZones []string
+	SecurityGroups    []string
+	Listeners         []string
+	TargetGroups      []string
+	Targets           []string
+	CreatedAt         string
+	Region            string
+	AccountID         string
+	AccountAlias      string
+}
+
+// networkData is what fetchNetwork finds in one region.
+type networkData struct {
+	vpcs             []VPCInfo
+	subnets          []SubnetInfo
+	routeTables      []RouteTableInfo
+	internetGateways []InternetGatewayInfo
+	natGateways      []NATGatewayInfo
+	elasticIPs       []ElasticIPInfo
+	securityGroups   []SecurityGroupInfo
+}
+
+// fetchNetwork lists the VPCs in a region and the subnets, route tables,
+// gateways, Elastic IPs and security groups in them. Only a failure to list
+// the VPCs is fatal; the other failures are returned alongside the results.
+func fetchNetwork(ctx context.Context, cfg aws.Config) (networkData, []error, error) {
+	client := ec2.NewFromConfig(cfg)
+	region := cfg.Region
+
+	var network networkData
+	var errs []error
+
+	vpcs := ec2.NewDescribeVpcsPaginator(client, &ec2.DescribeVpcsInput{})
+	for vpcs.HasMorePages() {
+		page, err := vpcs.NextPage(ctx)
+		if err != nil {
+			return networkData{}, nil, fmt.Errorf("unable to describe VPCs, %v", err)
+		}
+		for _, vpc := range page.Vpcs {
+			network.vpcs = append(network.vpcs, VPCInfo{
+				VPCID:     aws.ToString(vpc.VpcId),
+				Name:      nameTag(vpc.Tags),
+				CIDRBlock: aws.ToString(vpc.CidrBlock),
+				IsDefault: aws.ToBool(vpc.IsDefault),
+				State:     string(vpc.State),
+				Region:    region,
+			})
+		}
+	}
+
+	subnets := ec2.NewDescribeSubnetsPaginator(client, &ec2.DescribeSubnetsInput{})
+	for subnets.HasMorePages() {
+		page, err := subnets.NextPage(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to describe subnets, %v", err))
+			break
+		}
+		for _, subnet := range page.Subnets {
+			network.subnets = append(network.subnets, SubnetInfo{
+				SubnetID:         aws.ToString(subnet.SubnetId),
+				Name:             nameTag(subnet.Tags),
+				VPCID:            aws.ToString(subnet.VpcId),
+				CIDRBlock:        aws.ToString(subnet.CidrBlock),
+				AvailabilityZone: aws.ToString(subnet.AvailabilityZone),
+				AvailableIPs:     aws.ToInt32(subnet.AvailableIpAddressCount),
+				MapPublicIP:      aws.ToBool(subnet.MapPublicIpOnLaunch),
+				Region:           region,
+			})
+		}
+	}
+
+	routeTables := ec2.NewDescribeRouteTablesPaginator(client, &ec2.DescribeRouteTablesInput{})
+	for routeTables.HasMorePages() {
+		page, err := routeTables.NextPage(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to describe route tables, %v", err))
+			break
+		}
+		for _, routeTable := range page.RouteTables {
+			network.routeTables = append(network.routeTables, routeTableInfo(routeTable, region))
+		}
+	}
+
+	internetGateways := ec2.NewDescribeInternetGatewaysPaginator(client, &ec2.DescribeInternetGatewaysInput{})
+	for internetGateways.HasMorePages() {
+		page, err := internetGateways.NextPage(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to describe internet gateways, %v", err))
+			break
+		}
+		for _, gateway := range page.InternetGateways {
+			info := InternetGatewayInfo{
+				GatewayID: aws.ToString(gateway.InternetGatewayId),
+				Name:      nameTag(gateway.Tags),
+				State:     "detached",
+				Region:    region,
+			}
+			for _, attachment := range gateway.Attachments {
+				info.VPCID = aws.ToString(attachment.VpcId)
+				info.State = string(attachment.State)
+			}
+			network.internetGateways = append(network.internetGateways, info)
+		}
+	}
+
+	natGateways := ec2.NewDescribeNatGatewaysPaginator(client, &ec2.DescribeNatGatewaysInput{})
+	for natGateways.HasMorePages() {
+		page, err := natGateways.NextPage(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to describe NAT gateways, %v", err))
+			break
+		}
+		for _, gateway := range page.NatGateways {
+			info := NATGatewayInfo{
+				GatewayID:        aws.ToString(gateway.NatGatewayId),
+				Name:             nameTag(gateway.Tags),
+				VPCID:            aws.ToString(gateway.VpcId),
+				SubnetID:         aws.ToString(gateway.SubnetId),
+				State:            string(gateway.State),
+				ConnectivityType: string(gateway.ConnectivityType),
+				Region:           region,
+			}
+			if len(gateway.NatGatewayAddresses) > 0 {
+				info.PublicIP = aws.ToString(gateway.NatGatewayAddresses[0].PublicIp)
+				info.PrivateIP = aws.ToString(gateway.NatGatewayAddresses[0].PrivateIp)
+			}
+			network.natGateways = append(network.natGateways, info)
+		}
+	}
+
+	addresses, err := client.DescribeAddresses(ctx, &ec2.DescribeAddressesInput{})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("unable to describe Elastic IPs, %v", err))
+	} else {
+		for _, address := range addresses.Addresses {
+			network.elasticIPs = append(network.elasticIPs, ElasticIPInfo{
+				AllocationID:       aws.ToString(address.AllocationId),
+				Name:               nameTag(address.Tags),
+				PublicIP:           aws.ToString(address.PublicIp),
+				Domain:             string(address.Domain),
+				InstanceID:         aws.ToString(address.InstanceId),
+				NetworkInterfaceID: aws.ToString(address.NetworkInterfaceId),
+				PrivateIP:          aws.ToString(address.PrivateIpAddress),
+				Unassociated:       address.AssociationId == nil && address.InstanceId == nil,
+				Region:             region,
+			})
+		}
+	}
+
+	securityGroups := ec2.NewDescribeSecurityGroupsPaginator(client, &ec2.DescribeSecurityGroupsInput{})
+	for securityGroups.HasMorePages() {
+		page, err := securityGroups.NextPage(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to describe security groups, %v", err))
+			break
+		}
+		for _, group := range page.SecurityGroups {
+			network.securityGroups = append(network.securityGroups, securityGroupInfo(group, region))
+		}
+	}
+
+	return network, errs, nil
+}
+
+func nameTag(tags []ec2types.Tag) string {
+	for _, tag := range tags {
+		if aws.ToString(tag.Key) == "Name" {
+			return aws.ToString(tag.Value)
+		}
+	}
+	return ""
+}
+
+func routeTableInfo(routeTable ec2types.RouteTable, region string) RouteTableInfo {
+	info := RouteTableInfo{
+		RouteTableID: aws.ToString(routeTable.RouteTableId),
+		Name:         nameTag(routeTable.Tags),
+		VPCID:        aws.ToString(routeTable.VpcId),
+		Region:       region,
+	}
+
+	for _, association := range routeTable.Associations {
+		if aws.ToBool(association.Main) {
+			info.Main = true
+		}
+		if association.SubnetId != nil {
+			info.Subnets = append(info.Subnets, aws.ToString(association.SubnetId))
+		}
+	}
+
+	for _, route := range routeTable.Routes {
+		destination := aws.ToString(route.DestinationCidrBlock)
+		if destination == "" {
+			destination = aws.ToString(route.DestinationIpv6CidrBlock)
+		}
+		if destination == "" {
+			destination = aws.ToString(route.DestinationPrefixListId)
+		}
+
+		target := "unknown"
+		for _, id := range []*string{route.GatewayId, route.NatGatewayId, route.TransitGatewayId,
+			route.VpcPeeringConnectionId, route.InstanceId, route.NetworkInterfaceId, route.LocalGatewayId, route.CarrierGatewayId} {
+			if aws.ToString(id) != "" {
+				target = aws.ToString(id)
+				break
+			}
+		}
+
+		summary := destination + " -> " + target
+		if route.State == ec2types.RouteStateBlackhole {
+			summary += " (blackhole)"
+		}
+		info.Routes = append(info.Routes, summary)
+	}
+
+	return info
+}
+
+func securityGroupInfo(group ec2types.SecurityGroup, region string) SecurityGroupInfo {
+	info := SecurityGroupInfo{
+		GroupID:     aws.ToString(group.GroupId),
+		GroupName:   aws.ToString(group.GroupName),
+		Description: aws.ToString(group.Description),
+		VPCID:       aws.ToString(group.VpcId),
+		Region:      region,
+	}
+
+	for _, permission := range group.IpPermissions {
+		rules, open := permissionRules(permission, "from")
+		info.InboundRules = append(info.InboundRules, rules...)
+		info.OpenToWorld = info.OpenToWorld || open
+	}
+	for _, permission := range group.IpPermissionsEgress {
+		rules, _ := permissionRules(permission, "to")
+		info.OutboundRules = append(info.OutboundRules, rules...)
+	}
+
+	return info
+}
+
+// permissionRules summarises a permission as one rule per source, such as
+// "tcp 443 from 0.0.0.0/0", and reports whether any source is the internet.
+func permissionRules(permission ec2types.IpPermission, direction string) ([]string, bool) {
+	protocol := aws.ToString(permission.IpProtocol)
+	ports := "all"
+	switch {
+	case protocol == "-1":
+		protocol = "all"
+	case permission.FromPort != nil && aws.ToInt32(permission.FromPort) == aws.ToInt32(permission.ToPort):
+		ports = fmt.Sprint(aws.ToInt32(permission.FromPort))
+	case permission.FromPort != nil:
+		ports = fmt.Sprintf("%d-%d", aws.ToInt32(permission.FromPort), aws.ToInt32(permission.ToPort))
+	}
+	prefix := protocol
+	if protocol != "all" {
+		prefix += " " + ports
+	}
+
+	var sources []string
+	for _, ipRange := range permission.IpRanges {
+		sources = append(sources, aws.ToString(ipRange.CidrIp))
+	}
+	for _, ipRange := range permission.Ipv6Ranges {
+		sources = append(sources, aws.ToString(ipRange.CidrIpv6))
+	}
+	for _, prefixList := range permission.PrefixListIds {
+		sources = append(sources, aws.ToString(prefixList.PrefixListId))
+	}
+	for _, pair := range permission.UserIdGroupPairs {
+		sources = append(sources, aws.ToString(pair.GroupId))
+	}
+
+	rules := make([]string, 0, len(sources))
+	open := false
+	for _, source := range sources {
+		rules = append(rules, fmt.Sprintf("%s %s %s", prefix, direction, source))
+		open = open || source == "0.0.0.0/0" || source == "::/0"
+	}
+	return rules, open
+}
+
+// fetchLoadBalancers lists the application, network, gateway and classic
+// load balancers in a region with their listeners and targets. A load
+// balancer whose listeners or targets cannot be read is still returned, with
+// the error alongside the others.
+func fetchLoadBalancers(ctx context.Context, cfg aws.Config) ([]LoadBalancerInfo, []error, error) {
+	client := elbv2.NewFromConfig(cfg)
+
+	var loadBalancers []LoadBalancerInfo
+	var errs []error
+	paginator := elbv2.NewDescribeLoadBalancersPaginator(client, &elbv2.DescribeLoadBalancersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to describe load balancers, %v", err)
+		}
+		for _, loadBalancer := range page.LoadBalancers {
+			info, err := loadBalancerInfo(ctx, client, loadBalancer)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			info.Region = cfg.Region
+			loadBalancers = append(loadBalancers, info)
+		}
+	}
+
+	classic, err := fetchClassicLoadBalancers(ctx, cfg)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	loadBalancers = append(loadBalancers, classic...)
+
+	return loadBalancers, errs, nil
+}
+
+func loadBalancerInfo(ctx context.Context, client *elbv2.Client, loadBalancer elbv2types.LoadBalancer) (LoadBalancerInfo, error) {
+	info := LoadBalancerInfo{
+		Name:           aws.ToString(loadBalancer.LoadBalancerName),
+		Type:           string(loadBalancer.Type),
+		Scheme:         string(loadBalancer.Scheme),
+		DNSName:        aws.ToString(loadBalancer.DNSName),
+		VPCID:          aws.ToString(loadBalancer.VpcId),
+		SecurityGroups: loadBalancer.SecurityGroups,
+		CreatedAt:      formatTime(loadBalancer.CreatedTime),
+	}
+	if loadBalancer.State != nil {
+		info.State = string(loadBalancer.State.Code)
+	}
+	for _, zone := range loadBalancer.AvailabilityZones {
+		info.AvailabilityZones = append(info.AvailabilityZones, aws.ToString(zone.ZoneName))
+	}
+
+	listeners := elbv2.NewDescribeListenersPaginator(client, &elbv2.DescribeListenersInput{LoadBalancerArn: loadBalancer.LoadBalancerArn})
+	for listeners.HasMorePages() {
+		page, err := listeners.NextPage(ctx)
+		if err != nil {
+			return info, fmt.Errorf("unable to describe listeners of load balancer %s, %v", info.Name, err)
+		}
+		for _, listener := range page.Listeners {
+			info.Listeners = append(info.Listeners, fmt.Sprintf("%s:%d", listener.Protocol, aws.ToInt32(listener.Port)))
+		}
+	}
+
+	targetGroups := elbv2.NewDescribeTargetGroupsPaginator(client, &elbv2.DescribeTargetGroupsInput{LoadBalancerArn: loadBalancer.LoadBalancerArn})
+	for targetGroups.HasMorePages() {
+		page, err := targetGroups.NextPage(ctx)
+		if err != nil {
+			return info, fmt.Errorf("unable to describe target groups of load balancer %s, %v", info.Name, err)
+		}
+		for _, targetGroup := range page.TargetGroups {
+			name := aws.ToString(targetGroup.TargetGroupName)
+			info.TargetGroups = append(info.TargetGroups, name)
+
+			health, err := client.DescribeTargetHealth(ctx, &elbv2.DescribeTargetHealthInput{TargetGroupArn: targetGroup.TargetGroupArn})
+			if err != nil {
+				return info, fmt.Errorf("unable to describe targets of target group %s, %v", name, err)
+			}
+			for _, target := range health.TargetHealthDescriptions {
+				if target.Target == nil {
+					continue
+				}
+				summary := fmt.Sprintf("%s/%s", name, aws.ToString(target.Target.Id))
+				if target.Target.Port != nil {
+					summary += fmt.Sprintf(":%d", *target.Target.Port)
+				}
+				if target.TargetHealth != nil {
+					summary += fmt.Sprintf(" (%s)", target.TargetHealth.State)
+				}
+				info.Targets = append(info.Targets, summary)
+			}
+		}
+	}
+
+	return info, nil
+}
+
+func fetchClassicLoadBalancers(ctx context.Context, cfg aws.Config) ([]LoadBalancerInfo, error) {
+	client := elb.NewFromConfig(cfg)
+
+	var loadBalancers []LoadBalancerInfo
+	paginator := elb.NewDescribeLoadBalancersPaginator(client, &elb.DescribeLoadBalancersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return loadBalancers, fmt.Errorf("unable to describe classic load balancers, %v", err)
+		}
+		for _, loadBalancer := range page.LoadBalancerDescriptions {
+			info := LoadBalancerInfo{
+				Name:              aws.ToString(loadBalancer.LoadBalancerName),
+				Type:              "classic",
+				Scheme:            aws.ToString(loadBalancer.Scheme),
+				DNSName:           aws.ToString(loadBalancer.DNSName),
+				VPCID:             aws.ToString(loadBalancer.VPCId),
+				AvailabilityZones: loadBalancer.AvailabilityZones,
+				SecurityGroups:    loadBalancer.SecurityGroups,
+				CreatedAt:         formatTime(loadBalancer.CreatedTime),
+				Region:            cfg.Region,
+			}
+			for _, description := range loadBalancer.ListenerDescriptions {
+				if listener := description.Listener; listener != nil {
+					info.Listeners = append(info.Listeners, fmt.Sprintf("%s:%d", strings.ToUpper(aws.ToString(listener.Protocol)), listener.LoadBalancerPort))
+				}
+			}
+
+			health, err := client.DescribeInstanceHealth(ctx, &elb.DescribeInstanceHealthInput{LoadBalancerName: loadBalancer.LoadBalancerName})
+			if err != nil {
+				for _, instance := range loadBalancer.Instances {
+					info.Targets = append(info.Targets, aws.ToString(instance.InstanceId))
+				}
+			} else {
+				for _, state := range health.InstanceStates {
+					info.Targets = append(info.Targets, fmt.Sprintf("%s (%s)", aws.ToString(state.InstanceId), aws.ToString(state.State)))
+				}
+			}
+			loadBalancers = append(loadBalancers, info)
+		}
+	}
+
+	return loadBalancers, nil
+}
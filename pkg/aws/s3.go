@@ -0,0 +1,395 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+type S3BucketInfo struct {
+	Name                string
+	Immutable           bool
+	Region              string
+	Versioning          string
+	MFADelete           bool
+	ObjectLockMode      string
+	ObjectLockRetention string
+	LifecycleRules      []string
+	Encryption          string
+	KMSKeyID            string
+	BucketKeyEnabled    bool
+	PublicAccessBlock   *S3PublicAccessBlock `json:",omitempty"`
+	PublicAccessBlocked bool
+	Replication         []string
+	SizeBytes           int64
+	ObjectCount         int64
+	StorageClassBytes   map[string]int64 `json:",omitempty"`
+	AccountID           string
+	AccountAlias        string
+}
+
+// S3PublicAccessBlock is a bucket's public access block configuration.
+type S3PublicAccessBlock struct {
+	BlockPublicAcls       bool
+	IgnorePublicAcls      bool
+	BlockPublicPolicy     bool
+	RestrictPublicBuckets bool
+}
+
+// fetchS3Buckets lists every bucket in the account with its protection
+// settings and the size reported by CloudWatch. A setting that cannot be read
+// is left empty and its error returned with the others.
+func fetchS3Buckets(ctx context.Context, cfg aws.Config) ([]S3BucketInfo, []error, error) {
+	svc := s3.NewFromConfig(cfg)
+
+	var listed []s3types.Bucket
+	paginator := s3.NewListBucketsPaginator(svc, &s3.ListBucketsInput{MaxBuckets: aws.Int32(1000)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to list buckets, %v", err)
+		}
+		listed = append(listed, page.Buckets...)
+	}
+
+	buckets := make([]S3BucketInfo, len(listed))
+	var errs []error
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrency)
+	for i, bucket := range listed {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			info, bucketErrs := s3BucketDetails(ctx, cfg, svc, aws.ToString(bucket.Name))
+			buckets[i] = info
+			mutex.Lock()
+			errs = append(errs, bucketErrs...)
+			mutex.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	byRegion := map[string][]int{}
+	for i, bucket := range buckets {
+		region := bucketRegion(bucket.Region)
+		byRegion[region] = append(byRegion[region], i)
+	}
+	for region, indexes := range byRegion {
+		regionCfg := cfg.Copy()
+		regionCfg.Region = region
+		storage, err := fetchS3StorageMetrics(ctx, regionCfg)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to get S3 storage metrics in %s, %v", region, err))
+			continue
+		}
+		for _, i := range indexes {
+			if metrics, ok := storage[buckets[i].Name]; ok {
+				buckets[i].SizeBytes = metrics.sizeBytes
+				buckets[i].ObjectCount = metrics.objects
+				buckets[i].StorageClassBytes = metrics.byStorageType
+			}
+		}
+	}
+
+	return buckets, errs, nil
+}
+
+// bucketRegion returns the region of a bucket location constraint, which is
+// empty for us-east-1 and "EU" for old eu-west-1 buckets.
+func bucketRegion(location string) string {
+	switch location {
+	case "":
+		return "us-east-1"
+	case "EU":
+		return "eu-west-1"
+	}
+	return location
+}
+
+// s3BucketDetails reads a bucket's location and then its versioning, Object
+// Lock, lifecycle, encryption, public access block and replication settings
+// from a client in the bucket's region. Settings that are not configured are
+// left empty; any other failure is returned.
+func s3BucketDetails(ctx context.Context, cfg aws.Config, svc *s3.Client, name string) (S3BucketInfo, []error) {
+	info := S3BucketInfo{Name: name}
+	var errs []error
+	record := func(setting string, err error) {
+		if err != nil && !s3NotConfigured(err) {
+			errs = append(errs, fmt.Errorf("unable to get bucket %s for %s, %v", setting, name, err))
+		}
+	}
+
+	location, err := svc.GetBucketLocation(ctx, &s3.GetBucketLocationInput{Bucket: aws.String(name)})
+	if err != nil {
+		return info, []error{fmt.Errorf("unable to get bucket location for %s, %v", name, err)}
+	}
+	info.Region = string(location.LocationConstraint)
+	svc = s3.NewFromConfig(cfg, func(o *s3.Options) {
+		o.Region = bucketRegion(info.Region)
+	})
+	bucket := aws.String(name)
+
+	versioning, err := svc.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{Bucket: bucket})
+	record("versioning", err)
+	if err == nil {
+		info.Versioning = string(versioning.Status)
+		if info.Versioning == "" {
+			info.Versioning = "Disabled"
+		}
+		info.MFADelete = versioning.MFADelete == s3types.MFADeleteStatusEnabled
+	}
+
+	objectLock, err := svc.GetObjectLockConfiguration(ctx, &s3.GetObjectLockConfigurationInput{Bucket: bucket})
+	record("object lock configuration", err)
+	if err == nil && objectLock.ObjectLockConfiguration != nil {
+		info.Immutable = objectLock.ObjectLockConfiguration.ObjectLockEnabled == s3types.ObjectLockEnabledEnabled
+		if rule := objectLock.ObjectLockConfiguration.Rule; rule != nil && rule.DefaultRetention != nil {
+			retention := rule.DefaultRetention
+			info.ObjectLockMode = string(retention.Mode)
+			if retention.Years != nil {
+				info.ObjectLockRetention = fmt.Sprintf("%d years", *retention.Years)
+			} else if retention.Days != nil {
+				info.ObjectLockRetention = fmt.Sprintf("%d days", *retention.Days)
+			}
+		}
+	}
+
+	lifecycle, err := svc.GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{Bucket: bucket})
+	record("lifecycle configuration", err)
+	if err == nil {
+		for _, rule := range lifecycle.Rules {
+			info.LifecycleRules = append(info.LifecycleRules, lifecycleRuleSummary(rule))
+		}
+	}
+
+	encryption, err := svc.GetBucketEncryption(ctx, &s3.GetBucketEncryptionInput{Bucket: bucket})
+	record("encryption", err)
+	if err == nil && encryption.ServerSideEncryptionConfiguration != nil {
+		for _, rule := range encryption.ServerSideEncryptionConfiguration.Rules {
+			if rule.ApplyServerSideEncryptionByDefault != nil {
+				info.Encryption = string(rule.ApplyServerSideEncryptionByDefault.SSEAlgorithm)
+				info.KMSKeyID = aws.ToString(rule.ApplyServerSideEncryptionByDefault.KMSMasterKeyID)
+			}
+			info.BucketKeyEnabled = aws.ToBool(rule.BucketKeyEnabled)
+		}
+	}
+
+	publicAccess, err := svc.GetPublicAccessBlock(ctx, &s3.GetPublicAccessBlockInput{Bucket: bucket})
+	record("public access block", err)
+	if err == nil && publicAccess.PublicAccessBlockConfiguration != nil {
+		config := publicAccess.PublicAccessBlockConfiguration
+		info.PublicAccessBlock = &S3PublicAccessBlock{
+			BlockPublicAcls:       aws.ToBool(config.BlockPublicAcls),
+			IgnorePublicAcls:      aws.ToBool(config.IgnorePublicAcls),
+			BlockPublicPolicy:     aws.ToBool(config.BlockPublicPolicy),
+			RestrictPublicBuckets: aws.ToBool(config.RestrictPublicBuckets),
+		}
+		block := info.PublicAccessBlock
+		info.PublicAccessBlocked = block.BlockPublicAcls && block.IgnorePublicAcls && block.BlockPublicPolicy && block.RestrictPublicBuckets
+	}
+
+	replication, err := svc.GetBucketReplication(ctx, &s3.GetBucketReplicationInput{Bucket: bucket})
+	record("replication", err)
+	if err == nil && replication.ReplicationConfiguration != nil {
+		for _, rule := range replication.ReplicationConfiguration.Rules {
+			info.Replication = append(info.Replication, replicationRuleSummary(rule))
+		}
+	}
+
+	return info, errs
+}
+
+// s3NotConfigured reports whether err means the bucket setting was never
+// configured rather than that it could not be read.
+func s3NotConfigured(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.ErrorCode() {
+	case "NoSuchLifecycleConfiguration",
+		"ObjectLockConfigurationNotFoundError",
+		"ServerSideEncryptionConfigurationNotFoundError",
+		"NoSuchPublicAccessBlockConfiguration",
+		"ReplicationConfigurationNotFoundError":
+		return true
+	}
+	return false
+}
+
+// lifecycleRuleSummary describes a lifecycle rule as, for example,
+// "archive: logs/, GLACIER after 30d, expire after 365d".
+func lifecycleRuleSummary(rule s3types.LifecycleRule) string {
+	parts := []string{aws.ToString(rule.ID) + ":"}
+	if rule.Filter != nil && aws.ToString(rule.Filter.Prefix) != "" {
+		parts = append(parts, aws.ToString(rule.Filter.Prefix))
+	} else if prefix := aws.ToString(rule.Prefix); prefix != "" {
+		parts = append(parts, prefix)
+	}
+
+	var actions []string
+	for _, transition := range rule.Transitions {
+		if transition.Days != nil {
+			actions = append(actions, fmt.Sprintf("%s after %dd", transition.StorageClass, *transition.Days))
+		} else if transition.Date != nil {
+			actions = append(actions, fmt.Sprintf("%s on %s", transition.StorageClass, transition.Date.Format("2006-01-02")))
+		}
+	}
+	if expiration := rule.Expiration; expiration != nil {
+		if expiration.Days != nil {
+			actions = append(actions, fmt.Sprintf("expire after %dd", *expiration.Days))
+		} else if expiration.Date != nil {
+			actions = append(actions, fmt.Sprintf("expire on %s", expiration.Date.Format("2006-01-02")))
+		}
+	}
+	if expiration := rule.NoncurrentVersionExpiration; expiration != nil && expiration.NoncurrentDays != nil {
+		actions = append(actions, fmt.Sprintf("noncurrent versions expire after %dd", *expiration.NoncurrentDays))
+	}
+	if abort := rule.AbortIncompleteMultipartUpload; abort != nil && abort.DaysAfterInitiation != nil {
+		actions = append(actions, fmt.Sprintf("abort uploads after %dd", *abort.DaysAfterInitiation))
+	}
+
+	summary := strings.Join(parts, " ")
+	if len(actions) > 0 {
+		summary += " " + strings.Join(actions, ", ")
+	}
+	if rule.Status != s3types.ExpirationStatusEnabled {
+		summary += " (disabled)"
+	}
+	return summary
+}
+
+// replicationRuleSummary describes a replication rule as, for example,
+// "dr: to backup-bucket (STANDARD_IA)".
+func replicationRuleSummary(rule s3types.ReplicationRule) string {
+	summary := aws.ToString(rule.ID) + ":"
+	if destination := rule.Destination; destination != nil {
+		summary += " to " + strings.TrimPrefix(aws.ToString(destination.Bucket), "arn:aws:s3:::")
+		if destination.StorageClass != "" {
+			summary += fmt.Sprintf(" (%s)", destination.StorageClass)
+		}
+	}
+	if rule.Status != s3types.ReplicationRuleStatusEnabled {
+		summary += " (disabled)"
+	}
+	return summary
+}
+
+type s3Storage struct {
+	sizeBytes     int64
+	objects       int64
+	byStorageType map[string]int64
+}
+
+// s3MetricQueriesLimit is the most queries GetMetricData accepts per call.
+const s3MetricQueriesLimit = 500
+
+// fetchS3StorageMetrics returns the latest size and object count of every
+// bucket that reports S3 storage metrics to CloudWatch in a region. S3
+// publishes these once a day, with the size split by storage type.
+func fetchS3StorageMetrics(ctx context.Context, cfg aws.Config) (map[string]*s3Storage, error) {
+	svc := cloudwatch.NewFromConfig(cfg)
+
+	var metrics []cwtypes.Metric
+	for _, name := range []string{"BucketSizeBytes", "NumberOfObjects"} {
+		paginator := cloudwatch.NewListMetricsPaginator(svc, &cloudwatch.ListMetricsInput{
+			Namespace:  aws.String("AWS/S3"),
+			MetricName: aws.String(name),
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			metrics = append(metrics, page.Metrics...)
+		}
+	}
+
+	storage := map[string]*s3Storage{}
+	end := time.Now()
+	for start := 0; start < len(metrics); start += s3MetricQueriesLimit {
+		batch := metrics[start:min(start+s3MetricQueriesLimit, len(metrics))]
+		queries := make([]cwtypes.MetricDataQuery, len(batch))
+		byID := map[string]cwtypes.Metric{}
+		for i := range batch {
+			id := fmt.Sprintf("m%d", i)
+			byID[id] = batch[i]
+			queries[i] = cwtypes.MetricDataQuery{
+				Id: aws.String(id),
+				MetricStat: &cwtypes.MetricStat{
+					Metric: &batch[i],
+					Period: aws.Int32(86400),
+					Stat:   aws.String("Average"),
+				},
+			}
+		}
+
+		paginator := cloudwatch.NewGetMetricDataPaginator(svc, &cloudwatch.GetMetricDataInput{
+			MetricDataQueries: queries,
+			StartTime:         aws.Time(end.Add(-3 * 24 * time.Hour)),
+			EndTime:           aws.Time(end),
+			ScanBy:            cwtypes.ScanByTimestampDescending,
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			// Values are newest first, so only the first one seen for each
+			// query is kept.
+			for _, result := range page.MetricDataResults {
+				metric, ok := byID[aws.ToString(result.Id)]
+				if !ok || len(result.Values) == 0 {
+					continue
+				}
+				delete(byID, aws.ToString(result.Id))
+				addS3Metric(storage, metric, int64(result.Values[0]))
+			}
+		}
+	}
+	return storage, nil
+}
+
+func addS3Metric(storage map[string]*s3Storage, metric cwtypes.Metric, value int64) {
+	var bucket, storageType string
+	for _, dimension := range metric.Dimensions {
+		switch aws.ToString(dimension.Name) {
+		case "BucketName":
+			bucket = aws.ToString(dimension.Value)
+		case "StorageType":
+			storageType = aws.ToString(dimension.Value)
+		}
+	}
+	if bucket == "" {
+		return
+	}
+
+	s, ok := storage[bucket]
+	if !ok {
+		s = &s3Storage{byStorageType: map[string]int64{}}
+		storage[bucket] = s
+	}
+	switch aws.ToString(metric.MetricName) {
+	case "BucketSizeBytes":
+		s.byStorageType[storageType] = value
+		s.sizeBytes += value
+	case "NumberOfObjects":
+		if storageType == "AllStorageTypes" {
+			s.objects = value
+		}
+	}
+}
@@ -0,0 +1,818 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// Vault types of backup policies and protected items.
+const (
+	VaultTypeRecoveryServices = "RecoveryServices"
+	VaultTypeBackupVault      = "BackupVault"
+)
+
+const (
+	recoveryServicesAPIVersion = "2024-04-01"
+	dataProtectionAPIVersion   = "2024-04-01"
+)
+
+// RecoveryServicesVaultInfo is a Recovery Services vault, which backs up
+// VMs, SQL Server and SAP HANA in VMs, and Azure file shares.
+type RecoveryServicesVaultInfo struct {
+	ResourceInfo
+	SKU                     string `json:",omitempty"`
+	Redundancy              string `json:",omitempty"`
+	CrossRegionRestore      bool
+	SoftDelete              string `json:",omitempty"`
+	SoftDeleteRetentionDays int32  `json:",omitempty"`
+	Immutability            string `json:",omitempty"`
+	PublicNetworkAccess     string `json:",omitempty"`
+	ProvisioningState       string `json:",omitempty"`
+	ProtectedItems          int
+}
+
+// BackupVaultInfo is a Data Protection Backup vault, which backs up managed
+// disks, blobs, AKS clusters and Azure Database for PostgreSQL.
+type BackupVaultInfo struct {
+	ResourceInfo
+	Redundancy              string `json:",omitempty"`
+	CrossRegionRestore      bool
+	SoftDelete              string `json:",omitempty"`
+	SoftDeleteRetentionDays int32  `json:",omitempty"`
+	Immutability            string `json:",omitempty"`
+	ProvisioningState       string `json:",omitempty"`
+	ProtectedItems          int
+}
+
+type BackupPolicyInfo struct {
+	ResourceInfo
+	Vault          string
+	VaultType      string
+	WorkloadType   string `json:",omitempty"`
+	Schedule       string `json:",omitempty"`
+	Retention      string `json:",omitempty"`
+	ProtectedItems int
+}
+
+// ProtectedItemInfo is a resource backed up to a vault. SourceResourceID is
+// the VM, database, file share or disk it protects.
+type ProtectedItemInfo struct {
+	ResourceInfo
+	Vault             string
+	VaultType         string
+	WorkloadType      string
+	SourceResourceID  string
+	Policy            string `json:",omitempty"`
+	ProtectionState   string `json:",omitempty"`
+	HealthStatus      string `json:",omitempty"`
+	LastBackupStatus  string `json:",omitempty"`
+	LastBackupTime    string `json:",omitempty"`
+	LastRecoveryPoint string `json:",omitempty"`
+	SoftDeleted       bool
+}
+
+// BackupCoverage records a protected item that backs up a resource. Item
+// names what is backed up when it is not the whole resource, such as a file
+// share of a storage account or a SQL Server database in a VM.
+type BackupCoverage struct {
+	Item             string `json:",omitempty"`
+	WorkloadType     string `json:",omitempty"`
+	Vault            string
+	Policy           string `json:",omitempty"`
+	ProtectionState  string `json:",omitempty"`
+	LastBackupStatus string `json:",omitempty"`
+	LastBackupTime   string `json:",omitempty"`
+}
+
+// The Recovery Services and Data Protection APIs are read through the ARM
+// pipeline directly, into the minimal shapes below.
+
+type armResource[P any] struct {
+	ID       string            `json:"id"`
+	Name     string            `json:"name"`
+	Location string            `json:"location"`
+	Tags     map[string]string `json:"tags"`
+	SKU      struct {
+		Name string `json:"name"`
+	} `json:"sku"`
+	Properties P `json:"properties"`
+}
+
+type rsVaultProperties struct {
+	ProvisioningState   string `json:"provisioningState"`
+	PublicNetworkAccess string `json:"publicNetworkAccess"`
+	RedundancySettings  struct {
+		StandardTierStorageRedundancy string `json:"standardTierStorageRedundancy"`
+		CrossRegionRestore            string `json:"crossRegionRestore"`
+	} `json:"redundancySettings"`
+	SecuritySettings struct {
+		ImmutabilitySettings struct {
+			State string `json:"state"`
+		} `json:"immutabilitySettings"`
+		SoftDeleteSettings struct {
+			SoftDeleteState                 string `json:"softDeleteState"`
+			SoftDeleteRetentionPeriodInDays int32  `json:"softDeleteRetentionPeriodInDays"`
+		} `json:"softDeleteSettings"`
+	} `json:"securitySettings"`
+}
+
+type rsVaultConfigProperties struct {
+	SoftDeleteFeatureState string `json:"softDeleteFeatureState"`
+}
+
+type rsProtectedItemProperties struct {
+	FriendlyName                 string `json:"friendlyName"`
+	BackupManagementType         string `json:"backupManagementType"`
+	WorkloadType                 string `json:"workloadType"`
+	SourceResourceID             string `json:"sourceResourceId"`
+	PolicyID                     string `json:"policyId"`
+	PolicyName                   string `json:"policyName"`
+	ProtectionState              string `json:"protectionState"`
+	HealthStatus                 string `json:"healthStatus"`
+	LastBackupStatus             string `json:"lastBackupStatus"`
+	LastBackupTime               string `json:"lastBackupTime"`
+	LastRecoveryPoint            string `json:"lastRecoveryPoint"`
+	IsScheduledForDeferredDelete bool   `json:"isScheduledForDeferredDelete"`
+}
+
+type rsPolicyProperties struct {
+	BackupManagementType string             `json:"backupManagementType"`
+	WorkLoadType         string             `json:"workLoadType"`
+	ProtectedItemsCount  int                `json:"protectedItemsCount"`
+	SchedulePolicy       *rsSchedulePolicy  `json:"schedulePolicy"`
+	RetentionPolicy      *rsRetentionPolicy `json:"retentionPolicy"`
+	SubProtectionPolicy  []struct {
+		PolicyType      string             `json:"policyType"`
+		SchedulePolicy  *rsSchedulePolicy  `json:"schedulePolicy"`
+		RetentionPolicy *rsRetentionPolicy `json:"retentionPolicy"`
+	} `json:"subProtectionPolicy"`
+}
+
+type rsSchedulePolicy struct {
+	ScheduleRunFrequency string   `json:"scheduleRunFrequency"`
+	ScheduleRunTimes     []string `json:"scheduleRunTimes"`
+	HourlySchedule       *struct {
+		Interval int `json:"interval"`
+	} `json:"hourlySchedule"`
+}
+
+type rsRetention struct {
+	RetentionDuration *rsDuration `json:"retentionDuration"`
+}
+
+type rsRetentionPolicy struct {
+	RetentionDuration *rsDuration  `json:"retentionDuration"`
+	DailySchedule     *rsRetention `json:"dailySchedule"`
+	WeeklySchedule    *rsRetention `json:"weeklySchedule"`
+	MonthlySchedule   *rsRetention `json:"monthlySchedule"`
+	YearlySchedule    *rsRetention `json:"yearlySchedule"`
+}
+
+type rsDuration struct {
+	Count        int    `json:"count"`
+	DurationType string `json:"durationType"`
+}
+
+type rsRecoveryPointProperties struct {
+	ObjectType               string `json:"objectType"`
+	RecoveryPointType        string `json:"recoveryPointType"`
+	RecoveryPointTime        string `json:"recoveryPointTime"`
+	RecoveryPointTimeInUTC   string `json:"recoveryPointTimeInUTC"`
+	RecoveryPointTierDetails []struct {
+		Type   string `json:"type"`
+		Status string `json:"status"`
+	} `json:"recoveryPointTierDetails"`
+}
+
+type dpVaultProperties struct {
+	ProvisioningState string `json:"provisioningState"`
+	StorageSettings   []struct {
+		DatastoreType string `json:"datastoreType"`
+		Type          string `json:"type"`
+	} `json:"storageSettings"`
+	SecuritySettings struct {
+		SoftDeleteSettings struct {
+			State                   string  `json:"state"`
+			RetentionDurationInDays float64 `json:"retentionDurationInDays"`
+		} `json:"softDeleteSettings"`
+		ImmutabilitySettings struct {
+			State string `json:"state"`
+		} `json:"immutabilitySettings"`
+	} `json:"securitySettings"`
+	FeatureSettings struct {
+		CrossRegionRestoreSettings struct {
+			State string `json:"state"`
+		} `json:"crossRegionRestoreSettings"`
+	} `json:"featureSettings"`
+}
+
+type dpInstanceProperties struct {
+	FriendlyName   string `json:"friendlyName"`
+	DataSourceInfo struct {
+		ResourceID     string `json:"resourceID"`
+		ResourceName   string `json:"resourceName"`
+		DatasourceType string `json:"datasourceType"`
+	} `json:"dataSourceInfo"`
+	PolicyInfo struct {
+		PolicyID string `json:"policyId"`
+	} `json:"policyInfo"`
+	ProtectionStatus struct {
+		Status string `json:"status"`
+	} `json:"protectionStatus"`
+	CurrentProtectionState string `json:"currentProtectionState"`
+}
+
+type dpJobProperties struct {
+	BackupInstanceID  string `json:"backupInstanceId"`
+	OperationCategory string `json:"operationCategory"`
+	Status            string `json:"status"`
+	StartTime         string `json:"startTime"`
+}
+
+// dpBackupJob is the latest backup job of a Backup vault instance.
+type dpBackupJob struct {
+	Status string
+	Start  time.Time
+}
+
+type dpPolicyProperties struct {
+	DatasourceTypes []string `json:"datasourceTypes"`
+	PolicyRules     []struct {
+		ObjectType string `json:"objectType"`
+		IsDefault  bool   `json:"isDefault"`
+		Lifecycles []struct {
+			DeleteAfter struct {
+				Duration string `json:"duration"`
+			} `json:"deleteAfter"`
+		} `json:"lifecycles"`
+		Trigger struct {
+			Schedule struct {
+				RepeatingTimeIntervals []string `json:"repeatingTimeIntervals"`
+			} `json:"schedule"`
+		} `json:"trigger"`
+	} `json:"policyRules"`
+}
+
+type dpRecoveryPointProperties struct {
+	RecoveryPointTime              string `json:"recoveryPointTime"`
+	RecoveryPointType              string `json:"recoveryPointType"`
+	RecoveryPointDataStoresDetails []struct {
+		Type string `json:"type"`
+	} `json:"recoveryPointDataStoresDetails"`
+}
+
+func newARMClient(subscription Subscription) (*arm.Client, error) {
+	options := &arm.ClientOptions{ClientOptions: policy.ClientOptions{Telemetry: policy.TelemetryOptions{Disabled: true}}}
+	client, err := arm.NewClient("kollect", "", subscription.cred, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ARM client: %v", err)
+	}
+	return client, nil
+}
+
+// listARM lists every item of an ARM collection, following nextLink.
+func listARM[T any](ctx context.Context, client *arm.Client, path, apiVersion string) ([]T, error) {
+	var items []T
+	url := runtime.JoinPaths(client.Endpoint(), path) + "?api-version=" + apiVersion
+	for url != "" {
+		var page struct {
+			Value    []T    `json:"value"`
+			NextLink string `json:"nextLink"`
+		}
+		if err := getARM(ctx, client, url, &page); err != nil {
+			return items, err
+		}
+		items = append(items, page.Value...)
+		url = page.NextLink
+	}
+	return items, nil
+}
+
+func getARM(ctx context.Context, client *arm.Client, url string, v interface{}) error {
+	req, err := runtime.NewRequest(ctx, http.MethodGet, url)
+	if err != nil {
+		return err
+	}
+	req.Raw().Header.Set("Accept", "application/json")
+	resp, err := client.Pipeline().Do(req)
+	if err != nil {
+		return err
+	}
+	if !runtime.HasStatusCode(resp, http.StatusOK) {
+		return runtime.NewResponseError(resp)
+	}
+	return runtime.UnmarshalAsJSON(resp, v)
+}
+
+// collectBackups collects the Recovery Services vaults, Backup vaults, their
+// policies and protected items of one subscription. The two kinds of vault
+// are listed independently, so failing to list one does not hide the other;
+// anything inside a vault that cannot be read is logged and skipped.
+func collectBackups(ctx context.Context, subscription Subscription) (AzureData, error) {
+	var data AzureData
+	client, err := newARMClient(subscription)
+	if err != nil {
+		return data, err
+	}
+	rsErr := data.addRecoveryServicesVaults(ctx, client, subscription)
+	dpErr := data.addBackupVaults(ctx, client, subscription)
+	return data, errors.Join(rsErr, dpErr)
+}
+
+// addRecoveryServicesVaults adds the Recovery Services vaults of a
+// subscription, with their protected items and policies.
+func (d *AzureData) addRecoveryServicesVaults(ctx context.Context, client *arm.Client, subscription Subscription) error {
+	tag := subscription.subscribed()
+	rsVaults, err := listARM[armResource[rsVaultProperties]](ctx, client,
+		"/subscriptions/"+subscription.ID+"/providers/Microsoft.RecoveryServices/vaults", recoveryServicesAPIVersion)
+	if err != nil {
+		return fmt.Errorf("failed to list Recovery Services vaults: %v", err)
+	}
+	for _, vault := range rsVaults {
+		info := rsVaultInfo(vault, tag)
+		if info.SoftDelete == "" {
+			var config armResource[rsVaultConfigProperties]
+			url := runtime.JoinPaths(client.Endpoint(), vault.ID, "backupconfig/vaultconfig") + "?api-version=" + recoveryServicesAPIVersion
+			if err := getARM(ctx, client, url, &config); err != nil {
+				log.Printf("Warning: Failed to get soft delete state of vault %s: %v", vault.Name, err)
+			} else {
+				info.SoftDelete = config.Properties.SoftDeleteFeatureState
+			}
+		}
+
+		items, err := listARM[armResource[rsProtectedItemProperties]](ctx, client, vault.ID+"/backupProtectedItems", recoveryServicesAPIVersion)
+		if err != nil {
+			log.Printf("Warning: Failed to get protected items of vault %s: %v", vault.Name, err)
+		}
+		for _, item := range items {
+			d.ProtectedItems = append(d.ProtectedItems, rsProtectedItemInfo(item, vault.Name, tag))
+		}
+		info.ProtectedItems = len(items)
+
+		policies, err := listARM[armResource[rsPolicyProperties]](ctx, client, vault.ID+"/backupPolicies", recoveryServicesAPIVersion)
+		if err != nil {
+			log.Printf("Warning: Failed to get backup policies of vault %s: %v", vault.Name, err)
+		}
+		for _, p := range policies {
+			d.BackupPolicies = append(d.BackupPolicies, rsPolicyInfo(p, vault.Name, tag))
+		}
+
+		d.RecoveryServicesVaults = append(d.RecoveryServicesVaults, info)
+	}
+	return nil
+}
+
+// addBackupVaults adds the Data Protection Backup vaults of a subscription,
+// with their backup instances and policies.
+func (d *AzureData) addBackupVaults(ctx context.Context, client *arm.Client, subscription Subscription) error {
+	tag := subscription.subscribed()
+	dpVaults, err := listARM[armResource[dpVaultProperties]](ctx, client,
+		"/subscriptions/"+subscription.ID+"/providers/Microsoft.DataProtection/backupVaults", dataProtectionAPIVersion)
+	if err != nil {
+		return fmt.Errorf("failed to list Backup vaults: %v", err)
+	}
+	for _, vault := range dpVaults {
+		info := dpVaultInfo(vault, tag)
+
+		policies, err := listARM[armResource[dpPolicyProperties]](ctx, client, vault.ID+"/backupPolicies", dataProtectionAPIVersion)
+		if err != nil {
+			log.Printf("Warning: Failed to get backup policies of vault %s: %v", vault.Name, err)
+		}
+		policyNames := map[string]string{}
+		for _, p := range policies {
+			policyNames[strings.ToLower(p.ID)] = p.Name
+		}
+
+		instances, err := listARM[armResource[dpInstanceProperties]](ctx, client, vault.ID+"/backupInstances", dataProtectionAPIVersion)
+		if err != nil {
+			log.Printf("Warning: Failed to get backup instances of vault %s: %v", vault.Name, err)
+		}
+		lastBackups := dpLastBackups(ctx, client, vault)
+		protected := map[string]int{}
+		for _, instance := range instances {
+			item := dpProtectedItemInfo(instance, vault.Name, tag)
+			if job, ok := lastBackups[strings.ToLower(instance.Name)]; ok {
+				item.LastBackupStatus = job.Status
+				item.LastBackupTime = formatTime(&job.Start)
+			}
+			item.Policy = policyNames[strings.ToLower(instance.Properties.PolicyInfo.PolicyID)]
+			if item.Policy == "" {
+				item.Policy = resourceName(instance.Properties.PolicyInfo.PolicyID)
+			}
+			protected[strings.ToLower(item.Policy)]++
+			d.ProtectedItems = append(d.ProtectedItems, item)
+		}
+		info.ProtectedItems = len(instances)
+
+		for _, p := range policies {
+			policyInfo := dpPolicyInfo(p, vault.Name, tag)
+			policyInfo.ProtectedItems = protected[strings.ToLower(p.Name)]
+			d.BackupPolicies = append(d.BackupPolicies, policyInfo)
+		}
+
+		d.BackupVaults = append(d.BackupVaults, info)
+	}
+	return nil
+}
+
+// dpLastBackups returns the latest backup job of each instance in a Backup
+// vault, by lower-cased instance name. Unlike Recovery Services protected
+// items, backup instances do not record their last backup themselves.
+func dpLastBackups(ctx context.Context, client *arm.Client, vault armResource[dpVaultProperties]) map[string]dpBackupJob {
+	jobs, err := listARM[armResource[dpJobProperties]](ctx, client, vault.ID+"/backupJobs", dataProtectionAPIVersion)
+	if err != nil {
+		log.Printf("Warning: Failed to get backup jobs of vault %s: %v", vault.Name, err)
+	}
+	latest := map[string]dpBackupJob{}
+	for _, job := range jobs {
+		props := job.Properties
+		if !strings.EqualFold(props.OperationCategory, "Backup") {
+			continue
+		}
+		start, err := time.Parse(time.RFC3339Nano, props.StartTime)
+		if err != nil {
+			continue
+		}
+		name := strings.ToLower(resourceName(props.BackupInstanceID))
+		if last, ok := latest[name]; !ok || start.After(last.Start) {
+			latest[name] = dpBackupJob{Status: props.Status, Start: start}
+		}
+	}
+	return latest
+}
+
+// collectRecoveryPoints lists the recovery points of a protected item, for
+// Snapshot Hunter.
+func collectRecoveryPoints(ctx context.Context, client *arm.Client, item ProtectedItemInfo) ([]map[string]string, error) {
+	var recoveryPoints []map[string]string
+	newRecoveryPoint := func(id, name, created string) map[string]string {
+		return map[string]string{
+			"ID":               id,
+			"Name":             name,
+			"ProtectedItem":    item.Name,
+			"SourceResourceId": item.SourceResourceID,
+			"WorkloadType":     item.WorkloadType,
+			"Vault":            item.Vault,
+			"VaultType":        item.VaultType,
+			"ResourceGroup":    item.ResourceGroup,
+			"CreationTime":     created,
+			"SubscriptionId":   item.SubscriptionID,
+			"SubscriptionName": item.SubscriptionName,
+		}
+	}
+
+	if item.VaultType == VaultTypeBackupVault {
+		points, err := listARM[armResource[dpRecoveryPointProperties]](ctx, client, item.ID+"/recoveryPoints", dataProtectionAPIVersion)
+		for _, point := range points {
+			recoveryPoint := newRecoveryPoint(point.ID, point.Name, point.Properties.RecoveryPointTime)
+			recoveryPoint["RecoveryPointType"] = point.Properties.RecoveryPointType
+			var tiers []string
+			for _, store := range point.Properties.RecoveryPointDataStoresDetails {
+				tiers = append(tiers, store.Type)
+			}
+			recoveryPoint["Tier"] = strings.Join(tiers, ", ")
+			recoveryPoints = append(recoveryPoints, recoveryPoint)
+		}
+		return recoveryPoints, err
+	}
+
+	points, err := listARM[armResource[rsRecoveryPointProperties]](ctx, client, item.ID+"/recoveryPoints", recoveryServicesAPIVersion)
+	for _, point := range points {
+		created := point.Properties.RecoveryPointTime
+		if created == "" {
+			created = point.Properties.RecoveryPointTimeInUTC
+		}
+		recoveryPoint := newRecoveryPoint(point.ID, point.Name, created)
+		recoveryPoint["RecoveryPointType"] = point.Properties.RecoveryPointType
+		var tiers []string
+		for _, tier := range point.Properties.RecoveryPointTierDetails {
+			if tier.Status == "" || tier.Status == "Valid" {
+				tiers = append(tiers, tier.Type)
+			}
+		}
+		recoveryPoint["Tier"] = strings.Join(tiers, ", ")
+		recoveryPoints = append(recoveryPoints, recoveryPoint)
+	}
+	return recoveryPoints, err
+}
+
+func rsVaultInfo(vault armResource[rsVaultProperties], tag Subscribed) RecoveryServicesVaultInfo {
+	props := vault.Properties
+	info := RecoveryServicesVaultInfo{
+		ResourceInfo:            armResourceInfo(vault.ID, vault.Name, vault.Location, vault.Tags, tag),
+		SKU:                     vault.SKU.Name,
+		Redundancy:              props.RedundancySettings.StandardTierStorageRedundancy,
+		CrossRegionRestore:      props.RedundancySettings.CrossRegionRestore == "Enabled",
+		SoftDelete:              props.SecuritySettings.SoftDeleteSettings.SoftDeleteState,
+		SoftDeleteRetentionDays: props.SecuritySettings.SoftDeleteSettings.SoftDeleteRetentionPeriodInDays,
+		Immutability:            props.SecuritySettings.ImmutabilitySettings.State,
+		PublicNetworkAccess:     props.PublicNetworkAccess,
+		ProvisioningState:       props.ProvisioningState,
+	}
+	return info
+}
+
+func dpVaultInfo(vault armResource[dpVaultProperties], tag Subscribed) BackupVaultInfo {
+	props := vault.Properties
+	info := BackupVaultInfo{
+		ResourceInfo:            armResourceInfo(vault.ID, vault.Name, vault.Location, vault.Tags, tag),
+		CrossRegionRestore:      props.FeatureSettings.CrossRegionRestoreSettings.State == "Enabled",
+		SoftDelete:              props.SecuritySettings.SoftDeleteSettings.State,
+		SoftDeleteRetentionDays: int32(props.SecuritySettings.SoftDeleteSettings.RetentionDurationInDays),
+		Immutability:            props.SecuritySettings.ImmutabilitySettings.State,
+		ProvisioningState:       props.ProvisioningState,
+	}
+	var redundancy []string
+	for _, storage := range props.StorageSettings {
+		redundancy = append(redundancy, storage.Type)
+	}
+	info.Redundancy = strings.Join(redundancy, ", ")
+	return info
+}
+
+func rsProtectedItemInfo(item armResource[rsProtectedItemProperties], vault string, tag Subscribed) ProtectedItemInfo {
+	props := item.Properties
+	info := ProtectedItemInfo{
+		ResourceInfo:      armResourceInfo(item.ID, props.FriendlyName, item.Location, item.Tags, tag),
+		Vault:             vault,
+		VaultType:         VaultTypeRecoveryServices,
+		WorkloadType:      props.WorkloadType,
+		SourceResourceID:  props.SourceResourceID,
+		Policy:            props.PolicyName,
+		ProtectionState:   props.ProtectionState,
+		HealthStatus:      props.HealthStatus,
+		LastBackupStatus:  props.LastBackupStatus,
+		LastBackupTime:    props.LastBackupTime,
+		LastRecoveryPoint: props.LastRecoveryPoint,
+		SoftDeleted:       props.IsScheduledForDeferredDelete,
+	}
+	if info.Name == "" {
+		info.Name = item.Name
+	}
+	if info.Policy == "" {
+		info.Policy = resourceName(props.PolicyID)
+	}
+	if info.WorkloadType == "" {
+		info.WorkloadType = props.BackupManagementType
+	}
+	return info
+}
+
+func dpProtectedItemInfo(instance armResource[dpInstanceProperties], vault string, tag Subscribed) ProtectedItemInfo {
+	props := instance.Properties
+	info := ProtectedItemInfo{
+		ResourceInfo:     armResourceInfo(instance.ID, props.FriendlyName, instance.Location, instance.Tags, tag),
+		Vault:            vault,
+		VaultType:        VaultTypeBackupVault,
+		WorkloadType:     props.DataSourceInfo.DatasourceType,
+		SourceResourceID: props.DataSourceInfo.ResourceID,
+		ProtectionState:  props.CurrentProtectionState,
+		HealthStatus:     props.ProtectionStatus.Status,
+	}
+	if info.Name == "" {
+		info.Name = props.DataSourceInfo.ResourceName
+	}
+	return info
+}
+
+func rsPolicyInfo(p armResource[rsPolicyProperties], vault string, tag Subscribed) BackupPolicyInfo {
+	props := p.Properties
+	info := BackupPolicyInfo{
+		ResourceInfo:   armResourceInfo(p.ID, p.Name, p.Location, p.Tags, tag),
+		Vault:          vault,
+		VaultType:      VaultTypeRecoveryServices,
+		WorkloadType:   props.WorkLoadType,
+		ProtectedItems: props.ProtectedItemsCount,
+	}
+	if info.WorkloadType == "" {
+		info.WorkloadType = props.BackupManagementType
+	}
+
+	schedule, retention := props.SchedulePolicy, props.RetentionPolicy
+	for _, sub := range props.SubProtectionPolicy {
+		if schedule == nil || sub.PolicyType == "Full" {
+			schedule, retention = sub.SchedulePolicy, sub.RetentionPolicy
+		}
+	}
+	info.Schedule = rsScheduleSummary(schedule)
+	info.Retention = rsRetentionSummary(retention)
+	return info
+}
+
+func dpPolicyInfo(p armResource[dpPolicyProperties], vault string, tag Subscribed) BackupPolicyInfo {
+	info := BackupPolicyInfo{
+		ResourceInfo: armResourceInfo(p.ID, p.Name, p.Location, p.Tags, tag),
+		Vault:        vault,
+		VaultType:    VaultTypeBackupVault,
+		WorkloadType: strings.Join(p.Properties.DatasourceTypes, ", "),
+	}
+	var retention []string
+	for _, rule := range p.Properties.PolicyRules {
+		switch rule.ObjectType {
+		case "AzureBackupRule":
+			for _, interval := range rule.Trigger.Schedule.RepeatingTimeIntervals {
+				info.Schedule = repeatingIntervalSummary(interval)
+			}
+		case "AzureRetentionRule":
+			for _, lifecycle := range rule.Lifecycles {
+				if duration := isoDurationSummary(lifecycle.DeleteAfter.Duration); duration != "" {
+					retention = append(retention, duration)
+				}
+			}
+		}
+	}
+	info.Retention = strings.Join(retention, ", ")
+	return info
+}
+
+// rsScheduleSummary describes a schedule as e.g. "Daily at 02:00".
+func rsScheduleSummary(schedule *rsSchedulePolicy) string {
+	if schedule == nil {
+		return ""
+	}
+	summary := schedule.ScheduleRunFrequency
+	if schedule.HourlySchedule != nil && schedule.HourlySchedule.Interval > 0 {
+		summary = fmt.Sprintf("Every %d hours", schedule.HourlySchedule.Interval)
+	}
+	if len(schedule.ScheduleRunTimes) > 0 {
+		if at := clockTime(schedule.ScheduleRunTimes[0]); at != "" {
+			summary += " at " + at
+		}
+	}
+	return summary
+}
+
+// rsRetentionSummary describes a retention policy as e.g.
+// "30 days, 12 weeks, 12 months".
+func rsRetentionSummary(retention *rsRetentionPolicy) string {
+	if retention == nil {
+		return ""
+	}
+	var durations []string
+	if retention.RetentionDuration != nil {
+		durations = append(durations, retention.RetentionDuration.String())
+	}
+	for _, schedule := range []*rsRetention{retention.DailySchedule, retention.WeeklySchedule, retention.MonthlySchedule, retention.YearlySchedule} {
+		if schedule != nil && schedule.RetentionDuration != nil {
+			durations = append(durations, schedule.RetentionDuration.String())
+		}
+	}
+	return strings.Join(durations, ", ")
+}
+
+func (d rsDuration) String() string {
+	return fmt.Sprintf("%d %s", d.Count, strings.ToLower(d.DurationType))
+}
+
+var isoDurationPattern = regexp.MustCompile(`^P(?:T(\d+)H|(\d+)([DWMY]))$`)
+
+// isoDurationSummary turns a simple ISO 8601 duration such as "P30D" into
+// "30 days". Durations it does not recognise are returned unchanged.
+func isoDurationSummary(duration string) string {
+	match := isoDurationPattern.FindStringSubmatch(duration)
+	if match == nil {
+		return duration
+	}
+	if match[1] != "" {
+		return match[1] + " hours"
+	}
+	unit := map[string]string{"D": "days", "W": "weeks", "M": "months", "Y": "years"}[match[3]]
+	return match[2] + " " + unit
+}
+
+// repeatingIntervalSummary describes an ISO 8601 repeating interval such as
+// "R/2024-01-01T02:00:00+00:00/P1D" as "Every 1 days at 02:00".
+func repeatingIntervalSummary(interval string) string {
+	parts := strings.Split(interval, "/")
+	if len(parts) != 3 {
+		return interval
+	}
+	summary := "Every " + isoDurationSummary(parts[2])
+	if at := clockTime(parts[1]); at != "" {
+		summary += " at " + at
+	}
+	return summary
+}
+
+// clockTime returns the HH:MM of an RFC 3339 timestamp.
+func clockTime(timestamp string) string {
+	if i := strings.Index(timestamp, "T"); i >= 0 && len(timestamp) >= i+6 {
+		return timestamp[i+1 : i+6]
+	}
+	return ""
+}
+
+func armResourceInfo(id, name, location string, tags map[string]string, tag Subscribed) ResourceInfo {
+	info := ResourceInfo{
+		Name:             name,
+		ID:               id,
+		ResourceGroup:    getResourceGroupFromID(id),
+		Location:         location,
+		SubscriptionID:   tag.SubscriptionID,
+		SubscriptionName: tag.SubscriptionName,
+	}
+	if len(tags) > 0 {
+		info.Tags = tags
+	}
+	return info
+}
+
+// backupCoverage indexes the protected items by the lower-cased ID of the
+// resource they protect. Several items can share a source: a VM has one for
+// the VM itself and one for each SQL Server or SAP HANA database backed up
+// inside it, and a storage account has one for each file share.
+func backupCoverage(items []ProtectedItemInfo) map[string][]BackupCoverage {
+	coverage := map[string][]BackupCoverage{}
+	for _, item := range items {
+		if item.SourceResourceID == "" || item.SoftDeleted {
+			continue
+		}
+		id := strings.ToLower(item.SourceResourceID)
+		coverage[id] = append(coverage[id], BackupCoverage{
+			Item:             item.Name,
+			WorkloadType:     item.WorkloadType,
+			Vault:            item.Vault,
+			Policy:           item.Policy,
+			ProtectionState:  item.ProtectionState,
+			LastBackupStatus: item.LastBackupStatus,
+			LastBackupTime:   item.LastBackupTime,
+		})
+	}
+	return coverage
+}
+
+// isVMBackup reports whether a protected item backs up a whole VM, rather
+// than a database inside it.
+func isVMBackup(coverage BackupCoverage) bool {
+	return coverage.WorkloadType == "VM" || coverage.WorkloadType == "AzureIaasVM"
+}
+
+// isFileShareBackup reports whether a protected item backs up a file share
+// of a storage account.
+func isFileShareBackup(coverage BackupCoverage) bool {
+	return coverage.WorkloadType == "AzureFileShare" || coverage.WorkloadType == "AzureStorage"
+}
+
+// backupVaultMaps describes the vaults for Snapshot Hunter.
+func backupVaultMaps(data AzureData) []map[string]string {
+	var vaults []map[string]string
+	for _, vault := range data.RecoveryServicesVaults {
+		vaults = append(vaults, backupVaultMap(vault.ResourceInfo, VaultTypeRecoveryServices, vault.Redundancy,
+			vault.SoftDelete, vault.Immutability, vault.ProtectedItems))
+	}
+	for _, vault := range data.BackupVaults {
+		vaults = append(vaults, backupVaultMap(vault.ResourceInfo, VaultTypeBackupVault, vault.Redundancy,
+			vault.SoftDelete, vault.Immutability, vault.ProtectedItems))
+	}
+	return vaults
+}
+
+func backupVaultMap(info ResourceInfo, vaultType, redundancy, softDelete, immutability string, protectedItems int) map[string]string {
+	return map[string]string{
+		"ID":               info.ID,
+		"Name":             info.Name,
+		"VaultType":        vaultType,
+		"ResourceGroup":    info.ResourceGroup,
+		"Location":         info.Location,
+		"Redundancy":       redundancy,
+		"SoftDelete":       softDelete,
+		"Immutability":     immutability,
+		"ProtectedItems":   strconv.Itoa(protectedItems),
+		"SubscriptionId":   info.SubscriptionID,
+		"SubscriptionName": info.SubscriptionName,
+	}
+}
+
+// protectedItemMaps describes the protected items for Snapshot Hunter.
+func protectedItemMaps(items []ProtectedItemInfo) []map[string]string {
+	var result []map[string]string
+	for _, item := range items {
+		result = append(result, map[string]string{
+			"ID":               item.ID,
+			"Name":             item.Name,
+			"WorkloadType":     item.WorkloadType,
+			"SourceResourceId": item.SourceResourceID,
+			"Vault":            item.Vault,
+			"VaultType":        item.VaultType,
+			"Policy":           item.Policy,
+			"ProtectionState":  item.ProtectionState,
+			"LastBackupStatus": item.LastBackupStatus,
+			"LastBackupTime":   item.LastBackupTime,
+			"SoftDeleted":      strconv.FormatBool(item.SoftDeleted),
+			"SubscriptionId":   item.SubscriptionID,
+			"SubscriptionName": item.SubscriptionName,
+		})
+	}
+	return result
+}
@@ -0,0 +1,101 @@
+package azure
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/michaelcade/kollect/pkg/collector"
+)
+
+type azureCollector struct{}
+
+func init() {
+	collector.Register(azureCollector{})
+}
+
+// allowList returns the subscriptions listed in opts, which may be a comma
+// separated list of IDs or names.
+func allowList(opts collector.Options) []string {
+	var names []string
+	for _, name := range strings.Split(opts.AzureSubscription, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (azureCollector) Name() string {
+	return "azure"
+}
+
+func (azureCollector) CheckCredentials(ctx context.Context, opts collector.Options) (bool, error) {
+	return CheckCredentials(ctx, allowList(opts), opts.AzureManagementGroup)
+}
+
+func (azureCollector) Collect(ctx context.Context, opts collector.Options) (interface{}, error) {
+	subscriptions, err := Subscriptions(ctx, allowList(opts), opts.AzureManagementGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	var data AzureData
+	if opts.AzureResourceGraph {
+		data, err = CollectResourceGraphData(ctx, subscriptions)
+		if err != nil {
+			log.Printf("Warning: Resource Graph query failed, listing each resource type instead: %v", err)
+		}
+	}
+	if !opts.AzureResourceGraph || err != nil {
+		data, err = CollectSubscriptionsData(ctx, subscriptions)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !opts.AzureRaw {
+		data.dropRaw()
+	}
+	return data, nil
+}
+
+func (azureCollector) CollectSnapshots(ctx context.Context, opts collector.Options) (map[string]interface{}, error) {
+	subscriptions, err := Subscriptions(ctx, allowList(opts), opts.AzureManagementGroup)
+	if err != nil {
+		return nil, err
+	}
+	return CollectSubscriptionsSnapshotData(ctx, subscriptions)
+}
+
+func (azureCollector) Identity(ctx context.Context, opts collector.Options, data interface{}) (map[string]string, error) {
+	var subscriptions []SubscriptionInfo
+	if azureData, ok := data.(AzureData); ok {
+		subscriptions = azureData.Subscriptions
+	}
+	if len(subscriptions) == 0 {
+		resolved, err := Subscriptions(ctx, allowList(opts), opts.AzureManagementGroup)
+		if err != nil {
+			return nil, err
+		}
+		for _, subscription := range resolved {
+			subscriptions = append(subscriptions, subscription.info(nil))
+		}
+	}
+
+	result := map[string]string{
+		"subscriptionId":   subscriptions[0].SubscriptionID,
+		"subscriptionName": subscriptions[0].SubscriptionName,
+		"tenantId":         subscriptions[0].TenantID,
+	}
+	if opts.AzureManagementGroup != "" {
+		result["managementGroup"] = opts.AzureManagementGroup
+	}
+	if len(subscriptions) > 1 {
+		ids := make([]string, 0, len(subscriptions))
+		for _, subscription := range subscriptions {
+			ids = append(ids, subscription.SubscriptionID)
+		}
+		result["subscriptions"] = strings.Join(ids, ",")
+	}
+	return result, nil
+}
@@ -0,0 +1,327 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcegraph/armresourcegraph"
+)
+
+// Resource is any Azure resource found by Resource Graph.
+type Resource struct {
+	ID               string
+	Name             string
+	Type             string
+	Kind             string `json:",omitempty"`
+	ResourceGroup    string
+	Location         string
+	SKU              string            `json:",omitempty"`
+	Tags             map[string]string `json:",omitempty"`
+	SubscriptionID   string
+	SubscriptionName string
+}
+
+const (
+	// graphPageSize is the most rows Resource Graph returns per page.
+	graphPageSize = 1000
+	// graphSubscriptionBatch is the most subscriptions one query may name.
+	graphSubscriptionBatch = 1000
+
+	graphResourcesQuery = `Resources
+| project id, name, type, kind, location, resourceGroup, subscriptionId, tenantId, tags, sku, plan, identity, zones, extendedLocation, managedBy, properties
+| order by id asc`
+	graphResourceGroupsQuery = `ResourceContainers
+| where type =~ 'microsoft.resources/subscriptions/resourcegroups'
+| project id, name, type, location, tags, managedBy, properties, subscriptionId
+| order by id asc`
+)
+
+// CollectResourceGraphData collects every resource of the subscriptions with
+// Resource Graph queries instead of one ARM list per type. Every resource is
+// listed in Resources, and those of the types kollect knows are also decoded
+// into the typed slices. Blob containers, SQL retention policies and
+// replication links, and backup vault contents are not in Resource Graph, so
+// they are still listed through ARM.
+func CollectResourceGraphData(ctx context.Context, subscriptions []Subscription) (AzureData, error) {
+	var data AzureData
+	if len(subscriptions) == 0 {
+		return data, fmt.Errorf("no Azure subscriptions to query")
+	}
+
+	client, err := armresourcegraph.NewClient(subscriptions[0].cred, nil)
+	if err != nil {
+		return data, fmt.Errorf("failed to create Resource Graph client: %v", err)
+	}
+
+	ids := make([]string, 0, len(subscriptions))
+	byID := map[string]Subscription{}
+	for _, subscription := range subscriptions {
+		ids = append(ids, subscription.ID)
+		byID[strings.ToLower(subscription.ID)] = subscription
+	}
+
+	log.Printf("Querying Azure Resource Graph across %d subscriptions", len(subscriptions))
+	err = queryResourceGraph(ctx, client, graphResourcesQuery, ids, func(row map[string]interface{}) {
+		subscription := byID[strings.ToLower(graphString(row, "subscriptionId"))]
+		data.Resources = append(data.Resources, graphResource(row, subscription.Name))
+		if err := data.addGraphRow(row, subscription.subscribed()); err != nil {
+			log.Printf("Warning: Failed to decode %s: %v", graphString(row, "id"), err)
+		}
+	})
+	if err != nil {
+		return AzureData{}, err
+	}
+
+	err = queryResourceGraph(ctx, client, graphResourceGroupsQuery, ids, func(row map[string]interface{}) {
+		subscription := byID[strings.ToLower(graphString(row, "subscriptionId"))]
+		group := ResourceGroup{Subscribed: subscription.subscribed()}
+		if err := decodeGraphRow(row, &group.ResourceGroup); err != nil {
+			log.Printf("Warning: Failed to decode %s: %v", graphString(row, "id"), err)
+			return
+		}
+		data.AzureResourceGroups = append(data.AzureResourceGroups, group)
+	})
+	if err != nil {
+		return AzureData{}, err
+	}
+
+	for _, subscription := range subscriptions {
+		var accounts []StorageAccount
+		for _, account := range data.AzureStorageAccounts {
+			if account.SubscriptionID == subscription.ID {
+				accounts = append(accounts, account)
+			}
+		}
+		data.AzureBlobContainers = append(data.AzureBlobContainers, collectBlobContainers(ctx, subscription, accounts)...)
+		data.addSQLDetails(ctx, subscription)
+		data.addBackups(ctx, subscription)
+		data.Subscriptions = append(data.Subscriptions, subscription.info(nil))
+	}
+	data.summarize()
+
+	log.Printf("Found %d Azure resources with Resource Graph", len(data.Resources))
+	return data, nil
+}
+
+// queryResourceGraph runs query against the subscriptions, in batches and
+// following skip tokens, and calls visit with every row.
+func queryResourceGraph(ctx context.Context, client *armresourcegraph.Client, query string, subscriptionIDs []string, visit func(map[string]interface{})) error {
+	for start := 0; start < len(subscriptionIDs); start += graphSubscriptionBatch {
+		end := min(start+graphSubscriptionBatch, len(subscriptionIDs))
+		request := armresourcegraph.QueryRequest{
+			Query:         to.Ptr(query),
+			Subscriptions: to.SliceOfPtrs(subscriptionIDs[start:end]...),
+			Options: &armresourcegraph.QueryRequestOptions{
+				ResultFormat: to.Ptr(armresourcegraph.ResultFormatObjectArray),
+				Top:          to.Ptr[int32](graphPageSize),
+			},
+		}
+
+		for {
+			response, err := client.Resources(ctx, request, nil)
+			if err != nil {
+				return fmt.Errorf("failed to query Resource Graph: %v", err)
+			}
+			rows, _ := response.Data.([]interface{})
+			for _, row := range rows {
+				if fields, ok := row.(map[string]interface{}); ok {
+					visit(fields)
+				}
+			}
+			if response.SkipToken == nil || *response.SkipToken == "" {
+				break
+			}
+			request.Options.SkipToken = response.SkipToken
+		}
+	}
+	return nil
+}
+
+// addGraphRow decodes a Resource Graph row into the typed slice for its
+// resource type, if kollect has one.
+func (d *AzureData) addGraphRow(row map[string]interface{}, tag Subscribed) error {
+	switch strings.ToLower(graphString(row, "type")) {
+	case "microsoft.compute/virtualmachines":
+		vm := VirtualMachine{Subscribed: tag}
+		if err := decodeGraphRow(row, &vm.VirtualMachine); err != nil {
+			return err
+		}
+		addGraphPowerState(row, &vm)
+		d.AzureVMs = append(d.AzureVMs, vm)
+	case "microsoft.compute/virtualmachinescalesets":
+		vmss := VirtualMachineScaleSet{Subscribed: tag}
+		if err := decodeGraphRow(row, &vmss.VirtualMachineScaleSet); err != nil {
+			return err
+		}
+		d.AzureVMSS = append(d.AzureVMSS, vmss)
+	case "microsoft.containerservice/managedclusters":
+		aks := ManagedCluster{Subscribed: tag}
+		if err := decodeGraphRow(row, &aks.ManagedCluster); err != nil {
+			return err
+		}
+		d.AzureAKSClusters = append(d.AzureAKSClusters, aks)
+	case "microsoft.storage/storageaccounts":
+		account := StorageAccount{Subscribed: tag}
+		if err := decodeGraphRow(row, &account.Account); err != nil {
+			return err
+		}
+		d.AzureStorageAccounts = append(d.AzureStorageAccounts, account)
+	case "microsoft.network/virtualnetworks":
+		vnet := VirtualNetwork{Subscribed: tag}
+		if err := decodeGraphRow(row, &vnet.VirtualNetwork); err != nil {
+			return err
+		}
+		d.AzureVirtualNetworks = append(d.AzureVirtualNetworks, vnet)
+	case "microsoft.network/networkinterfaces":
+		nic := NetworkInterface{Subscribed: tag}
+		if err := decodeGraphRow(row, &nic.Interface); err != nil {
+			return err
+		}
+		d.AzureNetworkInterfaces = append(d.AzureNetworkInterfaces, nic)
+	case "microsoft.network/networksecuritygroups":
+		nsg := NetworkSecurityGroup{Subscribed: tag}
+		if err := decodeGraphRow(row, &nsg.SecurityGroup); err != nil {
+			return err
+		}
+		d.AzureNetworkSecurityGroups = append(d.AzureNetworkSecurityGroups, nsg)
+	case "microsoft.network/publicipaddresses":
+		ip := PublicIPAddress{Subscribed: tag}
+		if err := decodeGraphRow(row, &ip.PublicIPAddress); err != nil {
+			return err
+		}
+		d.AzurePublicIPs = append(d.AzurePublicIPs, ip)
+	case "microsoft.network/loadbalancers":
+		lb := LoadBalancer{Subscribed: tag}
+		if err := decodeGraphRow(row, &lb.LoadBalancer); err != nil {
+			return err
+		}
+		d.AzureLoadBalancers = append(d.AzureLoadBalancers, lb)
+	case "microsoft.network/applicationgateways":
+		gateway := ApplicationGateway{Subscribed: tag}
+		if err := decodeGraphRow(row, &gateway.ApplicationGateway); err != nil {
+			return err
+		}
+		d.AzureApplicationGateways = append(d.AzureApplicationGateways, gateway)
+	case "microsoft.network/privateendpoints":
+		endpoint := PrivateEndpoint{Subscribed: tag}
+		if err := decodeGraphRow(row, &endpoint.PrivateEndpoint); err != nil {
+			return err
+		}
+		d.AzurePrivateEndpoints = append(d.AzurePrivateEndpoints, endpoint)
+	case "microsoft.sql/servers":
+		server := SQLServer{Subscribed: tag}
+		if err := decodeGraphRow(row, &server.Server); err != nil {
+			return err
+		}
+		d.AzureSQLServers = append(d.AzureSQLServers, server)
+	case "microsoft.sql/servers/databases":
+		db := SQLDatabase{Subscribed: tag}
+		if err := decodeGraphRow(row, &db.Database); err != nil {
+			return err
+		}
+		d.AzureSQLDatabases = append(d.AzureSQLDatabases, db)
+	case "microsoft.sql/managedinstances":
+		instance := SQLManagedInstance{Subscribed: tag}
+		if err := decodeGraphRow(row, &instance.ManagedInstance); err != nil {
+			return err
+		}
+		d.AzureSQLManagedInstances = append(d.AzureSQLManagedInstances, instance)
+	case "microsoft.sql/managedinstances/databases":
+		db := SQLManagedDatabase{Subscribed: tag}
+		if err := decodeGraphRow(row, &db.ManagedDatabase); err != nil {
+			return err
+		}
+		d.AzureSQLManagedDatabases = append(d.AzureSQLManagedDatabases, db)
+	case "microsoft.documentdb/databaseaccounts":
+		db := CosmosDBAccount{Subscribed: tag}
+		if err := decodeGraphRow(row, &db.DatabaseAccountGetResults); err != nil {
+			return err
+		}
+		d.AzureCosmosDBs = append(d.AzureCosmosDBs, db)
+	case "microsoft.compute/disks":
+		disk := Disk{Subscribed: tag}
+		if err := decodeGraphRow(row, &disk.Disk); err != nil {
+			return err
+		}
+		d.AzureDisks = append(d.AzureDisks, disk)
+	case "microsoft.compute/snapshots":
+		snapshot := Snapshot{Subscribed: tag}
+		if err := decodeGraphRow(row, &snapshot.Snapshot); err != nil {
+			return err
+		}
+		d.AzureSnapshots = append(d.AzureSnapshots, snapshot)
+	}
+	return nil
+}
+
+// addGraphPowerState copies the power state Resource Graph reports under
+// properties.extended.instanceView into the VM's instance view statuses.
+func addGraphPowerState(row map[string]interface{}, vm *VirtualMachine) {
+	properties, _ := row["properties"].(map[string]interface{})
+	extended, _ := properties["extended"].(map[string]interface{})
+	view, _ := extended["instanceView"].(map[string]interface{})
+	powerState, _ := view["powerState"].(map[string]interface{})
+	code := graphString(powerState, "code")
+	if code == "" || vm.Properties == nil {
+		return
+	}
+	if vm.Properties.InstanceView == nil {
+		vm.Properties.InstanceView = &armcompute.VirtualMachineInstanceView{}
+	}
+	vm.Properties.InstanceView.Statuses = append(vm.Properties.InstanceView.Statuses, &armcompute.InstanceViewStatus{Code: to.Ptr(code)})
+}
+
+// decodeGraphRow decodes a row into an ARM SDK type. Resource Graph returns
+// resources in the same shape as ARM, so the SDK's own decoding applies.
+func decodeGraphRow(row map[string]interface{}, v interface{}) error {
+	data, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func graphResource(row map[string]interface{}, subscriptionName string) Resource {
+	resource := Resource{
+		ID:               graphString(row, "id"),
+		Name:             graphString(row, "name"),
+		Type:             graphString(row, "type"),
+		Kind:             graphString(row, "kind"),
+		ResourceGroup:    graphString(row, "resourceGroup"),
+		Location:         graphString(row, "location"),
+		SubscriptionID:   graphString(row, "subscriptionId"),
+		SubscriptionName: subscriptionName,
+	}
+
+	if sku, ok := row["sku"].(map[string]interface{}); ok {
+		resource.SKU = graphString(sku, "name")
+		if tier := graphString(sku, "tier"); tier != "" && tier != resource.SKU {
+			resource.SKU += " (" + tier + ")"
+		}
+	}
+	if resource.SKU == "" {
+		if properties, ok := row["properties"].(map[string]interface{}); ok {
+			if hardware, ok := properties["hardwareProfile"].(map[string]interface{}); ok {
+				resource.SKU = graphString(hardware, "vmSize")
+			}
+		}
+	}
+
+	if tags, ok := row["tags"].(map[string]interface{}); ok && len(tags) > 0 {
+		resource.Tags = make(map[string]string, len(tags))
+		for key, value := range tags {
+			resource.Tags[key] = fmt.Sprint(value)
+		}
+	}
+	return resource
+}
+
+func graphString(row map[string]interface{}, key string) string {
+	value, _ := row[key].(string)
+	return value
+}
@@ -2,53 +2,189 @@ package azure
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
-	"os/exec"
+	"maps"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/cosmos/armcosmos"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
-	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/sql/armsql"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
 )
 
+// AzureData holds the kollect summaries of the collected resources. The
+// Azure* fields hold the raw ARM resources they were built from, and are
+// only kept in exports in raw mode.
 type AzureData struct {
-	AzureVMs             []armcompute.VirtualMachine
-	AzureVMSS            []armcompute.VirtualMachineScaleSet
-	AzureAKSClusters     []armcontainerservice.ManagedCluster
-	AzureStorageAccounts []armstorage.Account
-	AzureBlobContainers  []armstorage.ListContainerItem
-	AzureVirtualNetworks []armnetwork.VirtualNetwork
-	AzureSQLDatabases    []armsql.Database
-	AzureCosmosDBs       []armcosmos.DatabaseAccountGetResults
-	AzureResourceGroups  []armresources.ResourceGroup
+	VirtualMachines       []VMInfo                   `json:",omitempty"`
+	ScaleSets             []ScaleSetInfo             `json:",omitempty"`
+	AKSClusters           []AKSClusterInfo           `json:",omitempty"`
+	StorageAccounts       []StorageAccountInfo       `json:",omitempty"`
+	BlobContainers        []BlobContainerInfo        `json:",omitempty"`
+	VirtualNetworks       []VirtualNetworkInfo       `json:",omitempty"`
+	Subnets               []SubnetInfo               `json:",omitempty"`
+	VNetPeerings          []VNetPeeringInfo          `json:",omitempty"`
+	NetworkInterfaces     []NetworkInterfaceInfo     `json:",omitempty"`
+	NetworkSecurityGroups []NetworkSecurityGroupInfo `json:",omitempty"`
+	PublicIPs             []PublicIPInfo             `json:",omitempty"`
+	LoadBalancers         []LoadBalancerInfo         `json:",omitempty"`
+	ApplicationGateways   []ApplicationGatewayInfo   `json:",omitempty"`
+	PrivateEndpoints      []PrivateEndpointInfo      `json:",omitempty"`
+	SQLServers            []SQLServerInfo            `json:",omitempty"`
+	SQLManagedInstances   []SQLManagedInstanceInfo   `json:",omitempty"`
+	SQLDatabases          []SQLDatabaseInfo          `json:",omitempty"`
+	CosmosDBAccounts      []CosmosDBAccountInfo      `json:",omitempty"`
+	ResourceGroups        []ResourceGroupInfo        `json:",omitempty"`
+	Disks                 []DiskInfo                 `json:",omitempty"`
+	OrphanedSnapshots     []OrphanedSnapshotInfo     `json:",omitempty"`
+
+	RecoveryServicesVaults []RecoveryServicesVaultInfo `json:",omitempty"`
+	BackupVaults           []BackupVaultInfo           `json:",omitempty"`
+	BackupPolicies         []BackupPolicyInfo          `json:",omitempty"`
+	ProtectedItems         []ProtectedItemInfo         `json:",omitempty"`
+
+	AzureVMs                   []VirtualMachine         `json:",omitempty"`
+	AzureVMSS                  []VirtualMachineScaleSet `json:",omitempty"`
+	AzureAKSClusters           []ManagedCluster         `json:",omitempty"`
+	AzureStorageAccounts       []StorageAccount         `json:",omitempty"`
+	AzureBlobContainers        []BlobContainer          `json:",omitempty"`
+	AzureVirtualNetworks       []VirtualNetwork         `json:",omitempty"`
+	AzureNetworkInterfaces     []NetworkInterface       `json:",omitempty"`
+	AzureNetworkSecurityGroups []NetworkSecurityGroup   `json:",omitempty"`
+	AzurePublicIPs             []PublicIPAddress        `json:",omitempty"`
+	AzureLoadBalancers         []LoadBalancer           `json:",omitempty"`
+	AzureApplicationGateways   []ApplicationGateway     `json:",omitempty"`
+	AzurePrivateEndpoints      []PrivateEndpoint        `json:",omitempty"`
+	AzureSQLServers            []SQLServer              `json:",omitempty"`
+	AzureSQLManagedInstances   []SQLManagedInstance     `json:",omitempty"`
+	AzureSQLDatabases          []SQLDatabase            `json:",omitempty"`
+	AzureSQLManagedDatabases   []SQLManagedDatabase     `json:",omitempty"`
+	AzureSQLRetentionPolicies  []SQLRetentionPolicy     `json:",omitempty"`
+	AzureSQLReplicationLinks   []SQLReplicationLink     `json:",omitempty"`
+	AzureCosmosDBs             []CosmosDBAccount        `json:",omitempty"`
+	AzureResourceGroups        []ResourceGroup          `json:",omitempty"`
+	AzureDisks                 []Disk                   `json:",omitempty"`
+	AzureSnapshots             []Snapshot               `json:",omitempty"`
+
+	Resources     []Resource         `json:",omitempty"`
+	Subscriptions []SubscriptionInfo `json:",omitempty"`
+
+	// diskErr is why a subscription's managed disks were not all listed.
+	diskErr error
 }
 
-func CheckCredentials(ctx context.Context) (bool, error) {
-	err := error(nil)
-
-	_, err = getAzureSubscriptionID()
+// maxSubscriptions bounds how many subscriptions are collected at once.
+const maxSubscriptions = 4
 
+func CheckCredentials(ctx context.Context, allow []string, managementGroup string) (bool, error) {
+	_, err := Subscriptions(ctx, allow, managementGroup)
 	return err == nil, err
 }
 
+// CollectAzureData collects every subscription visible to the default Azure
+// credential.
 func CollectAzureData(ctx context.Context) (AzureData, error) {
-	var data AzureData
-	subscriptionID, err := getAzureSubscriptionID()
+	subscriptions, err := Subscriptions(ctx, nil, "")
 	if err != nil {
-		return data, fmt.Errorf("failed to get Azure subscription ID: %v", err)
+		return AzureData{}, err
 	}
-	cred, err := azidentity.NewDefaultAzureCredential(nil)
-	if err != nil {
-		return data, err
+	return CollectSubscriptionsData(ctx, subscriptions)
+}
+
+// CollectSubscriptionsData collects the subscriptions concurrently into one
+// AzureData with each resource tagged by subscription. A subscription that
+// fails is recorded in Subscriptions and skipped, unless every subscription
+// fails.
+func CollectSubscriptionsData(ctx context.Context, subscriptions []Subscription) (AzureData, error) {
+	results := make([]AzureData, len(subscriptions))
+	errs := make([]error, len(subscriptions))
+	eachSubscription(subscriptions, func(i int, subscription Subscription) {
+		log.Printf("Collecting Azure subscription %s (%s)", subscription.ID, subscription.Name)
+		results[i], errs[i] = collectSubscription(ctx, subscription)
+	})
+
+	var data AzureData
+	var lastErr error
+	for i, subscription := range subscriptions {
+		info := subscription.info(errs[i])
+		if errs[i] == nil && results[i].diskErr != nil {
+			info.DiskError = results[i].diskErr.Error()
+		}
+		data.Subscriptions = append(data.Subscriptions, info)
+		if errs[i] != nil {
+			log.Printf("Warning: Failed to collect Azure subscription %s: %v", subscription.ID, errs[i])
+			lastErr = errs[i]
+			continue
+		}
+
+		result := results[i]
+		data.AzureVMs = append(data.AzureVMs, result.AzureVMs...)
+		data.AzureVMSS = append(data.AzureVMSS, result.AzureVMSS...)
+		data.AzureAKSClusters = append(data.AzureAKSClusters, result.AzureAKSClusters...)
+		data.AzureStorageAccounts = append(data.AzureStorageAccounts, result.AzureStorageAccounts...)
+		data.AzureBlobContainers = append(data.AzureBlobContainers, result.AzureBlobContainers...)
+		data.AzureVirtualNetworks = append(data.AzureVirtualNetworks, result.AzureVirtualNetworks...)
+		data.AzureNetworkInterfaces = append(data.AzureNetworkInterfaces, result.AzureNetworkInterfaces...)
+		data.AzureNetworkSecurityGroups = append(data.AzureNetworkSecurityGroups, result.AzureNetworkSecurityGroups...)
+		data.AzurePublicIPs = append(data.AzurePublicIPs, result.AzurePublicIPs...)
+		data.AzureLoadBalancers = append(data.AzureLoadBalancers, result.AzureLoadBalancers...)
+		data.AzureApplicationGateways = append(data.AzureApplicationGateways, result.AzureApplicationGateways...)
+		data.AzurePrivateEndpoints = append(data.AzurePrivateEndpoints, result.AzurePrivateEndpoints...)
+		data.AzureSQLServers = append(data.AzureSQLServers, result.AzureSQLServers...)
+		data.AzureSQLManagedInstances = append(data.AzureSQLManagedInstances, result.AzureSQLManagedInstances...)
+		data.AzureSQLDatabases = append(data.AzureSQLDatabases, result.AzureSQLDatabases...)
+		data.AzureSQLManagedDatabases = append(data.AzureSQLManagedDatabases, result.AzureSQLManagedDatabases...)
+		data.AzureSQLRetentionPolicies = append(data.AzureSQLRetentionPolicies, result.AzureSQLRetentionPolicies...)
+		data.AzureSQLReplicationLinks = append(data.AzureSQLReplicationLinks, result.AzureSQLReplicationLinks...)
+		data.AzureCosmosDBs = append(data.AzureCosmosDBs, result.AzureCosmosDBs...)
+		data.AzureResourceGroups = append(data.AzureResourceGroups, result.AzureResourceGroups...)
+		data.AzureDisks = append(data.AzureDisks, result.AzureDisks...)
+		data.AzureSnapshots = append(data.AzureSnapshots, result.AzureSnapshots...)
+		data.RecoveryServicesVaults = append(data.RecoveryServicesVaults, result.RecoveryServicesVaults...)
+		data.BackupVaults = append(data.BackupVaults, result.BackupVaults...)
+		data.BackupPolicies = append(data.BackupPolicies, result.BackupPolicies...)
+		data.ProtectedItems = append(data.ProtectedItems, result.ProtectedItems...)
+	}
+
+	if failed := countFailed(data.Subscriptions); failed == len(subscriptions) {
+		if failed == 1 {
+			return AzureData{}, lastErr
+		}
+		return AzureData{}, fmt.Errorf("all %d Azure subscriptions failed, last error: %v", failed, lastErr)
+	}
+	data.summarize()
+	return data, nil
+}
+
+// eachSubscription calls collect for every subscription concurrently, at
+// most maxSubscriptions at a time, and waits for them all.
+func eachSubscription(subscriptions []Subscription, collect func(i int, subscription Subscription)) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxSubscriptions)
+	for i, subscription := range subscriptions {
+		wg.Add(1)
+		go func(i int, subscription Subscription) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			collect(i, subscription)
+		}(i, subscription)
 	}
+	wg.Wait()
+}
+
+// collectSubscription collects the resources of one subscription.
+func collectSubscription(ctx context.Context, subscription Subscription) (AzureData, error) {
+	var data AzureData
+	subscriptionID, cred, tag := subscription.ID, subscription.cred, subscription.subscribed()
 	rgClient, err := armresources.NewResourceGroupsClient(subscriptionID, cred, nil)
 	if err != nil {
 		return data, err
@@ -61,7 +197,7 @@ func CollectAzureData(ctx context.Context) (AzureData, error) {
 			break
 		}
 		for _, rg := range page.Value {
-			data.AzureResourceGroups = append(data.AzureResourceGroups, *rg)
+			data.AzureResourceGroups = append(data.AzureResourceGroups, ResourceGroup{ResourceGroup: *rg, Subscribed: tag})
 		}
 	}
 
@@ -77,7 +213,44 @@ func CollectAzureData(ctx context.Context) (AzureData, error) {
 			break
 		}
 		for _, vm := range page.Value {
-			data.AzureVMs = append(data.AzureVMs, *vm)
+			data.AzureVMs = append(data.AzureVMs, VirtualMachine{VirtualMachine: *vm, Subscribed: tag})
+		}
+	}
+	addPowerStates(ctx, vmClient, data.AzureVMs)
+
+	diskClient, err := armcompute.NewDisksClient(subscriptionID, cred, nil)
+	if err != nil {
+		log.Printf("Warning: Failed to create Disk client: %v", err)
+		data.diskErr = err
+	} else {
+		diskPager := diskClient.NewListPager(nil)
+		for diskPager.More() {
+			page, err := diskPager.NextPage(ctx)
+			if err != nil {
+				log.Printf("Warning: Failed to get Managed Disks: %v", err)
+				data.diskErr = err
+				break
+			}
+			for _, disk := range page.Value {
+				data.AzureDisks = append(data.AzureDisks, Disk{Disk: *disk, Subscribed: tag})
+			}
+		}
+	}
+
+	snapshotClient, err := armcompute.NewSnapshotsClient(subscriptionID, cred, nil)
+	if err != nil {
+		log.Printf("Warning: Failed to create Snapshot client: %v", err)
+	} else {
+		snapshotPager := snapshotClient.NewListPager(nil)
+		for snapshotPager.More() {
+			page, err := snapshotPager.NextPage(ctx)
+			if err != nil {
+				log.Printf("Warning: Failed to get Disk Snapshots: %v", err)
+				break
+			}
+			for _, snapshot := range page.Value {
+				data.AzureSnapshots = append(data.AzureSnapshots, Snapshot{Snapshot: *snapshot, Subscribed: tag})
+			}
 		}
 	}
 
@@ -93,7 +266,7 @@ func CollectAzureData(ctx context.Context) (AzureData, error) {
 				break
 			}
 			for _, vmss := range page.Value {
-				data.AzureVMSS = append(data.AzureVMSS, *vmss)
+				data.AzureVMSS = append(data.AzureVMSS, VirtualMachineScaleSet{VirtualMachineScaleSet: *vmss, Subscribed: tag})
 			}
 		}
 	}
@@ -110,7 +283,7 @@ func CollectAzureData(ctx context.Context) (AzureData, error) {
 				break
 			}
 			for _, aks := range page.Value {
-				data.AzureAKSClusters = append(data.AzureAKSClusters, *aks)
+				data.AzureAKSClusters = append(data.AzureAKSClusters, ManagedCluster{ManagedCluster: *aks, Subscribed: tag})
 			}
 		}
 	}
@@ -127,30 +300,12 @@ func CollectAzureData(ctx context.Context) (AzureData, error) {
 				break
 			}
 			for _, account := range page.Value {
-				data.AzureStorageAccounts = append(data.AzureStorageAccounts, *account)
+				data.AzureStorageAccounts = append(data.AzureStorageAccounts, StorageAccount{Account: *account, Subscribed: tag})
 			}
 		}
 	}
 
-	for _, account := range data.AzureStorageAccounts {
-		resourceGroup := getResourceGroupFromID(*account.ID)
-		blobClient, err := armstorage.NewBlobContainersClient(subscriptionID, cred, nil)
-		if err != nil {
-			log.Printf("Warning: Failed to create Blob client: %v", err)
-			continue
-		}
-		blobPager := blobClient.NewListPager(resourceGroup, *account.Name, nil)
-		for blobPager.More() {
-			page, err := blobPager.NextPage(ctx)
-			if err != nil {
-				log.Printf("Warning: Failed to get Blob Containers: %v", err)
-				break
-			}
-			for _, container := range page.Value {
-				data.AzureBlobContainers = append(data.AzureBlobContainers, *container)
-			}
-		}
-	}
+	data.AzureBlobContainers = collectBlobContainers(ctx, subscription, data.AzureStorageAccounts)
 
 	vnetClient, err := armnetwork.NewVirtualNetworksClient(subscriptionID, cred, nil)
 	if err != nil {
@@ -164,43 +319,14 @@ func CollectAzureData(ctx context.Context) (AzureData, error) {
 				break
 			}
 			for _, vnet := range page.Value {
-				data.AzureVirtualNetworks = append(data.AzureVirtualNetworks, *vnet)
+				data.AzureVirtualNetworks = append(data.AzureVirtualNetworks, VirtualNetwork{VirtualNetwork: *vnet, Subscribed: tag})
 			}
 		}
 	}
 
-	sqlClient, err := armsql.NewDatabasesClient(subscriptionID, cred, nil)
-	if err != nil {
-		log.Printf("Warning: Failed to create SQL client: %v", err)
-	} else {
-		sqlServerClient, err := armsql.NewServersClient(subscriptionID, cred, nil)
-		if err != nil {
-			log.Printf("Warning: Failed to create SQL server client: %v", err)
-		} else {
-			sqlServerPager := sqlServerClient.NewListPager(nil)
-			for sqlServerPager.More() {
-				page, err := sqlServerPager.NextPage(ctx)
-				if err != nil {
-					log.Printf("Warning: Failed to get SQL Servers: %v", err)
-					break
-				}
-				for _, server := range page.Value {
-					resourceGroup := getResourceGroupFromID(*server.ID)
-					dbPager := sqlClient.NewListByServerPager(resourceGroup, *server.Name, nil)
-					for dbPager.More() {
-						dbPage, err := dbPager.NextPage(ctx)
-						if err != nil {
-							log.Printf("Warning: Failed to get SQL Databases: %v", err)
-							break
-						}
-						for _, db := range dbPage.Value {
-							data.AzureSQLDatabases = append(data.AzureSQLDatabases, *db)
-						}
-					}
-				}
-			}
-		}
-	}
+	data.addNetwork(ctx, subscription)
+
+	data.addSQL(ctx, subscription)
 
 	cosmosClient, err := armcosmos.NewDatabaseAccountsClient(subscriptionID, cred, nil)
 	if err != nil {
@@ -214,55 +340,160 @@ func CollectAzureData(ctx context.Context) (AzureData, error) {
 				break
 			}
 			for _, db := range page.Value {
-				data.AzureCosmosDBs = append(data.AzureCosmosDBs, *db)
+				data.AzureCosmosDBs = append(data.AzureCosmosDBs, CosmosDBAccount{DatabaseAccountGetResults: *db, Subscribed: tag})
 			}
 		}
 	}
 
+	data.addBackups(ctx, subscription)
+
 	return data, nil
 }
 
+// addBackups adds the backup vaults of a subscription, keeping whatever was
+// collected before a failure.
+func (d *AzureData) addBackups(ctx context.Context, subscription Subscription) {
+	backups, err := collectBackups(ctx, subscription)
+	if err != nil {
+		log.Printf("Warning: Failed to get backup vaults: %v", err)
+	}
+	d.RecoveryServicesVaults = append(d.RecoveryServicesVaults, backups.RecoveryServicesVaults...)
+	d.BackupVaults = append(d.BackupVaults, backups.BackupVaults...)
+	d.BackupPolicies = append(d.BackupPolicies, backups.BackupPolicies...)
+	d.ProtectedItems = append(d.ProtectedItems, backups.ProtectedItems...)
+}
+
+// collectBlobContainers lists the blob containers of each storage account.
+func collectBlobContainers(ctx context.Context, subscription Subscription, accounts []StorageAccount) []BlobContainer {
+	var containers []BlobContainer
+	blobClient, err := armstorage.NewBlobContainersClient(subscription.ID, subscription.cred, nil)
+	if err != nil {
+		log.Printf("Warning: Failed to create Blob client: %v", err)
+		return nil
+	}
+	for _, account := range accounts {
+		if account.ID == nil || account.Name == nil {
+			continue
+		}
+		resourceGroup := getResourceGroupFromID(*account.ID)
+		blobPager := blobClient.NewListPager(resourceGroup, *account.Name, nil)
+		for blobPager.More() {
+			page, err := blobPager.NextPage(ctx)
+			if err != nil {
+				log.Printf("Warning: Failed to get Blob Containers: %v", err)
+				break
+			}
+			for _, container := range page.Value {
+				containers = append(containers, BlobContainer{ListContainerItem: *container, Subscribed: subscription.subscribed()})
+			}
+		}
+	}
+	return containers
+}
+
+// addPowerStates fills in the instance view statuses of the VMs, which the
+// full listing leaves out, from a status-only listing.
+func addPowerStates(ctx context.Context, client *armcompute.VirtualMachinesClient, vms []VirtualMachine) {
+	if len(vms) == 0 {
+		return
+	}
+	views := map[string]*armcompute.VirtualMachineInstanceView{}
+	pager := client.NewListAllPager(&armcompute.VirtualMachinesClientListAllOptions{StatusOnly: to.Ptr("true")})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			log.Printf("Warning: Failed to get VM power states: %v", err)
+			return
+		}
+		for _, vm := range page.Value {
+			if vm.ID != nil && vm.Properties != nil && vm.Properties.InstanceView != nil {
+				views[strings.ToLower(*vm.ID)] = vm.Properties.InstanceView
+			}
+		}
+	}
+
+	for i := range vms {
+		view, ok := views[strings.ToLower(value(vms[i].ID))]
+		if !ok || vms[i].Properties == nil {
+			continue
+		}
+		vms[i].Properties.InstanceView = view
+	}
+}
+
 func getResourceGroupFromID(resourceID string) string {
+	return resourceIDSegment(resourceID, "resourceGroups")
+}
+
+// resourceIDSegment returns the segment after key in a resource ID, such as
+// the server name for "servers".
+func resourceIDSegment(resourceID, key string) string {
 	parts := strings.Split(resourceID, "/")
 	for i, part := range parts {
-		if part == "resourceGroups" && i+1 < len(parts) {
+		if strings.EqualFold(part, key) && i+1 < len(parts) {
 			return parts[i+1]
 		}
 	}
 	return ""
 }
 
-func getAzureSubscriptionID() (string, error) {
-	cmd := exec.Command("az", "account", "show", "--query", "id", "--output", "json")
-	output, err := cmd.Output()
+// CollectSnapshotData collects snapshots from every subscription visible to
+// the default Azure credential.
+func CollectSnapshotData(ctx context.Context) (map[string]interface{}, error) {
+	subscriptions, err := Subscriptions(ctx, nil, "")
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	return CollectSubscriptionsSnapshotData(ctx, subscriptions)
+}
 
-	var subscriptionID string
-	if err := json.Unmarshal(output, &subscriptionID); err != nil {
-		return "", err
-	}
+// CollectSubscriptionsSnapshotData collects snapshots from every
+// subscription concurrently, tagging each with SubscriptionId and
+// SubscriptionName. Snapshots of a managed disk are marked DiskDeleted when
+// that disk no longer exists. Backup vaults, their protected items and
+// recovery points are included alongside the disk snapshots.
+func CollectSubscriptionsSnapshotData(ctx context.Context, subscriptions []Subscription) (map[string]interface{}, error) {
+	log.Printf("Collecting Azure disk snapshots and backup recovery points...")
+	results := make([]subscriptionSnapshots, len(subscriptions))
+	eachSubscription(subscriptions, func(i int, subscription Subscription) {
+		results[i] = collectSubscriptionSnapshots(ctx, subscription)
+	})
 
-	return subscriptionID, nil
-}
-func CollectSnapshotData(ctx context.Context) (map[string]interface{}, error) {
 	snapshots := map[string]interface{}{}
+	var diskSnapshots, recoveryPoints []map[string]string
+	var backups AzureData
+	failed := 0
+	var lastErr error
+	exists := map[string]bool{}
+	collected := map[string]bool{}
+	for i, subscription := range subscriptions {
+		result := results[i]
+		if result.diskErr != nil {
+			log.Printf("Warning: Unable to check Azure snapshot disks in subscription %s: %v", subscription.ID, result.diskErr)
+		} else {
+			maps.Copy(exists, result.diskIDs)
+			collected[strings.ToLower(subscription.ID)] = true
+		}
+		if result.snapshotErr != nil {
+			log.Printf("Warning: Failed to collect disk snapshots from Azure subscription %s: %v", subscription.ID, result.snapshotErr)
+			failed++
+			lastErr = result.snapshotErr
+		} else {
+			diskSnapshots = append(diskSnapshots, result.snapshots...)
+		}
 
-	subscriptionID, err := getAzureSubscriptionID()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get Azure subscription ID: %v", err)
+		backups.RecoveryServicesVaults = append(backups.RecoveryServicesVaults, result.backups.RecoveryServicesVaults...)
+		backups.BackupVaults = append(backups.BackupVaults, result.backups.BackupVaults...)
+		backups.ProtectedItems = append(backups.ProtectedItems, result.backups.ProtectedItems...)
+		recoveryPoints = append(recoveryPoints, result.recoveryPoints...)
 	}
-
-	cred, err := azidentity.NewDefaultAzureCredential(nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create credential: %v", err)
+	if failed == len(subscriptions) {
+		return nil, fmt.Errorf("failed to collect disk snapshots: %v", lastErr)
 	}
-
-	log.Printf("Collecting Azure disk snapshots...")
-	diskSnapshots, err := collectAllSnapshots(ctx, subscriptionID, cred)
-	if err != nil {
-		return nil, fmt.Errorf("failed to collect disk snapshots: %v", err)
+	for _, snapshot := range diskSnapshots {
+		if snapshot["SourceDiskId"] != "" {
+			snapshot["DiskDeleted"] = strconv.FormatBool(diskDeleted(snapshot["SourceDiskId"], exists, collected))
+		}
 	}
 
 	if len(diskSnapshots) > 0 {
@@ -272,9 +503,83 @@ func CollectSnapshotData(ctx context.Context) (map[string]interface{}, error) {
 		log.Printf("No Azure disk snapshots found")
 	}
 
+	if vaults := backupVaultMaps(backups); len(vaults) > 0 {
+		snapshots["BackupVaults"] = vaults
+	}
+	if len(backups.ProtectedItems) > 0 {
+		snapshots["ProtectedItems"] = protectedItemMaps(backups.ProtectedItems)
+	}
+	if len(recoveryPoints) > 0 {
+		snapshots["RecoveryPoints"] = recoveryPoints
+		log.Printf("Successfully collected %d Azure recovery points", len(recoveryPoints))
+	} else {
+		log.Printf("No Azure recovery points found")
+	}
+
 	return snapshots, nil
 }
-func collectAllSnapshots(ctx context.Context, subscriptionID string, cred *azidentity.DefaultAzureCredential) ([]map[string]string, error) {
+
+// subscriptionSnapshots is what Snapshot Hunter collects from one
+// subscription.
+type subscriptionSnapshots struct {
+	diskIDs        map[string]bool
+	diskErr        error
+	snapshots      []map[string]string
+	snapshotErr    error
+	backups        AzureData
+	recoveryPoints []map[string]string
+}
+
+// collectSubscriptionSnapshots collects the managed disk IDs, disk
+// snapshots, backup vaults, protected items and recovery points of one
+// subscription.
+func collectSubscriptionSnapshots(ctx context.Context, subscription Subscription) subscriptionSnapshots {
+	var result subscriptionSnapshots
+	result.diskIDs, result.diskErr = diskIDs(ctx, subscription)
+	result.snapshots, result.snapshotErr = collectAllSnapshots(ctx, subscription.ID, subscription.cred)
+	for _, snapshot := range result.snapshots {
+		snapshot["SubscriptionId"] = subscription.ID
+		snapshot["SubscriptionName"] = subscription.Name
+	}
+
+	result.backups.addBackups(ctx, subscription)
+	client, err := newARMClient(subscription)
+	if err != nil {
+		log.Printf("Warning: %v", err)
+		return result
+	}
+	for _, item := range result.backups.ProtectedItems {
+		found, err := collectRecoveryPoints(ctx, client, item)
+		if err != nil {
+			log.Printf("Warning: Failed to get recovery points of %s in vault %s: %v", item.Name, item.Vault, err)
+		}
+		result.recoveryPoints = append(result.recoveryPoints, found...)
+	}
+	return result
+}
+
+// diskIDs returns the lower-cased IDs of every managed disk in a
+// subscription.
+func diskIDs(ctx context.Context, subscription Subscription) (map[string]bool, error) {
+	client, err := armcompute.NewDisksClient(subscription.ID, subscription.cred, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create disks client: %v", err)
+	}
+	ids := map[string]bool{}
+	pager := client.NewListPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list disks: %v", err)
+		}
+		for _, disk := range page.Value {
+			ids[strings.ToLower(value(disk.ID))] = true
+		}
+	}
+	return ids, nil
+}
+
+func collectAllSnapshots(ctx context.Context, subscriptionID string, cred azcore.TokenCredential) ([]map[string]string, error) {
 	snapshotClient, err := armcompute.NewSnapshotsClient(subscriptionID, cred, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create snapshots client: %v", err)
@@ -324,6 +629,11 @@ func collectAllSnapshots(ctx context.Context, subscriptionID string, cred *azide
 
 			if snapshot.ID != nil {
 				snapshotInfo["ID"] = *snapshot.ID
+				snapshotInfo["ResourceGroup"] = getResourceGroupFromID(*snapshot.ID)
+			}
+
+			if source := snapshotSourceDisk(*snapshot); source != "" {
+				snapshotInfo["SourceDiskId"] = source
 			}
 
 			if snapshot.Properties != nil {
@@ -0,0 +1,957 @@
+package azure
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute"
+)
+
+// ResourceInfo holds the fields every Azure summary type shares.
+type ResourceInfo struct {
+	Name             string
+	ID               string
+	ResourceGroup    string
+	Location         string            `json:",omitempty"`
+	Tags             map[string]string `json:",omitempty"`
+	SubscriptionID   string            `json:",omitempty"`
+	SubscriptionName string            `json:",omitempty"`
+}
+
+// VMInfo is a virtual machine. PowerState is the code of its PowerState
+// status, such as "running" or "deallocated".
+type VMInfo struct {
+	ResourceInfo
+	Size                  string
+	PowerState            string   `json:",omitempty"`
+	ProvisioningState     string   `json:",omitempty"`
+	OSType                string   `json:",omitempty"`
+	OSName                string   `json:",omitempty"`
+	Image                 string   `json:",omitempty"`
+	Priority              string   `json:",omitempty"`
+	Zones                 []string `json:",omitempty"`
+	ScaleSet              string   `json:",omitempty"`
+	Disks                 []VMDiskInfo
+	NetworkInterfaces     []string         `json:",omitempty"`
+	PrivateIPs            []string         `json:",omitempty"`
+	PublicIPs             []string         `json:",omitempty"`
+	Subnets               []string         `json:",omitempty"`
+	NetworkSecurityGroups []string         `json:",omitempty"`
+	Backup                *BackupCoverage  `json:",omitempty"`
+	WorkloadBackups       []BackupCoverage `json:",omitempty"`
+	CreatedAt             string           `json:",omitempty"`
+}
+
+// VMDiskInfo is a disk attached to a virtual machine. Lun is unset for the
+// OS disk.
+type VMDiskInfo struct {
+	Name        string
+	ID          string `json:",omitempty"`
+	OSDisk      bool
+	Lun         *int32 `json:",omitempty"`
+	SizeGB      int32
+	StorageType string `json:",omitempty"`
+}
+
+type ScaleSetInfo struct {
+	ResourceInfo
+	SKU               string
+	Tier              string `json:",omitempty"`
+	Capacity          int64
+	OrchestrationMode string   `json:",omitempty"`
+	UpgradeMode       string   `json:",omitempty"`
+	Zones             []string `json:",omitempty"`
+	ProvisioningState string   `json:",omitempty"`
+}
+
+type AKSClusterInfo struct {
+	ResourceInfo
+	KubernetesVersion string
+	SKUTier           string `json:",omitempty"`
+	PowerState        string `json:",omitempty"`
+	ProvisioningState string `json:",omitempty"`
+	FQDN              string `json:",omitempty"`
+	NodeResourceGroup string `json:",omitempty"`
+	NodeCount         int32
+	NodePools         []AKSNodePoolInfo
+}
+
+type AKSNodePoolInfo struct {
+	Name        string
+	Mode        string
+	VMSize      string
+	OSType      string `json:",omitempty"`
+	Count       int32
+	AutoScaling bool
+	MinCount    int32    `json:",omitempty"`
+	MaxCount    int32    `json:",omitempty"`
+	Zones       []string `json:",omitempty"`
+}
+
+type StorageAccountInfo struct {
+	ResourceInfo
+	SKU                   string
+	Kind                  string
+	AccessTier            string `json:",omitempty"`
+	HTTPSOnly             bool
+	MinimumTLSVersion     string `json:",omitempty"`
+	AllowBlobPublicAccess bool
+	PublicNetworkAccess   string `json:",omitempty"`
+	HierarchicalNamespace bool
+	ProvisioningState     string           `json:",omitempty"`
+	FileShareBackups      []BackupCoverage `json:",omitempty"`
+	CreatedAt             string           `json:",omitempty"`
+}
+
+type BlobContainerInfo struct {
+	ResourceInfo
+	StorageAccount        string
+	PublicAccess          string `json:",omitempty"`
+	ImmutableStorage      bool
+	HasImmutabilityPolicy bool
+	HasLegalHold          bool
+	LastModified          string `json:",omitempty"`
+}
+
+type VirtualNetworkInfo struct {
+	ResourceInfo
+	AddressSpace      []string
+	DNSServers        []string `json:",omitempty"`
+	Subnets           []string
+	Peerings          []string `json:",omitempty"`
+	ProvisioningState string   `json:",omitempty"`
+}
+
+type SQLServerInfo struct {
+	ResourceInfo
+	Version             string `json:",omitempty"`
+	FQDN                string `json:",omitempty"`
+	State               string `json:",omitempty"`
+	PublicNetworkAccess string `json:",omitempty"`
+	MinimalTLSVersion   string `json:",omitempty"`
+	Databases           int
+}
+
+type SQLManagedInstanceInfo struct {
+	ResourceInfo
+	SKU                     string
+	Tier                    string `json:",omitempty"`
+	VCores                  int32
+	StorageSizeGB           int32
+	LicenseType             string `json:",omitempty"`
+	State                   string `json:",omitempty"`
+	ZoneRedundant           bool
+	BackupStorageRedundancy string `json:",omitempty"`
+	SubnetID                string `json:",omitempty"`
+	Databases               int
+}
+
+// SQLDatabaseInfo is a database on a logical SQL server or, when
+// ManagedInstance is set, on the SQL Managed Instance named by Server. A
+// managed instance database shares the instance's SKU and backup storage
+// redundancy, and has no max size of its own.
+type SQLDatabaseInfo struct {
+	ResourceInfo
+	Server                  string
+	ManagedInstance         bool `json:",omitempty"`
+	SKU                     string
+	Tier                    string `json:",omitempty"`
+	Status                  string `json:",omitempty"`
+	MaxSizeBytes            int64
+	ZoneRedundant           bool
+	BackupStorageRedundancy string            `json:",omitempty"`
+	LongTermRetention       *SQLRetentionInfo `json:",omitempty"`
+	GeoReplicas             []SQLReplicaInfo  `json:",omitempty"`
+	Backup                  *BackupCoverage   `json:",omitempty"`
+	CreatedAt               string            `json:",omitempty"`
+}
+
+// SQLRetentionInfo is a long-term retention policy. Each retention is an ISO
+// 8601 duration such as "P12W"; WeekOfYear is the week whose backup is kept
+// for YearlyRetention.
+type SQLRetentionInfo struct {
+	WeeklyRetention  string `json:",omitempty"`
+	MonthlyRetention string `json:",omitempty"`
+	YearlyRetention  string `json:",omitempty"`
+	WeekOfYear       int32  `json:",omitempty"`
+}
+
+// SQLReplicaInfo is a geo-replication link of a database. Role is the role
+// of the database itself, PartnerRole that of the partner.
+type SQLReplicaInfo struct {
+	PartnerServer   string
+	PartnerDatabase string
+	PartnerLocation string `json:",omitempty"`
+	Role            string `json:",omitempty"`
+	PartnerRole     string `json:",omitempty"`
+	LinkType        string `json:",omitempty"`
+	State           string `json:",omitempty"`
+}
+
+type CosmosDBAccountInfo struct {
+	ResourceInfo
+	Kind                   string
+	ConsistencyLevel       string `json:",omitempty"`
+	Locations              []string
+	MultipleWriteLocations bool
+	AutomaticFailover      bool
+	BackupPolicy           string `json:",omitempty"`
+	PublicNetworkAccess    string `json:",omitempty"`
+	ProvisioningState      string `json:",omitempty"`
+}
+
+// DiskInfo is a managed disk. Unattached disks are still billed and are
+// usually left over from deleted VMs.
+type DiskInfo struct {
+	ResourceInfo
+	SKU               string
+	Tier              string `json:",omitempty"`
+	SizeGB            int32
+	IOPS              int64 `json:",omitempty"`
+	ThroughputMBps    int64 `json:",omitempty"`
+	DiskState         string
+	Unattached        bool
+	AttachedTo        []string `json:",omitempty"`
+	Zones             []string `json:",omitempty"`
+	OSType            string   `json:",omitempty"`
+	Encryption        string   `json:",omitempty"`
+	DiskEncryptionSet string   `json:",omitempty"`
+	SourceResourceID  string   `json:",omitempty"`
+	CreatedAt         string   `json:",omitempty"`
+}
+
+// OrphanedSnapshotInfo is a disk snapshot whose source disk no longer
+// exists.
+type OrphanedSnapshotInfo struct {
+	ResourceInfo
+	SourceDiskID string
+	SizeGB       int32
+	SKU          string `json:",omitempty"`
+	Incremental  bool
+	CreatedAt    string `json:",omitempty"`
+}
+
+type ResourceGroupInfo struct {
+	ResourceInfo
+	ProvisioningState string `json:",omitempty"`
+	ManagedBy         string `json:",omitempty"`
+}
+
+// summarize fills the summary slices from the raw ARM resources.
+func (d *AzureData) summarize() {
+	coverage := backupCoverage(d.ProtectedItems)
+	d.VirtualMachines = nil
+	for _, vm := range d.AzureVMs {
+		info := vm.summary()
+		for _, backup := range coverage[strings.ToLower(info.ID)] {
+			switch {
+			case !isVMBackup(backup):
+				info.WorkloadBackups = append(info.WorkloadBackups, backup)
+			case info.Backup == nil:
+				info.Backup = &backup
+			}
+		}
+		d.VirtualMachines = append(d.VirtualMachines, info)
+	}
+	d.ScaleSets = nil
+	for _, vmss := range d.AzureVMSS {
+		d.ScaleSets = append(d.ScaleSets, vmss.summary())
+	}
+	d.AKSClusters = nil
+	for _, cluster := range d.AzureAKSClusters {
+		d.AKSClusters = append(d.AKSClusters, cluster.summary())
+	}
+	d.StorageAccounts = nil
+	for _, account := range d.AzureStorageAccounts {
+		info := account.summary()
+		for _, backup := range coverage[strings.ToLower(info.ID)] {
+			if isFileShareBackup(backup) {
+				info.FileShareBackups = append(info.FileShareBackups, backup)
+			}
+		}
+		d.StorageAccounts = append(d.StorageAccounts, info)
+	}
+	d.BlobContainers = nil
+	for _, container := range d.AzureBlobContainers {
+		d.BlobContainers = append(d.BlobContainers, container.summary())
+	}
+	d.VirtualNetworks = nil
+	for _, vnet := range d.AzureVirtualNetworks {
+		d.VirtualNetworks = append(d.VirtualNetworks, vnet.summary())
+	}
+	d.summarizeSQL()
+	for i, db := range d.SQLDatabases {
+		if backups := coverage[strings.ToLower(db.ID)]; len(backups) > 0 {
+			d.SQLDatabases[i].Backup = &backups[0]
+		}
+	}
+	d.CosmosDBAccounts = nil
+	for _, account := range d.AzureCosmosDBs {
+		d.CosmosDBAccounts = append(d.CosmosDBAccounts, account.summary())
+	}
+	d.ResourceGroups = nil
+	for _, group := range d.AzureResourceGroups {
+		d.ResourceGroups = append(d.ResourceGroups, group.summary())
+	}
+
+	d.Disks = nil
+	exists := map[string]bool{}
+	for _, disk := range d.AzureDisks {
+		d.Disks = append(d.Disks, disk.summary())
+		exists[strings.ToLower(value(disk.ID))] = true
+	}
+	collected := collectedSubscriptions(d.Subscriptions)
+	d.OrphanedSnapshots = nil
+	for _, snapshot := range d.AzureSnapshots {
+		source := snapshotSourceDisk(snapshot.Snapshot)
+		if diskDeleted(source, exists, collected) {
+			d.OrphanedSnapshots = append(d.OrphanedSnapshots, snapshot.orphaned(source))
+		}
+	}
+
+	d.summarizeNetwork()
+}
+
+// dropRaw removes the raw ARM resources, leaving only the summaries.
+func (d *AzureData) dropRaw() {
+	d.AzureVMs = nil
+	d.AzureVMSS = nil
+	d.AzureAKSClusters = nil
+	d.AzureStorageAccounts = nil
+	d.AzureBlobContainers = nil
+	d.AzureVirtualNetworks = nil
+	d.AzureNetworkInterfaces = nil
+	d.AzureNetworkSecurityGroups = nil
+	d.AzurePublicIPs = nil
+	d.AzureLoadBalancers = nil
+	d.AzureApplicationGateways = nil
+	d.AzurePrivateEndpoints = nil
+	d.AzureSQLServers = nil
+	d.AzureSQLManagedInstances = nil
+	d.AzureSQLDatabases = nil
+	d.AzureSQLManagedDatabases = nil
+	d.AzureSQLRetentionPolicies = nil
+	d.AzureSQLReplicationLinks = nil
+	d.AzureCosmosDBs = nil
+	d.AzureResourceGroups = nil
+	d.AzureDisks = nil
+	d.AzureSnapshots = nil
+}
+
+func resourceInfo(id, name, location *string, tags map[string]*string, s Subscribed) ResourceInfo {
+	info := ResourceInfo{
+		Name:             value(name),
+		ID:               value(id),
+		Location:         value(location),
+		Tags:             tagMap(tags),
+		SubscriptionID:   s.SubscriptionID,
+		SubscriptionName: s.SubscriptionName,
+	}
+	info.ResourceGroup = getResourceGroupFromID(info.ID)
+	return info
+}
+
+func (r VirtualMachine) summary() VMInfo {
+	info := VMInfo{
+		ResourceInfo: resourceInfo(r.ID, r.Name, r.Location, r.Tags, r.Subscribed),
+		Zones:        values(r.Zones),
+	}
+	props := r.Properties
+	if props == nil {
+		return info
+	}
+
+	if props.HardwareProfile != nil && props.HardwareProfile.VMSize != nil {
+		info.Size = string(*props.HardwareProfile.VMSize)
+	}
+	info.ProvisioningState = value(props.ProvisioningState)
+	info.CreatedAt = formatTime(props.TimeCreated)
+	if props.Priority != nil {
+		info.Priority = string(*props.Priority)
+	}
+	if props.VirtualMachineScaleSet != nil {
+		info.ScaleSet = resourceName(value(props.VirtualMachineScaleSet.ID))
+	}
+
+	if view := props.InstanceView; view != nil {
+		info.OSName = strings.TrimSpace(value(view.OSName) + " " + value(view.OSVersion))
+		for _, status := range view.Statuses {
+			if code := value(status.Code); strings.HasPrefix(code, "PowerState/") {
+				info.PowerState = strings.TrimPrefix(code, "PowerState/")
+			}
+		}
+	}
+
+	if storage := props.StorageProfile; storage != nil {
+		if image := storage.ImageReference; image != nil {
+			if value(image.Publisher) != "" {
+				info.Image = strings.Join([]string{value(image.Publisher), value(image.Offer), value(image.SKU), value(image.Version)}, ":")
+			} else {
+				info.Image = resourceName(value(image.ID))
+			}
+		}
+		if disk := storage.OSDisk; disk != nil {
+			if disk.OSType != nil {
+				info.OSType = string(*disk.OSType)
+			}
+			osDisk := VMDiskInfo{Name: value(disk.Name), OSDisk: true, SizeGB: value(disk.DiskSizeGB)}
+			if disk.ManagedDisk != nil {
+				osDisk.ID = value(disk.ManagedDisk.ID)
+				if disk.ManagedDisk.StorageAccountType != nil {
+					osDisk.StorageType = string(*disk.ManagedDisk.StorageAccountType)
+				}
+			}
+			info.Disks = append(info.Disks, osDisk)
+		}
+		for _, disk := range storage.DataDisks {
+			dataDisk := VMDiskInfo{Name: value(disk.Name), Lun: disk.Lun, SizeGB: value(disk.DiskSizeGB)}
+			if disk.ManagedDisk != nil {
+				dataDisk.ID = value(disk.ManagedDisk.ID)
+				if disk.ManagedDisk.StorageAccountType != nil {
+					dataDisk.StorageType = string(*disk.ManagedDisk.StorageAccountType)
+				}
+			}
+			info.Disks = append(info.Disks, dataDisk)
+		}
+	}
+
+	if props.NetworkProfile != nil {
+		for _, nic := range props.NetworkProfile.NetworkInterfaces {
+			info.NetworkInterfaces = append(info.NetworkInterfaces, value(nic.ID))
+		}
+	}
+	return info
+}
+
+func (r VirtualMachineScaleSet) summary() ScaleSetInfo {
+	info := ScaleSetInfo{
+		ResourceInfo: resourceInfo(r.ID, r.Name, r.Location, r.Tags, r.Subscribed),
+		Zones:        values(r.Zones),
+	}
+	if r.SKU != nil {
+		info.SKU = value(r.SKU.Name)
+		info.Tier = value(r.SKU.Tier)
+		info.Capacity = value(r.SKU.Capacity)
+	}
+	if props := r.Properties; props != nil {
+		info.ProvisioningState = value(props.ProvisioningState)
+		if props.OrchestrationMode != nil {
+			info.OrchestrationMode = string(*props.OrchestrationMode)
+		}
+		if props.UpgradePolicy != nil && props.UpgradePolicy.Mode != nil {
+			info.UpgradeMode = string(*props.UpgradePolicy.Mode)
+		}
+	}
+	return info
+}
+
+func (r ManagedCluster) summary() AKSClusterInfo {
+	info := AKSClusterInfo{
+		ResourceInfo: resourceInfo(r.ID, r.Name, r.Location, r.Tags, r.Subscribed),
+	}
+	if r.SKU != nil && r.SKU.Tier != nil {
+		info.SKUTier = string(*r.SKU.Tier)
+	}
+	props := r.Properties
+	if props == nil {
+		return info
+	}
+
+	info.KubernetesVersion = value(props.KubernetesVersion)
+	if current := value(props.CurrentKubernetesVersion); current != "" {
+		info.KubernetesVersion = current
+	}
+	info.ProvisioningState = value(props.ProvisioningState)
+	info.FQDN = value(props.Fqdn)
+	if info.FQDN == "" {
+		info.FQDN = value(props.PrivateFQDN)
+	}
+	info.NodeResourceGroup = value(props.NodeResourceGroup)
+	if props.PowerState != nil && props.PowerState.Code != nil {
+		info.PowerState = string(*props.PowerState.Code)
+	}
+
+	for _, pool := range props.AgentPoolProfiles {
+		nodePool := AKSNodePoolInfo{
+			Name:        value(pool.Name),
+			VMSize:      value(pool.VMSize),
+			Count:       value(pool.Count),
+			AutoScaling: value(pool.EnableAutoScaling),
+			MinCount:    value(pool.MinCount),
+			MaxCount:    value(pool.MaxCount),
+			Zones:       values(pool.AvailabilityZones),
+		}
+		if pool.Mode != nil {
+			nodePool.Mode = string(*pool.Mode)
+		}
+		if pool.OSType != nil {
+			nodePool.OSType = string(*pool.OSType)
+		}
+		info.NodeCount += nodePool.Count
+		info.NodePools = append(info.NodePools, nodePool)
+	}
+	return info
+}
+
+func (r StorageAccount) summary() StorageAccountInfo {
+	info := StorageAccountInfo{
+		ResourceInfo: resourceInfo(r.ID, r.Name, r.Location, r.Tags, r.Subscribed),
+	}
+	if r.SKU != nil && r.SKU.Name != nil {
+		info.SKU = string(*r.SKU.Name)
+	}
+	if r.Kind != nil {
+		info.Kind = string(*r.Kind)
+	}
+	props := r.Properties
+	if props == nil {
+		return info
+	}
+
+	if props.AccessTier != nil {
+		info.AccessTier = string(*props.AccessTier)
+	}
+	info.HTTPSOnly = value(props.EnableHTTPSTrafficOnly)
+	if props.MinimumTLSVersion != nil {
+		info.MinimumTLSVersion = string(*props.MinimumTLSVersion)
+	}
+	info.AllowBlobPublicAccess = value(props.AllowBlobPublicAccess)
+	if props.PublicNetworkAccess != nil {
+		info.PublicNetworkAccess = string(*props.PublicNetworkAccess)
+	}
+	info.HierarchicalNamespace = value(props.IsHnsEnabled)
+	if props.ProvisioningState != nil {
+		info.ProvisioningState = string(*props.ProvisioningState)
+	}
+	info.CreatedAt = formatTime(props.CreationTime)
+	return info
+}
+
+func (r BlobContainer) summary() BlobContainerInfo {
+	info := BlobContainerInfo{
+		ResourceInfo: resourceInfo(r.ID, r.Name, nil, nil, r.Subscribed),
+	}
+	info.StorageAccount = resourceIDSegment(info.ID, "storageAccounts")
+	if props := r.Properties; props != nil {
+		if props.PublicAccess != nil {
+			info.PublicAccess = string(*props.PublicAccess)
+		}
+		if props.ImmutableStorageWithVersioning != nil {
+			info.ImmutableStorage = value(props.ImmutableStorageWithVersioning.Enabled)
+		}
+		info.HasImmutabilityPolicy = value(props.HasImmutabilityPolicy)
+		info.HasLegalHold = value(props.HasLegalHold)
+		info.LastModified = formatTime(props.LastModifiedTime)
+	}
+	return info
+}
+
+func (r VirtualNetwork) summary() VirtualNetworkInfo {
+	info := VirtualNetworkInfo{
+		ResourceInfo: resourceInfo(r.ID, r.Name, r.Location, r.Tags, r.Subscribed),
+	}
+	props := r.Properties
+	if props == nil {
+		return info
+	}
+
+	if props.AddressSpace != nil {
+		info.AddressSpace = values(props.AddressSpace.AddressPrefixes)
+	}
+	if props.DhcpOptions != nil {
+		info.DNSServers = values(props.DhcpOptions.DNSServers)
+	}
+	for _, subnet := range props.Subnets {
+		info.Subnets = append(info.Subnets, value(subnet.Name))
+	}
+	for _, peering := range props.VirtualNetworkPeerings {
+		info.Peerings = append(info.Peerings, value(peering.Name))
+	}
+	if props.ProvisioningState != nil {
+		info.ProvisioningState = string(*props.ProvisioningState)
+	}
+	return info
+}
+
+// summarizeSQL summarizes the SQL servers, managed instances and all of
+// their databases, with the retention policy and geo-replication links of
+// each database.
+func (d *AzureData) summarizeSQL() {
+	retention := map[string]*SQLRetentionInfo{}
+	for _, policy := range d.AzureSQLRetentionPolicies {
+		if info := policy.summary(); info != nil {
+			retention[parentResourceID(value(policy.ID))] = info
+		}
+	}
+	replicas := map[string][]SQLReplicaInfo{}
+	for _, link := range d.AzureSQLReplicationLinks {
+		id := parentResourceID(value(link.ID))
+		replicas[id] = append(replicas[id], link.summary())
+	}
+	instances := map[string]SQLManagedInstanceInfo{}
+	for _, instance := range d.AzureSQLManagedInstances {
+		info := instance.summary()
+		instances[strings.ToLower(info.ID)] = info
+	}
+
+	databases := map[string]int{}
+	d.SQLDatabases = nil
+	for _, db := range d.AzureSQLDatabases {
+		info := db.summary()
+		id := strings.ToLower(info.ID)
+		info.LongTermRetention = retention[id]
+		info.GeoReplicas = replicas[id]
+		if !isSystemDatabase(info.Name) {
+			databases[parentResourceID(id)]++
+		}
+		d.SQLDatabases = append(d.SQLDatabases, info)
+	}
+	for _, db := range d.AzureSQLManagedDatabases {
+		id := strings.ToLower(value(db.ID))
+		info := db.summary(instances[parentResourceID(id)])
+		info.LongTermRetention = retention[id]
+		databases[parentResourceID(id)]++
+		d.SQLDatabases = append(d.SQLDatabases, info)
+	}
+
+	d.SQLServers = nil
+	for _, server := range d.AzureSQLServers {
+		info := server.summary()
+		info.Databases = databases[strings.ToLower(info.ID)]
+		d.SQLServers = append(d.SQLServers, info)
+	}
+	d.SQLManagedInstances = nil
+	for _, instance := range d.AzureSQLManagedInstances {
+		info := instances[strings.ToLower(value(instance.ID))]
+		info.Databases = databases[strings.ToLower(info.ID)]
+		d.SQLManagedInstances = append(d.SQLManagedInstances, info)
+	}
+}
+
+func (r SQLServer) summary() SQLServerInfo {
+	info := SQLServerInfo{
+		ResourceInfo: resourceInfo(r.ID, r.Name, r.Location, r.Tags, r.Subscribed),
+	}
+	if props := r.Properties; props != nil {
+		info.Version = value(props.Version)
+		info.FQDN = value(props.FullyQualifiedDomainName)
+		info.State = value(props.State)
+		if props.PublicNetworkAccess != nil {
+			info.PublicNetworkAccess = string(*props.PublicNetworkAccess)
+		}
+		info.MinimalTLSVersion = value(props.MinimalTLSVersion)
+	}
+	return info
+}
+
+func (r SQLManagedInstance) summary() SQLManagedInstanceInfo {
+	info := SQLManagedInstanceInfo{
+		ResourceInfo: resourceInfo(r.ID, r.Name, r.Location, r.Tags, r.Subscribed),
+	}
+	if r.SKU != nil {
+		info.SKU = value(r.SKU.Name)
+		info.Tier = value(r.SKU.Tier)
+	}
+	if props := r.Properties; props != nil {
+		info.VCores = value(props.VCores)
+		info.StorageSizeGB = value(props.StorageSizeInGB)
+		if props.LicenseType != nil {
+			info.LicenseType = string(*props.LicenseType)
+		}
+		info.State = value(props.State)
+		info.ZoneRedundant = value(props.ZoneRedundant)
+		if props.CurrentBackupStorageRedundancy != nil {
+			info.BackupStorageRedundancy = string(*props.CurrentBackupStorageRedundancy)
+		}
+		info.SubnetID = value(props.SubnetID)
+	}
+	return info
+}
+
+func (r SQLDatabase) summary() SQLDatabaseInfo {
+	info := SQLDatabaseInfo{
+		ResourceInfo: resourceInfo(r.ID, r.Name, r.Location, r.Tags, r.Subscribed),
+	}
+	info.Server = resourceIDSegment(info.ID, "servers")
+	if r.SKU != nil {
+		info.SKU = value(r.SKU.Name)
+		info.Tier = value(r.SKU.Tier)
+	}
+	if props := r.Properties; props != nil {
+		if props.Status != nil {
+			info.Status = string(*props.Status)
+		}
+		info.MaxSizeBytes = value(props.MaxSizeBytes)
+		info.ZoneRedundant = value(props.ZoneRedundant)
+		if props.CurrentBackupStorageRedundancy != nil {
+			info.BackupStorageRedundancy = string(*props.CurrentBackupStorageRedundancy)
+		}
+		info.CreatedAt = formatTime(props.CreationDate)
+	}
+	return info
+}
+
+func (r SQLManagedDatabase) summary(instance SQLManagedInstanceInfo) SQLDatabaseInfo {
+	info := SQLDatabaseInfo{
+		ResourceInfo:            resourceInfo(r.ID, r.Name, r.Location, r.Tags, r.Subscribed),
+		Server:                  resourceIDSegment(value(r.ID), "managedInstances"),
+		ManagedInstance:         true,
+		SKU:                     instance.SKU,
+		Tier:                    instance.Tier,
+		ZoneRedundant:           instance.ZoneRedundant,
+		BackupStorageRedundancy: instance.BackupStorageRedundancy,
+	}
+	if props := r.Properties; props != nil {
+		if props.Status != nil {
+			info.Status = string(*props.Status)
+		}
+		info.CreatedAt = formatTime(props.CreationDate)
+	}
+	return info
+}
+
+// summary returns nil for a policy that keeps no long-term backups, which
+// Azure reports as every retention being "PT0S".
+func (r SQLRetentionPolicy) summary() *SQLRetentionInfo {
+	if r.Properties == nil {
+		return nil
+	}
+	info := &SQLRetentionInfo{
+		WeeklyRetention:  retentionDuration(r.Properties.WeeklyRetention),
+		MonthlyRetention: retentionDuration(r.Properties.MonthlyRetention),
+		YearlyRetention:  retentionDuration(r.Properties.YearlyRetention),
+	}
+	if info.WeeklyRetention == "" && info.MonthlyRetention == "" && info.YearlyRetention == "" {
+		return nil
+	}
+	if info.YearlyRetention != "" {
+		info.WeekOfYear = value(r.Properties.WeekOfYear)
+	}
+	return info
+}
+
+func (r SQLReplicationLink) summary() SQLReplicaInfo {
+	var info SQLReplicaInfo
+	if props := r.Properties; props != nil {
+		info.PartnerServer = value(props.PartnerServer)
+		info.PartnerDatabase = value(props.PartnerDatabase)
+		info.PartnerLocation = value(props.PartnerLocation)
+		if props.Role != nil {
+			info.Role = string(*props.Role)
+		}
+		if props.PartnerRole != nil {
+			info.PartnerRole = string(*props.PartnerRole)
+		}
+		if props.LinkType != nil {
+			info.LinkType = string(*props.LinkType)
+		}
+		if props.ReplicationState != nil {
+			info.State = string(*props.ReplicationState)
+		}
+	}
+	return info
+}
+
+// retentionDuration returns a retention duration, or "" when it is "PT0S",
+// meaning those backups are not kept.
+func retentionDuration(duration *string) string {
+	if d := value(duration); d != "PT0S" {
+		return d
+	}
+	return ""
+}
+
+func (r CosmosDBAccount) summary() CosmosDBAccountInfo {
+	info := CosmosDBAccountInfo{
+		ResourceInfo: resourceInfo(r.ID, r.Name, r.Location, r.Tags, r.Subscribed),
+	}
+	if r.Kind != nil {
+		info.Kind = string(*r.Kind)
+	}
+	props := r.Properties
+	if props == nil {
+		return info
+	}
+
+	if props.ConsistencyPolicy != nil && props.ConsistencyPolicy.DefaultConsistencyLevel != nil {
+		info.ConsistencyLevel = string(*props.ConsistencyPolicy.DefaultConsistencyLevel)
+	}
+	for _, location := range props.Locations {
+		info.Locations = append(info.Locations, value(location.LocationName))
+	}
+	info.MultipleWriteLocations = value(props.EnableMultipleWriteLocations)
+	info.AutomaticFailover = value(props.EnableAutomaticFailover)
+	if props.BackupPolicy != nil {
+		if policy := props.BackupPolicy.GetBackupPolicy(); policy != nil && policy.Type != nil {
+			info.BackupPolicy = string(*policy.Type)
+		}
+	}
+	if props.PublicNetworkAccess != nil {
+		info.PublicNetworkAccess = string(*props.PublicNetworkAccess)
+	}
+	info.ProvisioningState = value(props.ProvisioningState)
+	return info
+}
+
+func (r ResourceGroup) summary() ResourceGroupInfo {
+	info := ResourceGroupInfo{
+		ResourceInfo: resourceInfo(r.ID, r.Name, r.Location, r.Tags, r.Subscribed),
+		ManagedBy:    value(r.ManagedBy),
+	}
+	info.ResourceGroup = info.Name
+	if r.Properties != nil {
+		info.ProvisioningState = value(r.Properties.ProvisioningState)
+	}
+	return info
+}
+
+func (r Disk) summary() DiskInfo {
+	info := DiskInfo{
+		ResourceInfo: resourceInfo(r.ID, r.Name, r.Location, r.Tags, r.Subscribed),
+		Zones:        values(r.Zones),
+	}
+	if r.SKU != nil && r.SKU.Name != nil {
+		info.SKU = string(*r.SKU.Name)
+	}
+	if managedBy := value(r.ManagedBy); managedBy != "" {
+		info.AttachedTo = append(info.AttachedTo, resourceName(managedBy))
+	}
+	for _, vm := range r.ManagedByExtended {
+		if name := resourceName(value(vm)); name != "" && !slices.Contains(info.AttachedTo, name) {
+			info.AttachedTo = append(info.AttachedTo, name)
+		}
+	}
+	props := r.Properties
+	if props == nil {
+		return info
+	}
+
+	info.Tier = value(props.Tier)
+	info.SizeGB = value(props.DiskSizeGB)
+	info.IOPS = value(props.DiskIOPSReadWrite)
+	info.ThroughputMBps = value(props.DiskMBpsReadWrite)
+	if props.DiskState != nil {
+		info.DiskState = string(*props.DiskState)
+	}
+	info.Unattached = info.DiskState == string(armcompute.DiskStateUnattached)
+	if props.OSType != nil {
+		info.OSType = string(*props.OSType)
+	}
+	if props.Encryption != nil {
+		if props.Encryption.Type != nil {
+			info.Encryption = string(*props.Encryption.Type)
+		}
+		info.DiskEncryptionSet = resourceName(value(props.Encryption.DiskEncryptionSetID))
+	}
+	if props.CreationData != nil {
+		info.SourceResourceID = value(props.CreationData.SourceResourceID)
+	}
+	info.CreatedAt = formatTime(props.TimeCreated)
+	return info
+}
+
+func (r Snapshot) orphaned(sourceDiskID string) OrphanedSnapshotInfo {
+	info := OrphanedSnapshotInfo{
+		ResourceInfo: resourceInfo(r.ID, r.Name, r.Location, r.Tags, r.Subscribed),
+		SourceDiskID: sourceDiskID,
+	}
+	if r.SKU != nil && r.SKU.Name != nil {
+		info.SKU = string(*r.SKU.Name)
+	}
+	if props := r.Properties; props != nil {
+		info.SizeGB = value(props.DiskSizeGB)
+		info.Incremental = value(props.Incremental)
+		info.CreatedAt = formatTime(props.TimeCreated)
+	}
+	return info
+}
+
+// snapshotSourceDisk returns the ID of the managed disk a snapshot was taken
+// from, or "" if it was made from a blob, an image or another snapshot.
+func snapshotSourceDisk(snapshot armcompute.Snapshot) string {
+	if snapshot.Properties == nil || snapshot.Properties.CreationData == nil {
+		return ""
+	}
+	source := value(snapshot.Properties.CreationData.SourceResourceID)
+	if !strings.Contains(strings.ToLower(source), "/providers/microsoft.compute/disks/") {
+		return ""
+	}
+	return source
+}
+
+// diskDeleted reports whether a snapshot's source disk is gone. Disks in
+// subscriptions that were not collected are never reported as deleted, since
+// kollect cannot tell. A nil collected set means every subscription was.
+func diskDeleted(sourceDiskID string, exists, collected map[string]bool) bool {
+	if sourceDiskID == "" || exists[strings.ToLower(sourceDiskID)] {
+		return false
+	}
+	if collected == nil {
+		return true
+	}
+	return collected[strings.ToLower(resourceIDSegment(sourceDiskID, "subscriptions"))]
+}
+
+// collectedSubscriptions returns the lower-cased IDs of the subscriptions
+// that were collected, managed disks included, without error, or nil if none
+// are recorded.
+func collectedSubscriptions(subscriptions []SubscriptionInfo) map[string]bool {
+	if len(subscriptions) == 0 {
+		return nil
+	}
+	collected := map[string]bool{}
+	for _, subscription := range subscriptions {
+		if subscription.Error == "" && subscription.DiskError == "" {
+			collected[strings.ToLower(subscription.SubscriptionID)] = true
+		}
+	}
+	return collected
+}
+
+func value[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
+}
+
+func values[T any](ps []*T) []T {
+	if len(ps) == 0 {
+		return nil
+	}
+	result := make([]T, 0, len(ps))
+	for _, p := range ps {
+		if p != nil {
+			result = append(result, *p)
+		}
+	}
+	return result
+}
+
+func tagMap(tags map[string]*string) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(tags))
+	for key, v := range tags {
+		result[key] = value(v)
+	}
+	return result
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// resourceName returns the last segment of a resource ID.
+func resourceName(id string) string {
+	if i := strings.LastIndex(id, "/"); i >= 0 {
+		return id[i+1:]
+	}
+	return id
+}
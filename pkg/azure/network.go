@@ -0,0 +1,729 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
+)
+
+// SubnetInfo is a subnet of a virtual network, with the IDs of the NICs and
+// the names of the private endpoints placed in it.
+type SubnetInfo struct {
+	ResourceInfo
+	VirtualNetwork       string
+	AddressPrefixes      []string
+	NetworkSecurityGroup string   `json:",omitempty"`
+	RouteTable           string   `json:",omitempty"`
+	NATGateway           string   `json:",omitempty"`
+	Delegations          []string `json:",omitempty"`
+	NetworkInterfaces    []string `json:",omitempty"`
+	PrivateEndpoints     []string `json:",omitempty"`
+}
+
+type VNetPeeringInfo struct {
+	ResourceInfo
+	VirtualNetwork        string
+	RemoteVirtualNetwork  string
+	RemoteAddressSpace    []string `json:",omitempty"`
+	State                 string   `json:",omitempty"`
+	AllowForwardedTraffic bool
+	AllowGatewayTransit   bool
+	UseRemoteGateways     bool
+}
+
+// NetworkSecurityGroupInfo is a network security group, with each of its own
+// rules summarised as "100 Allow tcp 22 from Internet". The default rules
+// every NSG has are left out. OpenToInternet is set when an inbound rule
+// allows traffic from any address or the Internet tag.
+type NetworkSecurityGroupInfo struct {
+	ResourceInfo
+	InboundRules      []string
+	OutboundRules     []string
+	OpenToInternet    bool
+	Subnets           []string `json:",omitempty"`
+	NetworkInterfaces []string `json:",omitempty"`
+}
+
+// NetworkInterfaceInfo is a NIC. Subnets are named "vnet/subnet", and
+// PublicIPs are the addresses of its public IPs, or their names while they
+// have no address.
+type NetworkInterfaceInfo struct {
+	ResourceInfo
+	VirtualMachine        string `json:",omitempty"`
+	PrivateEndpoint       string `json:",omitempty"`
+	PrivateIPs            []string
+	PublicIPs             []string `json:",omitempty"`
+	Subnets               []string
+	NetworkSecurityGroup  string `json:",omitempty"`
+	AcceleratedNetworking bool
+	IPForwarding          bool
+
+	subnetIDs []string
+}
+
+// PublicIPInfo is a public IP address. AttachedTo is the ID of the NIC, load
+// balancer, gateway or NAT gateway using it, and VirtualMachine the VM of
+// that NIC. An address used by nothing is marked Unassociated, since it is
+// still billed.
+type PublicIPInfo struct {
+	ResourceInfo
+	IPAddress        string `json:",omitempty"`
+	SKU              string `json:",omitempty"`
+	AllocationMethod string `json:",omitempty"`
+	Version          string `json:",omitempty"`
+	FQDN             string `json:",omitempty"`
+	AttachedTo       string `json:",omitempty"`
+	VirtualMachine   string `json:",omitempty"`
+	Unassociated     bool
+}
+
+// LoadBalancerInfo is a load balancer. Frontends are summarised as
+// "name: address" and rules as "Tcp 80 -> 8080".
+type LoadBalancerInfo struct {
+	ResourceInfo
+	SKU             string `json:",omitempty"`
+	Frontends       []string
+	BackendPools    []string `json:",omitempty"`
+	Rules           []string `json:",omitempty"`
+	VirtualMachines []string `json:",omitempty"`
+}
+
+// ApplicationGatewayInfo is an application gateway. Listeners are
+// summarised as "name (Https host)", and WAF is the firewall mode when the
+// built-in web application firewall is enabled.
+type ApplicationGatewayInfo struct {
+	ResourceInfo
+	SKU              string `json:",omitempty"`
+	Tier             string `json:",omitempty"`
+	Capacity         int32  `json:",omitempty"`
+	OperationalState string `json:",omitempty"`
+	Frontends        []string
+	Listeners        []string `json:",omitempty"`
+	BackendPools     []string `json:",omitempty"`
+	WAF              string   `json:",omitempty"`
+	FirewallPolicy   string   `json:",omitempty"`
+	Subnet           string   `json:",omitempty"`
+	VirtualMachines  []string `json:",omitempty"`
+}
+
+// PrivateEndpointInfo is a private endpoint. Target is the name of the
+// resource it connects to and GroupIDs the sub-resources, such as "blob".
+type PrivateEndpointInfo struct {
+	ResourceInfo
+	Subnet            string
+	PrivateIPs        []string `json:",omitempty"`
+	Target            string
+	TargetID          string
+	GroupIDs          []string `json:",omitempty"`
+	ConnectionState   string   `json:",omitempty"`
+	NetworkInterfaces []string `json:",omitempty"`
+}
+
+// addNetwork adds the NICs, NSGs, public IPs, load balancers, application
+// gateways and private endpoints of a subscription. Subnets and peerings are
+// part of the virtual networks.
+func (d *AzureData) addNetwork(ctx context.Context, subscription Subscription) {
+	subscriptionID, cred, tag := subscription.ID, subscription.cred, subscription.subscribed()
+
+	nicClient, err := armnetwork.NewInterfacesClient(subscriptionID, cred, nil)
+	if err != nil {
+		log.Printf("Warning: Failed to create Network Interface client: %v", err)
+	} else {
+		nicPager := nicClient.NewListAllPager(nil)
+		for nicPager.More() {
+			page, err := nicPager.NextPage(ctx)
+			if err != nil {
+				log.Printf("Warning: Failed to get Network Interfaces: %v", err)
+				break
+			}
+			for _, nic := range page.Value {
+				d.AzureNetworkInterfaces = append(d.AzureNetworkInterfaces, NetworkInterface{Interface: *nic, Subscribed: tag})
+			}
+		}
+	}
+
+	nsgClient, err := armnetwork.NewSecurityGroupsClient(subscriptionID, cred, nil)
+	if err != nil {
+		log.Printf("Warning: Failed to create NSG client: %v", err)
+	} else {
+		nsgPager := nsgClient.NewListAllPager(nil)
+		for nsgPager.More() {
+			page, err := nsgPager.NextPage(ctx)
+			if err != nil {
+				log.Printf("Warning: Failed to get Network Security Groups: %v", err)
+				break
+			}
+			for _, nsg := range page.Value {
+				d.AzureNetworkSecurityGroups = append(d.AzureNetworkSecurityGroups, NetworkSecurityGroup{SecurityGroup: *nsg, Subscribed: tag})
+			}
+		}
+	}
+
+	ipClient, err := armnetwork.NewPublicIPAddressesClient(subscriptionID, cred, nil)
+	if err != nil {
+		log.Printf("Warning: Failed to create Public IP client: %v", err)
+	} else {
+		ipPager := ipClient.NewListAllPager(nil)
+		for ipPager.More() {
+			page, err := ipPager.NextPage(ctx)
+			if err != nil {
+				log.Printf("Warning: Failed to get Public IP Addresses: %v", err)
+				break
+			}
+			for _, ip := range page.Value {
+				d.AzurePublicIPs = append(d.AzurePublicIPs, PublicIPAddress{PublicIPAddress: *ip, Subscribed: tag})
+			}
+		}
+	}
+
+	lbClient, err := armnetwork.NewLoadBalancersClient(subscriptionID, cred, nil)
+	if err != nil {
+		log.Printf("Warning: Failed to create Load Balancer client: %v", err)
+	} else {
+		lbPager := lbClient.NewListAllPager(nil)
+		for lbPager.More() {
+			page, err := lbPager.NextPage(ctx)
+			if err != nil {
+				log.Printf("Warning: Failed to get Load Balancers: %v", err)
+				break
+			}
+			for _, lb := range page.Value {
+				d.AzureLoadBalancers = append(d.AzureLoadBalancers, LoadBalancer{LoadBalancer: *lb, Subscribed: tag})
+			}
+		}
+	}
+
+	gatewayClient, err := armnetwork.NewApplicationGatewaysClient(subscriptionID, cred, nil)
+	if err != nil {
+		log.Printf("Warning: Failed to create Application Gateway client: %v", err)
+	} else {
+		gatewayPager := gatewayClient.NewListAllPager(nil)
+		for gatewayPager.More() {
+			page, err := gatewayPager.NextPage(ctx)
+			if err != nil {
+				log.Printf("Warning: Failed to get Application Gateways: %v", err)
+				break
+			}
+			for _, gateway := range page.Value {
+				d.AzureApplicationGateways = append(d.AzureApplicationGateways, ApplicationGateway{ApplicationGateway: *gateway, Subscribed: tag})
+			}
+		}
+	}
+
+	endpointClient, err := armnetwork.NewPrivateEndpointsClient(subscriptionID, cred, nil)
+	if err != nil {
+		log.Printf("Warning: Failed to create Private Endpoint client: %v", err)
+	} else {
+		endpointPager := endpointClient.NewListBySubscriptionPager(nil)
+		for endpointPager.More() {
+			page, err := endpointPager.NextPage(ctx)
+			if err != nil {
+				log.Printf("Warning: Failed to get Private Endpoints: %v", err)
+				break
+			}
+			for _, endpoint := range page.Value {
+				d.AzurePrivateEndpoints = append(d.AzurePrivateEndpoints, PrivateEndpoint{PrivateEndpoint: *endpoint, Subscribed: tag})
+			}
+		}
+	}
+}
+
+// summarizeNetwork summarizes the networking resources and relates them to
+// each other and to the VMs: NICs to their VM, public IPs to what uses them,
+// and load balancer and application gateway backends to VMs. It runs after
+// the VMs are summarized, and adds their IPs, subnets, and the NSGs of
+// their NICs and subnets.
+func (d *AzureData) summarizeNetwork() {
+	addresses := map[string]string{}
+	for _, ip := range d.AzurePublicIPs {
+		if ip.Properties != nil && value(ip.Properties.IPAddress) != "" {
+			addresses[strings.ToLower(value(ip.ID))] = value(ip.Properties.IPAddress)
+		}
+	}
+
+	d.NetworkInterfaces = nil
+	nics := map[string]NetworkInterfaceInfo{}
+	for _, nic := range d.AzureNetworkInterfaces {
+		info := nic.summary(addresses)
+		nics[strings.ToLower(info.ID)] = info
+		d.NetworkInterfaces = append(d.NetworkInterfaces, info)
+	}
+
+	d.PublicIPs = nil
+	for _, ip := range d.AzurePublicIPs {
+		info := ip.summary()
+		if nic, ok := nics[strings.ToLower(info.AttachedTo)]; ok {
+			info.VirtualMachine = nic.VirtualMachine
+		}
+		d.PublicIPs = append(d.PublicIPs, info)
+	}
+
+	d.NetworkSecurityGroups = nil
+	for _, nsg := range d.AzureNetworkSecurityGroups {
+		d.NetworkSecurityGroups = append(d.NetworkSecurityGroups, nsg.summary())
+	}
+	d.LoadBalancers = nil
+	for _, lb := range d.AzureLoadBalancers {
+		d.LoadBalancers = append(d.LoadBalancers, lb.summary(addresses, nics))
+	}
+	d.ApplicationGateways = nil
+	for _, gateway := range d.AzureApplicationGateways {
+		d.ApplicationGateways = append(d.ApplicationGateways, gateway.summary(addresses, nics))
+	}
+	d.PrivateEndpoints = nil
+	for _, endpoint := range d.AzurePrivateEndpoints {
+		info := endpoint.summary()
+		for _, id := range info.NetworkInterfaces {
+			info.PrivateIPs = append(info.PrivateIPs, nics[strings.ToLower(id)].PrivateIPs...)
+		}
+		d.PrivateEndpoints = append(d.PrivateEndpoints, info)
+	}
+
+	d.Subnets = nil
+	d.VNetPeerings = nil
+	subnetNSGs := map[string]string{}
+	for _, vnet := range d.AzureVirtualNetworks {
+		if vnet.Properties == nil {
+			continue
+		}
+		for _, subnet := range vnet.Properties.Subnets {
+			info := subnetSummary(vnet, subnet)
+			subnetNSGs[strings.ToLower(info.ID)] = info.NetworkSecurityGroup
+			d.Subnets = append(d.Subnets, info)
+		}
+		for _, peering := range vnet.Properties.VirtualNetworkPeerings {
+			d.VNetPeerings = append(d.VNetPeerings, peeringSummary(vnet, peering))
+		}
+	}
+
+	for i, vm := range d.VirtualMachines {
+		for _, id := range vm.NetworkInterfaces {
+			nic, ok := nics[strings.ToLower(id)]
+			if !ok {
+				continue
+			}
+			vm.PrivateIPs = append(vm.PrivateIPs, nic.PrivateIPs...)
+			vm.PublicIPs = append(vm.PublicIPs, nic.PublicIPs...)
+			vm.Subnets = appendUnique(vm.Subnets, nic.Subnets...)
+			vm.NetworkSecurityGroups = appendUnique(vm.NetworkSecurityGroups, nic.NetworkSecurityGroup)
+			for _, subnet := range nic.subnetIDs {
+				vm.NetworkSecurityGroups = appendUnique(vm.NetworkSecurityGroups, subnetNSGs[subnet])
+			}
+		}
+		d.VirtualMachines[i] = vm
+	}
+}
+
+func (r NetworkInterface) summary(addresses map[string]string) NetworkInterfaceInfo {
+	info := NetworkInterfaceInfo{
+		ResourceInfo: resourceInfo(r.ID, r.Name, r.Location, r.Tags, r.Subscribed),
+	}
+	props := r.Properties
+	if props == nil {
+		return info
+	}
+
+	if props.VirtualMachine != nil {
+		info.VirtualMachine = resourceName(value(props.VirtualMachine.ID))
+	}
+	if props.PrivateEndpoint != nil {
+		info.PrivateEndpoint = resourceName(value(props.PrivateEndpoint.ID))
+	}
+	if props.NetworkSecurityGroup != nil {
+		info.NetworkSecurityGroup = resourceName(value(props.NetworkSecurityGroup.ID))
+	}
+	info.AcceleratedNetworking = value(props.EnableAcceleratedNetworking)
+	info.IPForwarding = value(props.EnableIPForwarding)
+	for _, config := range props.IPConfigurations {
+		if config.Properties == nil {
+			continue
+		}
+		if ip := value(config.Properties.PrivateIPAddress); ip != "" {
+			info.PrivateIPs = append(info.PrivateIPs, ip)
+		}
+		if publicIP := config.Properties.PublicIPAddress; publicIP != nil {
+			id := value(publicIP.ID)
+			if address, ok := addresses[strings.ToLower(id)]; ok {
+				info.PublicIPs = append(info.PublicIPs, address)
+			} else {
+				info.PublicIPs = append(info.PublicIPs, resourceName(id))
+			}
+		}
+		if subnet := config.Properties.Subnet; subnet != nil {
+			info.Subnets = appendUnique(info.Subnets, subnetName(value(subnet.ID)))
+			info.subnetIDs = appendUnique(info.subnetIDs, strings.ToLower(value(subnet.ID)))
+		}
+	}
+	return info
+}
+
+func (r PublicIPAddress) summary() PublicIPInfo {
+	info := PublicIPInfo{
+		ResourceInfo: resourceInfo(r.ID, r.Name, r.Location, r.Tags, r.Subscribed),
+	}
+	if r.SKU != nil {
+		info.SKU = enumValue(r.SKU.Name)
+	}
+	props := r.Properties
+	if props == nil {
+		info.Unassociated = true
+		return info
+	}
+
+	info.IPAddress = value(props.IPAddress)
+	info.AllocationMethod = enumValue(props.PublicIPAllocationMethod)
+	info.Version = enumValue(props.PublicIPAddressVersion)
+	if props.DNSSettings != nil {
+		info.FQDN = value(props.DNSSettings.Fqdn)
+	}
+	switch {
+	case props.IPConfiguration != nil:
+		info.AttachedTo = ownerResourceID(value(props.IPConfiguration.ID))
+	case props.NatGateway != nil:
+		info.AttachedTo = value(props.NatGateway.ID)
+	default:
+		info.Unassociated = true
+	}
+	return info
+}
+
+func (r NetworkSecurityGroup) summary() NetworkSecurityGroupInfo {
+	info := NetworkSecurityGroupInfo{
+		ResourceInfo: resourceInfo(r.ID, r.Name, r.Location, r.Tags, r.Subscribed),
+	}
+	props := r.Properties
+	if props == nil {
+		return info
+	}
+
+	rules := slices.Clone(props.SecurityRules)
+	slices.SortStableFunc(rules, func(a, b *armnetwork.SecurityRule) int {
+		return int(securityRulePriority(a) - securityRulePriority(b))
+	})
+	for _, rule := range rules {
+		if rule.Properties == nil {
+			continue
+		}
+		summary, open := securityRuleSummary(*rule.Properties)
+		if enumValue(rule.Properties.Direction) == string(armnetwork.SecurityRuleDirectionInbound) {
+			info.InboundRules = append(info.InboundRules, summary)
+			info.OpenToInternet = info.OpenToInternet || open
+		} else {
+			info.OutboundRules = append(info.OutboundRules, summary)
+		}
+	}
+	for _, subnet := range props.Subnets {
+		info.Subnets = append(info.Subnets, subnetName(value(subnet.ID)))
+	}
+	for _, nic := range props.NetworkInterfaces {
+		info.NetworkInterfaces = append(info.NetworkInterfaces, value(nic.ID))
+	}
+	return info
+}
+
+func securityRulePriority(rule *armnetwork.SecurityRule) int32 {
+	if rule.Properties == nil {
+		return 0
+	}
+	return value(rule.Properties.Priority)
+}
+
+// securityRuleSummary describes a rule as, for example,
+// "100 Allow tcp 22 from Internet", or "... to 10.0.0.0/8" for outbound
+// rules, and reports whether it lets in traffic from the internet.
+func securityRuleSummary(rule armnetwork.SecurityRulePropertiesFormat) (string, bool) {
+	protocol := strings.ToLower(enumValue(rule.Protocol))
+	if protocol == "*" {
+		protocol = "all"
+	}
+	ports := joinPrefixes(rule.DestinationPortRange, rule.DestinationPortRanges, nil)
+	if ports == "*" || ports == "" {
+		ports = "all"
+	}
+	access := enumValue(rule.Access)
+
+	direction, peer := "from", joinPrefixes(rule.SourceAddressPrefix, rule.SourceAddressPrefixes, rule.SourceApplicationSecurityGroups)
+	if enumValue(rule.Direction) != string(armnetwork.SecurityRuleDirectionInbound) {
+		direction, peer = "to", joinPrefixes(rule.DestinationAddressPrefix, rule.DestinationAddressPrefixes, rule.DestinationApplicationSecurityGroups)
+	}
+	if peer == "" || peer == "*" {
+		peer = "any"
+	}
+
+	open := false
+	if direction == "from" && access == string(armnetwork.SecurityRuleAccessAllow) {
+		for _, source := range strings.Split(peer, ",") {
+			switch strings.ToLower(source) {
+			case "any", "internet", "0.0.0.0/0", "::/0":
+				open = true
+			}
+		}
+	}
+	return fmt.Sprintf("%d %s %s %s %s %s", value(rule.Priority), access, protocol, ports, direction, peer), open
+}
+
+// joinPrefixes joins a rule's single prefix, prefix list and application
+// security group names with commas.
+func joinPrefixes(prefix *string, prefixes []*string, groups []*armnetwork.ApplicationSecurityGroup) string {
+	var all []string
+	if value(prefix) != "" {
+		all = append(all, value(prefix))
+	}
+	all = append(all, values(prefixes)...)
+	for _, group := range groups {
+		all = append(all, resourceName(value(group.ID)))
+	}
+	return strings.Join(all, ",")
+}
+
+func (r LoadBalancer) summary(addresses map[string]string, nics map[string]NetworkInterfaceInfo) LoadBalancerInfo {
+	info := LoadBalancerInfo{
+		ResourceInfo: resourceInfo(r.ID, r.Name, r.Location, r.Tags, r.Subscribed),
+	}
+	if r.SKU != nil {
+		info.SKU = enumValue(r.SKU.Name)
+	}
+	props := r.Properties
+	if props == nil {
+		return info
+	}
+
+	for _, frontend := range props.FrontendIPConfigurations {
+		address := ""
+		if frontend.Properties != nil {
+			var publicIP string
+			if frontend.Properties.PublicIPAddress != nil {
+				publicIP = value(frontend.Properties.PublicIPAddress.ID)
+			}
+			address = frontendAddress(publicIP, value(frontend.Properties.PrivateIPAddress), addresses)
+		}
+		info.Frontends = append(info.Frontends, fmt.Sprintf("%s: %s", value(frontend.Name), address))
+	}
+	for _, pool := range props.BackendAddressPools {
+		info.BackendPools = append(info.BackendPools, value(pool.Name))
+		if pool.Properties == nil {
+			continue
+		}
+		for _, config := range pool.Properties.BackendIPConfigurations {
+			info.VirtualMachines = appendUnique(info.VirtualMachines, backendVM(value(config.ID), nics))
+		}
+	}
+	for _, rule := range props.LoadBalancingRules {
+		if rule.Properties == nil {
+			continue
+		}
+		info.Rules = append(info.Rules, fmt.Sprintf("%s %d -> %d", enumValue(rule.Properties.Protocol),
+			value(rule.Properties.FrontendPort), value(rule.Properties.BackendPort)))
+	}
+	return info
+}
+
+func (r ApplicationGateway) summary(addresses map[string]string, nics map[string]NetworkInterfaceInfo) ApplicationGatewayInfo {
+	info := ApplicationGatewayInfo{
+		ResourceInfo: resourceInfo(r.ID, r.Name, r.Location, r.Tags, r.Subscribed),
+	}
+	props := r.Properties
+	if props == nil {
+		return info
+	}
+
+	if sku := props.SKU; sku != nil {
+		info.SKU = enumValue(sku.Name)
+		info.Tier = enumValue(sku.Tier)
+		info.Capacity = value(sku.Capacity)
+	}
+	info.OperationalState = enumValue(props.OperationalState)
+	if props.FirewallPolicy != nil {
+		info.FirewallPolicy = resourceName(value(props.FirewallPolicy.ID))
+	}
+	if waf := props.WebApplicationFirewallConfiguration; waf != nil && value(waf.Enabled) {
+		info.WAF = enumValue(waf.FirewallMode)
+	}
+	for _, config := range props.GatewayIPConfigurations {
+		if config.Properties != nil && config.Properties.Subnet != nil {
+			info.Subnet = subnetName(value(config.Properties.Subnet.ID))
+		}
+	}
+
+	for _, frontend := range props.FrontendIPConfigurations {
+		address := ""
+		if frontend.Properties != nil {
+			var publicIP string
+			if frontend.Properties.PublicIPAddress != nil {
+				publicIP = value(frontend.Properties.PublicIPAddress.ID)
+			}
+			address = frontendAddress(publicIP, value(frontend.Properties.PrivateIPAddress), addresses)
+		}
+		info.Frontends = append(info.Frontends, fmt.Sprintf("%s: %s", value(frontend.Name), address))
+	}
+	for _, listener := range props.HTTPListeners {
+		summary := value(listener.Name)
+		if listener.Properties != nil {
+			detail := strings.TrimSpace(enumValue(listener.Properties.Protocol) + " " + value(listener.Properties.HostName))
+			if detail != "" {
+				summary += " (" + detail + ")"
+			}
+		}
+		info.Listeners = append(info.Listeners, summary)
+	}
+	for _, pool := range props.BackendAddressPools {
+		info.BackendPools = append(info.BackendPools, value(pool.Name))
+		if pool.Properties == nil {
+			continue
+		}
+		for _, config := range pool.Properties.BackendIPConfigurations {
+			info.VirtualMachines = appendUnique(info.VirtualMachines, backendVM(value(config.ID), nics))
+		}
+	}
+	return info
+}
+
+func (r PrivateEndpoint) summary() PrivateEndpointInfo {
+	info := PrivateEndpointInfo{
+		ResourceInfo: resourceInfo(r.ID, r.Name, r.Location, r.Tags, r.Subscribed),
+	}
+	props := r.Properties
+	if props == nil {
+		return info
+	}
+
+	if props.Subnet != nil {
+		info.Subnet = subnetName(value(props.Subnet.ID))
+	}
+	for _, nic := range props.NetworkInterfaces {
+		info.NetworkInterfaces = append(info.NetworkInterfaces, value(nic.ID))
+	}
+	connections := append(slices.Clone(props.PrivateLinkServiceConnections), props.ManualPrivateLinkServiceConnections...)
+	for _, connection := range connections {
+		if connection.Properties == nil {
+			continue
+		}
+		info.TargetID = value(connection.Properties.PrivateLinkServiceID)
+		info.Target = resourceName(info.TargetID)
+		info.GroupIDs = values(connection.Properties.GroupIDs)
+		if state := connection.Properties.PrivateLinkServiceConnectionState; state != nil {
+			info.ConnectionState = value(state.Status)
+		}
+		break
+	}
+	return info
+}
+
+func subnetSummary(vnet VirtualNetwork, subnet *armnetwork.Subnet) SubnetInfo {
+	info := SubnetInfo{
+		ResourceInfo:   resourceInfo(subnet.ID, subnet.Name, vnet.Location, nil, vnet.Subscribed),
+		VirtualNetwork: value(vnet.Name),
+	}
+	props := subnet.Properties
+	if props == nil {
+		return info
+	}
+
+	if value(props.AddressPrefix) != "" {
+		info.AddressPrefixes = append(info.AddressPrefixes, value(props.AddressPrefix))
+	}
+	info.AddressPrefixes = append(info.AddressPrefixes, values(props.AddressPrefixes)...)
+	if props.NetworkSecurityGroup != nil {
+		info.NetworkSecurityGroup = resourceName(value(props.NetworkSecurityGroup.ID))
+	}
+	if props.RouteTable != nil {
+		info.RouteTable = resourceName(value(props.RouteTable.ID))
+	}
+	if props.NatGateway != nil {
+		info.NATGateway = resourceName(value(props.NatGateway.ID))
+	}
+	for _, delegation := range props.Delegations {
+		if delegation.Properties != nil {
+			info.Delegations = append(info.Delegations, value(delegation.Properties.ServiceName))
+		}
+	}
+	for _, config := range props.IPConfigurations {
+		if id := value(config.ID); resourceIDSegment(id, "networkInterfaces") != "" {
+			info.NetworkInterfaces = appendUnique(info.NetworkInterfaces, ownerResourceID(id))
+		}
+	}
+	for _, endpoint := range props.PrivateEndpoints {
+		info.PrivateEndpoints = append(info.PrivateEndpoints, resourceName(value(endpoint.ID)))
+	}
+	return info
+}
+
+func peeringSummary(vnet VirtualNetwork, peering *armnetwork.VirtualNetworkPeering) VNetPeeringInfo {
+	info := VNetPeeringInfo{
+		ResourceInfo:   resourceInfo(peering.ID, peering.Name, vnet.Location, nil, vnet.Subscribed),
+		VirtualNetwork: value(vnet.Name),
+	}
+	props := peering.Properties
+	if props == nil {
+		return info
+	}
+
+	if props.RemoteVirtualNetwork != nil {
+		info.RemoteVirtualNetwork = value(props.RemoteVirtualNetwork.ID)
+	}
+	if props.RemoteAddressSpace != nil {
+		info.RemoteAddressSpace = values(props.RemoteAddressSpace.AddressPrefixes)
+	}
+	info.State = enumValue(props.PeeringState)
+	info.AllowForwardedTraffic = value(props.AllowForwardedTraffic)
+	info.AllowGatewayTransit = value(props.AllowGatewayTransit)
+	info.UseRemoteGateways = value(props.UseRemoteGateways)
+	return info
+}
+
+// frontendAddress returns the public address of a frontend, or its private
+// address when it has no public IP.
+func frontendAddress(publicIPID, privateIP string, addresses map[string]string) string {
+	if publicIPID == "" {
+		return privateIP
+	}
+	if address, ok := addresses[strings.ToLower(publicIPID)]; ok {
+		return address
+	}
+	return resourceName(publicIPID)
+}
+
+// backendVM returns the VM behind a backend IP configuration, or the name of
+// the NIC or scale set it belongs to when that is not a VM's NIC.
+func backendVM(ipConfigurationID string, nics map[string]NetworkInterfaceInfo) string {
+	owner := ownerResourceID(ipConfigurationID)
+	if nic, ok := nics[strings.ToLower(owner)]; ok && nic.VirtualMachine != "" {
+		return nic.VirtualMachine
+	}
+	return resourceName(owner)
+}
+
+// ownerResourceID trims a child resource ID, such as that of an IP
+// configuration, to the top-level resource it belongs to.
+func ownerResourceID(id string) string {
+	parts := strings.Split(id, "/")
+	if len(parts) <= 9 {
+		return id
+	}
+	return strings.Join(parts[:9], "/")
+}
+
+// subnetName returns "vnet/subnet" for a subnet ID.
+func subnetName(id string) string {
+	return resourceIDSegment(id, "virtualNetworks") + "/" + resourceIDSegment(id, "subnets")
+}
+
+func enumValue[T ~string](p *T) string {
+	if p == nil {
+		return ""
+	}
+	return string(*p)
+}
+
+func appendUnique(list []string, items ...string) []string {
+	for _, item := range items {
+		if item != "" && !slices.Contains(list, item) {
+			list = append(list, item)
+		}
+	}
+	return list
+}
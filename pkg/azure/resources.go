@@ -0,0 +1,343 @@
+package azure
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/cosmos/armcosmos"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/sql/armsql"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+)
+
+// Subscribed records the subscription a resource was collected from.
+type Subscribed struct {
+	SubscriptionID   string `json:"subscriptionId,omitempty"`
+	SubscriptionName string `json:"subscriptionName,omitempty"`
+}
+
+// The types below wrap the ARM SDK types with the subscription they came
+// from. They marshal to the SDK's own JSON with subscriptionId and
+// subscriptionName added, so exports keep the ARM shape.
+
+type VirtualMachine struct {
+	armcompute.VirtualMachine
+	Subscribed
+}
+
+type VirtualMachineScaleSet struct {
+	armcompute.VirtualMachineScaleSet
+	Subscribed
+}
+
+type ManagedCluster struct {
+	armcontainerservice.ManagedCluster
+	Subscribed
+}
+
+type StorageAccount struct {
+	armstorage.Account
+	Subscribed
+}
+
+type BlobContainer struct {
+	armstorage.ListContainerItem
+	Subscribed
+}
+
+type VirtualNetwork struct {
+	armnetwork.VirtualNetwork
+	Subscribed
+}
+
+type SQLDatabase struct {
+	armsql.Database
+	Subscribed
+}
+
+type NetworkInterface struct {
+	armnetwork.Interface
+	Subscribed
+}
+
+type NetworkSecurityGroup struct {
+	armnetwork.SecurityGroup
+	Subscribed
+}
+
+type PublicIPAddress struct {
+	armnetwork.PublicIPAddress
+	Subscribed
+}
+
+type LoadBalancer struct {
+	armnetwork.LoadBalancer
+	Subscribed
+}
+
+type ApplicationGateway struct {
+	armnetwork.ApplicationGateway
+	Subscribed
+}
+
+type PrivateEndpoint struct {
+	armnetwork.PrivateEndpoint
+	Subscribed
+}
+
+type SQLServer struct {
+	armsql.Server
+	Subscribed
+}
+
+type SQLManagedInstance struct {
+	armsql.ManagedInstance
+	Subscribed
+}
+
+type SQLManagedDatabase struct {
+	armsql.ManagedDatabase
+	Subscribed
+}
+
+// SQLRetentionPolicy is the long-term retention policy of a database, or of
+// a managed instance database.
+type SQLRetentionPolicy struct {
+	armsql.LongTermRetentionPolicy
+	Subscribed
+}
+
+type SQLReplicationLink struct {
+	armsql.ReplicationLink
+	Subscribed
+}
+
+type CosmosDBAccount struct {
+	armcosmos.DatabaseAccountGetResults
+	Subscribed
+}
+
+type ResourceGroup struct {
+	armresources.ResourceGroup
+	Subscribed
+}
+
+type Disk struct {
+	armcompute.Disk
+	Subscribed
+}
+
+type Snapshot struct {
+	armcompute.Snapshot
+	Subscribed
+}
+
+func (r VirtualMachine) MarshalJSON() ([]byte, error) {
+	return marshalSubscribed(r.VirtualMachine, r.Subscribed)
+}
+
+func (r *VirtualMachine) UnmarshalJSON(data []byte) error {
+	return unmarshalSubscribed(data, &r.VirtualMachine, &r.Subscribed)
+}
+
+func (r VirtualMachineScaleSet) MarshalJSON() ([]byte, error) {
+	return marshalSubscribed(r.VirtualMachineScaleSet, r.Subscribed)
+}
+
+func (r *VirtualMachineScaleSet) UnmarshalJSON(data []byte) error {
+	return unmarshalSubscribed(data, &r.VirtualMachineScaleSet, &r.Subscribed)
+}
+
+func (r ManagedCluster) MarshalJSON() ([]byte, error) {
+	return marshalSubscribed(r.ManagedCluster, r.Subscribed)
+}
+
+func (r *ManagedCluster) UnmarshalJSON(data []byte) error {
+	return unmarshalSubscribed(data, &r.ManagedCluster, &r.Subscribed)
+}
+
+func (r StorageAccount) MarshalJSON() ([]byte, error) {
+	return marshalSubscribed(r.Account, r.Subscribed)
+}
+
+func (r *StorageAccount) UnmarshalJSON(data []byte) error {
+	return unmarshalSubscribed(data, &r.Account, &r.Subscribed)
+}
+
+func (r BlobContainer) MarshalJSON() ([]byte, error) {
+	return marshalSubscribed(r.ListContainerItem, r.Subscribed)
+}
+
+func (r *BlobContainer) UnmarshalJSON(data []byte) error {
+	return unmarshalSubscribed(data, &r.ListContainerItem, &r.Subscribed)
+}
+
+func (r VirtualNetwork) MarshalJSON() ([]byte, error) {
+	return marshalSubscribed(r.VirtualNetwork, r.Subscribed)
+}
+
+func (r *VirtualNetwork) UnmarshalJSON(data []byte) error {
+	return unmarshalSubscribed(data, &r.VirtualNetwork, &r.Subscribed)
+}
+
+func (r SQLDatabase) MarshalJSON() ([]byte, error) {
+	return marshalSubscribed(r.Database, r.Subscribed)
+}
+
+func (r *SQLDatabase) UnmarshalJSON(data []byte) error {
+	return unmarshalSubscribed(data, &r.Database, &r.Subscribed)
+}
+
+func (r NetworkInterface) MarshalJSON() ([]byte, error) {
+	return marshalSubscribed(r.Interface, r.Subscribed)
+}
+
+func (r *NetworkInterface) UnmarshalJSON(data []byte) error {
+	return unmarshalSubscribed(data, &r.Interface, &r.Subscribed)
+}
+
+func (r NetworkSecurityGroup) MarshalJSON() ([]byte, error) {
+	return marshalSubscribed(r.SecurityGroup, r.Subscribed)
+}
+
+func (r *NetworkSecurityGroup) UnmarshalJSON(data []byte) error {
+	return unmarshalSubscribed(data, &r.SecurityGroup, &r.Subscribed)
+}
+
+func (r PublicIPAddress) MarshalJSON() ([]byte, error) {
+	return marshalSubscribed(r.PublicIPAddress, r.Subscribed)
+}
+
+func (r *PublicIPAddress) UnmarshalJSON(data []byte) error {
+	return unmarshalSubscribed(data, &r.PublicIPAddress, &r.Subscribed)
+}
+
+func (r LoadBalancer) MarshalJSON() ([]byte, error) {
+	return marshalSubscribed(r.LoadBalancer, r.Subscribed)
+}
+
+func (r *LoadBalancer) UnmarshalJSON(data []byte) error {
+	return unmarshalSubscribed(data, &r.LoadBalancer, &r.Subscribed)
+}
+
+func (r ApplicationGateway) MarshalJSON() ([]byte, error) {
+	return marshalSubscribed(r.ApplicationGateway, r.Subscribed)
+}
+
+func (r *ApplicationGateway) UnmarshalJSON(data []byte) error {
+	return unmarshalSubscribed(data, &r.ApplicationGateway, &r.Subscribed)
+}
+
+func (r PrivateEndpoint) MarshalJSON() ([]byte, error) {
+	return marshalSubscribed(r.PrivateEndpoint, r.Subscribed)
+}
+
+func (r *PrivateEndpoint) UnmarshalJSON(data []byte) error {
+	return unmarshalSubscribed(data, &r.PrivateEndpoint, &r.Subscribed)
+}
+
+func (r SQLServer) MarshalJSON() ([]byte, error) {
+	return marshalSubscribed(r.Server, r.Subscribed)
+}
+
+func (r *SQLServer) UnmarshalJSON(data []byte) error {
+	return unmarshalSubscribed(data, &r.Server, &r.Subscribed)
+}
+
+func (r SQLManagedInstance) MarshalJSON() ([]byte, error) {
+	return marshalSubscribed(r.ManagedInstance, r.Subscribed)
+}
+
+func (r *SQLManagedInstance) UnmarshalJSON(data []byte) error {
+	return unmarshalSubscribed(data, &r.ManagedInstance, &r.Subscribed)
+}
+
+func (r SQLManagedDatabase) MarshalJSON() ([]byte, error) {
+	return marshalSubscribed(r.ManagedDatabase, r.Subscribed)
+}
+
+func (r *SQLManagedDatabase) UnmarshalJSON(data []byte) error {
+	return unmarshalSubscribed(data, &r.ManagedDatabase, &r.Subscribed)
+}
+
+func (r SQLRetentionPolicy) MarshalJSON() ([]byte, error) {
+	return marshalSubscribed(r.LongTermRetentionPolicy, r.Subscribed)
+}
+
+func (r *SQLRetentionPolicy) UnmarshalJSON(data []byte) error {
+	return unmarshalSubscribed(data, &r.LongTermRetentionPolicy, &r.Subscribed)
+}
+
+func (r SQLReplicationLink) MarshalJSON() ([]byte, error) {
+	return marshalSubscribed(r.ReplicationLink, r.Subscribed)
+}
+
+func (r *SQLReplicationLink) UnmarshalJSON(data []byte) error {
+	return unmarshalSubscribed(data, &r.ReplicationLink, &r.Subscribed)
+}
+
+func (r CosmosDBAccount) MarshalJSON() ([]byte, error) {
+	return marshalSubscribed(r.DatabaseAccountGetResults, r.Subscribed)
+}
+
+func (r *CosmosDBAccount) UnmarshalJSON(data []byte) error {
+	return unmarshalSubscribed(data, &r.DatabaseAccountGetResults, &r.Subscribed)
+}
+
+func (r ResourceGroup) MarshalJSON() ([]byte, error) {
+	return marshalSubscribed(r.ResourceGroup, r.Subscribed)
+}
+
+func (r *ResourceGroup) UnmarshalJSON(data []byte) error {
+	return unmarshalSubscribed(data, &r.ResourceGroup, &r.Subscribed)
+}
+
+func (r Disk) MarshalJSON() ([]byte, error) {
+	return marshalSubscribed(r.Disk, r.Subscribed)
+}
+
+func (r *Disk) UnmarshalJSON(data []byte) error {
+	return unmarshalSubscribed(data, &r.Disk, &r.Subscribed)
+}
+
+func (r Snapshot) MarshalJSON() ([]byte, error) {
+	return marshalSubscribed(r.Snapshot, r.Subscribed)
+}
+
+func (r *Snapshot) UnmarshalJSON(data []byte) error {
+	return unmarshalSubscribed(data, &r.Snapshot, &r.Subscribed)
+}
+
+// marshalSubscribed marshals resource and splices the subscription fields
+// into the start of the resulting object.
+func marshalSubscribed(resource interface{}, s Subscribed) ([]byte, error) {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) <= 2 || len(data) < 2 || data[0] != '{' {
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	buf.Write(fields[:len(fields)-1])
+	if data[1] != '}' {
+		buf.WriteByte(',')
+	}
+	buf.Write(data[1:])
+	return buf.Bytes(), nil
+}
+
+func unmarshalSubscribed(data []byte, resource interface{}, s *Subscribed) error {
+	if err := json.Unmarshal(data, resource); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, s)
+}
@@ -0,0 +1,193 @@
+package azure
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/sql/armsql"
+)
+
+// addSQL adds the logical SQL servers and SQL Managed Instances of a
+// subscription, every database on them, and their retention policies and
+// replication links.
+func (d *AzureData) addSQL(ctx context.Context, subscription Subscription) {
+	subscriptionID, cred, tag := subscription.ID, subscription.cred, subscription.subscribed()
+
+	serverClient, err := armsql.NewServersClient(subscriptionID, cred, nil)
+	if err != nil {
+		log.Printf("Warning: Failed to create SQL server client: %v", err)
+	} else {
+		var servers []SQLServer
+		pager := serverClient.NewListPager(nil)
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				log.Printf("Warning: Failed to get SQL Servers: %v", err)
+				break
+			}
+			for _, server := range page.Value {
+				servers = append(servers, SQLServer{Server: *server, Subscribed: tag})
+			}
+		}
+		d.AzureSQLServers = append(d.AzureSQLServers, servers...)
+
+		dbClient, err := armsql.NewDatabasesClient(subscriptionID, cred, nil)
+		if err != nil {
+			log.Printf("Warning: Failed to create SQL client: %v", err)
+		} else {
+			for _, server := range servers {
+				dbPager := dbClient.NewListByServerPager(getResourceGroupFromID(value(server.ID)), value(server.Name), nil)
+				for dbPager.More() {
+					page, err := dbPager.NextPage(ctx)
+					if err != nil {
+						log.Printf("Warning: Failed to get SQL Databases of server %s: %v", value(server.Name), err)
+						break
+					}
+					for _, db := range page.Value {
+						d.AzureSQLDatabases = append(d.AzureSQLDatabases, SQLDatabase{Database: *db, Subscribed: tag})
+					}
+				}
+			}
+		}
+	}
+
+	instanceClient, err := armsql.NewManagedInstancesClient(subscriptionID, cred, nil)
+	if err != nil {
+		log.Printf("Warning: Failed to create SQL Managed Instance client: %v", err)
+	} else {
+		var instances []SQLManagedInstance
+		pager := instanceClient.NewListPager(nil)
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				log.Printf("Warning: Failed to get SQL Managed Instances: %v", err)
+				break
+			}
+			for _, instance := range page.Value {
+				instances = append(instances, SQLManagedInstance{ManagedInstance: *instance, Subscribed: tag})
+			}
+		}
+		d.AzureSQLManagedInstances = append(d.AzureSQLManagedInstances, instances...)
+
+		dbClient, err := armsql.NewManagedDatabasesClient(subscriptionID, cred, nil)
+		if err != nil {
+			log.Printf("Warning: Failed to create SQL Managed Instance database client: %v", err)
+		} else {
+			for _, instance := range instances {
+				dbPager := dbClient.NewListByInstancePager(getResourceGroupFromID(value(instance.ID)), value(instance.Name), nil)
+				for dbPager.More() {
+					page, err := dbPager.NextPage(ctx)
+					if err != nil {
+						log.Printf("Warning: Failed to get databases of SQL Managed Instance %s: %v", value(instance.Name), err)
+						break
+					}
+					for _, db := range page.Value {
+						d.AzureSQLManagedDatabases = append(d.AzureSQLManagedDatabases, SQLManagedDatabase{ManagedDatabase: *db, Subscribed: tag})
+					}
+				}
+			}
+		}
+	}
+
+	d.addSQLDetails(ctx, subscription)
+}
+
+// addSQLDetails adds the long-term retention policies of the subscription's
+// databases and the replication links of its servers. Neither is in
+// Resource Graph, so they are always listed through ARM.
+func (d *AzureData) addSQLDetails(ctx context.Context, subscription Subscription) {
+	subscriptionID, cred, tag := subscription.ID, subscription.cred, subscription.subscribed()
+
+	linkClient, err := armsql.NewReplicationLinksClient(subscriptionID, cred, nil)
+	if err != nil {
+		log.Printf("Warning: Failed to create SQL replication link client: %v", err)
+	} else {
+		for _, server := range d.AzureSQLServers {
+			if server.SubscriptionID != subscriptionID {
+				continue
+			}
+			pager := linkClient.NewListByServerPager(getResourceGroupFromID(value(server.ID)), value(server.Name), nil)
+			for pager.More() {
+				page, err := pager.NextPage(ctx)
+				if err != nil {
+					log.Printf("Warning: Failed to get replication links of SQL server %s: %v", value(server.Name), err)
+					break
+				}
+				for _, link := range page.Value {
+					d.AzureSQLReplicationLinks = append(d.AzureSQLReplicationLinks, SQLReplicationLink{ReplicationLink: *link, Subscribed: tag})
+				}
+			}
+		}
+	}
+
+	retentionClient, err := armsql.NewLongTermRetentionPoliciesClient(subscriptionID, cred, nil)
+	if err != nil {
+		log.Printf("Warning: Failed to create SQL retention policy client: %v", err)
+	} else {
+		for _, db := range d.AzureSQLDatabases {
+			if db.SubscriptionID != subscriptionID || isSystemDatabase(value(db.Name)) {
+				continue
+			}
+			server := resourceIDSegment(value(db.ID), "servers")
+			pager := retentionClient.NewListByDatabasePager(getResourceGroupFromID(value(db.ID)), server, value(db.Name), nil)
+			for pager.More() {
+				page, err := pager.NextPage(ctx)
+				if err != nil {
+					log.Printf("Warning: Failed to get retention policy of SQL database %s: %v", value(db.Name), err)
+					break
+				}
+				for _, policy := range page.Value {
+					d.AzureSQLRetentionPolicies = append(d.AzureSQLRetentionPolicies, SQLRetentionPolicy{LongTermRetentionPolicy: *policy, Subscribed: tag})
+				}
+			}
+		}
+	}
+
+	instanceRetentionClient, err := armsql.NewManagedInstanceLongTermRetentionPoliciesClient(subscriptionID, cred, nil)
+	if err != nil {
+		log.Printf("Warning: Failed to create SQL Managed Instance retention policy client: %v", err)
+	} else {
+		for _, db := range d.AzureSQLManagedDatabases {
+			if db.SubscriptionID != subscriptionID {
+				continue
+			}
+			instance := resourceIDSegment(value(db.ID), "managedInstances")
+			pager := instanceRetentionClient.NewListByDatabasePager(getResourceGroupFromID(value(db.ID)), instance, value(db.Name), nil)
+			for pager.More() {
+				page, err := pager.NextPage(ctx)
+				if err != nil {
+					log.Printf("Warning: Failed to get retention policy of SQL Managed Instance database %s: %v", value(db.Name), err)
+					break
+				}
+				for _, policy := range page.Value {
+					d.AzureSQLRetentionPolicies = append(d.AzureSQLRetentionPolicies, SQLRetentionPolicy{
+						LongTermRetentionPolicy: armsql.LongTermRetentionPolicy{
+							ID:         policy.ID,
+							Name:       policy.Name,
+							Type:       policy.Type,
+							Properties: policy.Properties,
+						},
+						Subscribed: tag,
+					})
+				}
+			}
+		}
+	}
+}
+
+// isSystemDatabase reports whether a logical server database is the master
+// database, which has no retention policy and is not billed.
+func isSystemDatabase(name string) bool {
+	return strings.EqualFold(name, "master")
+}
+
+// parentResourceID returns the lower-cased ID of the resource a child
+// resource, such as a database or its retention policy, belongs to.
+func parentResourceID(id string) string {
+	parts := strings.Split(strings.TrimSuffix(id, "/"), "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	return strings.ToLower(strings.Join(parts[:len(parts)-2], "/"))
+}
@@ -0,0 +1,164 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/managementgroups/armmanagementgroups"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
+)
+
+// Subscription is an Azure subscription to collect from, with the
+// credential that reaches it.
+type Subscription struct {
+	ID       string
+	Name     string
+	TenantID string
+	cred     azcore.TokenCredential
+}
+
+// SubscriptionInfo records a subscription in the collected data and whether
+// it could be collected. DiskError is set when its managed disks could not
+// all be listed, so its snapshots are not checked for deleted disks.
+type SubscriptionInfo struct {
+	SubscriptionID   string
+	SubscriptionName string
+	TenantID         string
+	Error            string `json:",omitempty"`
+	DiskError        string `json:",omitempty"`
+}
+
+// Subscriptions resolves the enabled subscriptions visible to the default
+// Azure credential. When allow is set, only subscriptions whose ID or name
+// is listed are kept. When managementGroup is set, only subscriptions below
+// that management group are kept.
+func Subscriptions(ctx context.Context, allow []string, managementGroup string) ([]Subscription, error) {
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create credential: %v", err)
+	}
+
+	client, err := armsubscriptions.NewClient(cred, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create subscriptions client: %v", err)
+	}
+
+	var inGroup map[string]bool
+	if managementGroup != "" {
+		inGroup, err = managementGroupSubscriptions(ctx, cred, managementGroup)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	wanted := map[string]bool{}
+	for _, name := range allow {
+		wanted[strings.ToLower(name)] = true
+	}
+	found := map[string]bool{}
+
+	var subscriptions []Subscription
+	pager := client.NewListPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list subscriptions: %v", err)
+		}
+		for _, sub := range page.Value {
+			if sub.SubscriptionID == nil {
+				continue
+			}
+			subscription := Subscription{
+				ID:   *sub.SubscriptionID,
+				cred: cred,
+			}
+			if sub.DisplayName != nil {
+				subscription.Name = *sub.DisplayName
+			}
+			if sub.TenantID != nil {
+				subscription.TenantID = *sub.TenantID
+			}
+
+			if sub.State != nil && *sub.State != armsubscriptions.SubscriptionStateEnabled &&
+				*sub.State != armsubscriptions.SubscriptionStateWarned {
+				continue
+			}
+			if inGroup != nil && !inGroup[strings.ToLower(subscription.ID)] {
+				continue
+			}
+			if len(wanted) > 0 {
+				id, name := strings.ToLower(subscription.ID), strings.ToLower(subscription.Name)
+				if !wanted[id] && !wanted[name] {
+					continue
+				}
+				found[id], found[name] = true, true
+			}
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+
+	for _, name := range allow {
+		if !found[strings.ToLower(name)] {
+			log.Printf("Warning: Azure subscription %s is not enabled or not visible to the credential", name)
+		}
+	}
+	if len(subscriptions) == 0 {
+		return nil, fmt.Errorf("no Azure subscriptions found")
+	}
+	return subscriptions, nil
+}
+
+// managementGroupSubscriptions returns the lower-cased IDs of every
+// subscription below the management group, including nested groups.
+func managementGroupSubscriptions(ctx context.Context, cred azcore.TokenCredential, groupID string) (map[string]bool, error) {
+	client, err := armmanagementgroups.NewClient(cred, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create management groups client: %v", err)
+	}
+
+	ids := map[string]bool{}
+	pager := client.NewGetDescendantsPager(groupID, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list descendants of management group %s: %v", groupID, err)
+		}
+		for _, descendant := range page.Value {
+			if descendant.Type == nil || descendant.Name == nil || !strings.HasSuffix(*descendant.Type, "/subscriptions") {
+				continue
+			}
+			ids[strings.ToLower(*descendant.Name)] = true
+		}
+	}
+	return ids, nil
+}
+
+func (s Subscription) info(err error) SubscriptionInfo {
+	info := SubscriptionInfo{
+		SubscriptionID:   s.ID,
+		SubscriptionName: s.Name,
+		TenantID:         s.TenantID,
+	}
+	if err != nil {
+		info.Error = err.Error()
+	}
+	return info
+}
+
+func (s Subscription) subscribed() Subscribed {
+	return Subscribed{SubscriptionID: s.ID, SubscriptionName: s.Name}
+}
+
+func countFailed(subscriptions []SubscriptionInfo) int {
+	failed := 0
+	for _, subscription := range subscriptions {
+		if subscription.Error != "" {
+			failed++
+		}
+	}
+	return failed
+}
@@ -0,0 +1,110 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Options carries every setting a collector may need. Each collector reads
+// only the fields that apply to its platform.
+type Options struct {
+	Kubeconfig  string
+	KubeContext string
+	StorageOnly bool
+
+	AWSProfile string
+	AWSRole    string
+
+	AzureSubscription    string
+	AzureManagementGroup string
+	AzureResourceGraph   bool
+	AzureRaw             bool
+
+	DockerHost string
+
+	VaultAddr     string
+	VaultToken    string
+	VaultInsecure bool
+
+	VeeamURL       string
+	VeeamUsername  string
+	VeeamPassword  string
+	VeeamIgnoreSSL bool
+
+	TerraformStateFile      string
+	TerraformS3Bucket       string
+	TerraformS3Region       string
+	TerraformAzureContainer string
+	TerraformGCSBucket      string
+}
+
+// Collector is implemented by every inventory source.
+type Collector interface {
+	Name() string
+	CheckCredentials(ctx context.Context, opts Options) (bool, error)
+	Collect(ctx context.Context, opts Options) (interface{}, error)
+}
+
+// SnapshotCollector is implemented by collectors that can also be used by
+// Snapshot Hunter.
+type SnapshotCollector interface {
+	Collector
+	CollectSnapshots(ctx context.Context, opts Options) (map[string]interface{}, error)
+}
+
+// Identifier is implemented by collectors that can describe which account,
+// subscription, project, cluster or server the collected data came from.
+type Identifier interface {
+	Identity(ctx context.Context, opts Options, data interface{}) (map[string]string, error)
+}
+
+var (
+	registryMutex sync.RWMutex
+	registry      = map[string]Collector{}
+)
+
+func Register(c Collector) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	name := c.Name()
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("collector %q registered twice", name))
+	}
+	registry[name] = c
+}
+
+func Get(name string) (Collector, bool) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	c, ok := registry[name]
+	return c, ok
+}
+
+func Names() []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func All() []Collector {
+	names := Names()
+
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	collectors := make([]Collector, 0, len(names))
+	for _, name := range names {
+		collectors = append(collectors, registry[name])
+	}
+	return collectors
+}
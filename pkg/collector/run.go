@@ -0,0 +1,163 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/michaelcade/kollect/pkg/inventory"
+)
+
+// ParseNames turns the value of --inventory into a list of registered
+// collector names. It accepts a single name, a comma separated list, or "all".
+func ParseNames(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "all" {
+		return Names(), nil
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		if _, ok := Get(name); !ok {
+			return nil, fmt.Errorf("invalid inventory type: %s", name)
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no inventory type specified")
+	}
+	return names, nil
+}
+
+// Source is a named inventory source. Several sources can use the same
+// platform with different options, such as one per Kubernetes context.
+// Resolve, when set, fills in options that must be looked up again on every
+// collection, such as secrets read from files or the environment.
+type Source struct {
+	Name     string
+	Platform string
+	Options  Options
+	Resolve  func(opts *Options) error
+}
+
+// Sources returns one source per platform name, each named after its
+// platform and sharing opts.
+func Sources(names []string, opts Options) []Source {
+	sources := make([]Source, 0, len(names))
+	for _, name := range names {
+		sources = append(sources, Source{Name: name, Platform: name, Options: opts})
+	}
+	return sources
+}
+
+// Run collects every named platform concurrently with the same options.
+func Run(ctx context.Context, names []string, opts Options) *inventory.Document {
+	return RunSources(ctx, Sources(names, opts))
+}
+
+// RunSources collects every source concurrently and returns a document with
+// one result per source. A failing source is recorded in the document and
+// does not stop the others.
+func RunSources(ctx context.Context, sources []Source) *inventory.Document {
+	doc := inventory.NewDocument()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, src := range sources {
+		wg.Add(1)
+		go func(src Source) {
+			defer wg.Done()
+			result := runOne(ctx, src)
+
+			mu.Lock()
+			doc.Sources[src.Name] = result
+			mu.Unlock()
+		}(src)
+	}
+	wg.Wait()
+
+	doc.Finish()
+	return doc
+}
+
+func runOne(ctx context.Context, src Source) *inventory.SourceResult {
+	startedAt := time.Now().UTC()
+
+	c, ok := Get(src.Platform)
+	if !ok {
+		return failed(src, startedAt, fmt.Errorf("unknown inventory type: %s", src.Platform))
+	}
+
+	if src.Resolve != nil {
+		if err := src.Resolve(&src.Options); err != nil {
+			log.Printf("Warning: Error collecting %s inventory: %v", src.Name, err)
+			return failed(src, startedAt, err)
+		}
+	}
+
+	log.Printf("Collecting %s inventory...", src.Name)
+	collected, err := c.Collect(ctx, src.Options)
+	if err != nil {
+		log.Printf("Warning: Error collecting %s inventory: %v", src.Name, err)
+		return failed(src, startedAt, err)
+	}
+
+	result := succeeded(ctx, src, collected, startedAt)
+	log.Printf("Collected %s inventory in %.1fs", src.Name, result.DurationSeconds)
+	return result
+}
+
+// Succeeded builds the result for data collected from the named platform,
+// including its identity when the collector provides one.
+func Succeeded(ctx context.Context, name string, opts Options, collected interface{}, startedAt time.Time) *inventory.SourceResult {
+	return succeeded(ctx, Source{Name: name, Platform: name, Options: opts}, collected, startedAt)
+}
+
+func succeeded(ctx context.Context, src Source, collected interface{}, startedAt time.Time) *inventory.SourceResult {
+	result := newResult(src.Platform, startedAt)
+	result.Status = inventory.StatusSuccess
+	result.Data = collected
+
+	if c, ok := Get(src.Platform); ok {
+		if identifier, ok := c.(Identifier); ok {
+			identity, err := identifier.Identity(ctx, src.Options, collected)
+			if err != nil {
+				log.Printf("Warning: Unable to identify %s source: %v", src.Name, err)
+			}
+			if len(identity) > 0 {
+				result.Identity = identity
+			}
+		}
+	}
+	return result
+}
+
+func Failed(name string, startedAt time.Time, err error) *inventory.SourceResult {
+	return failed(Source{Name: name, Platform: name}, startedAt, err)
+}
+
+func failed(src Source, startedAt time.Time, err error) *inventory.SourceResult {
+	result := newResult(src.Platform, startedAt)
+	result.Status = inventory.StatusFailed
+	result.Error = err.Error()
+	return result
+}
+
+func newResult(name string, startedAt time.Time) *inventory.SourceResult {
+	finishedAt := time.Now().UTC()
+	return &inventory.SourceResult{
+		Platform:        name,
+		StartedAt:       startedAt,
+		FinishedAt:      finishedAt,
+		DurationSeconds: finishedAt.Sub(startedAt).Seconds(),
+	}
+}
@@ -0,0 +1,257 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/michaelcade/kollect/pkg/collector"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the contents of a kollect configuration file.
+type Config struct {
+	Sources []Source `yaml:"sources"`
+}
+
+// Source is one named inventory source. Option keys match the command line
+// flags, so `kube-context: prod` in a source is the same as
+// `--kube-context prod`.
+type Source struct {
+	Name     string `yaml:"name"`
+	Type     string `yaml:"type"`
+	Schedule string `yaml:"schedule"`
+
+	Kubeconfig  string `yaml:"kubeconfig"`
+	KubeContext string `yaml:"kube-context"`
+	Storage     bool   `yaml:"storage"`
+
+	AWSProfile string `yaml:"aws-profile"`
+	AWSRole    string `yaml:"aws-role"`
+
+	AzureSubscription    string `yaml:"azure-subscription"`
+	AzureManagementGroup string `yaml:"azure-management-group"`
+	AzureResourceGraph   bool   `yaml:"azure-resource-graph"`
+	AzureRaw             bool   `yaml:"azure-raw"`
+
+	DockerHost string `yaml:"docker-host"`
+
+	VaultAddr     string `yaml:"vault-addr"`
+	VaultToken    Secret `yaml:"vault-token"`
+	VaultInsecure bool   `yaml:"vault-insecure"`
+
+	VeeamURL       string `yaml:"veeam-url"`
+	VeeamUsername  Secret `yaml:"veeam-username"`
+	VeeamPassword  Secret `yaml:"veeam-password"`
+	VeeamIgnoreSSL *bool  `yaml:"veeam-ignore-ssl"`
+
+	TerraformState    string `yaml:"terraform-state"`
+	TerraformS3       string `yaml:"terraform-s3"`
+	TerraformS3Region string `yaml:"terraform-s3-region"`
+	TerraformAzure    string `yaml:"terraform-azure"`
+	TerraformGCS      string `yaml:"terraform-gcs"`
+}
+
+// Secret is a value that is written inline or read from an environment
+// variable or a file when the configuration is used:
+//
+//	vault-token: hvs.example
+//	vault-token: {env: VAULT_TOKEN_PROD}
+//	vault-token: {file: /run/secrets/vault-token}
+type Secret struct {
+	Value string
+	Env   string
+	File  string
+}
+
+func (s *Secret) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&s.Value)
+	}
+
+	var ref struct {
+		Env  string `yaml:"env"`
+		File string `yaml:"file"`
+	}
+	if err := node.Decode(&ref); err != nil {
+		return err
+	}
+	if (ref.Env == "") == (ref.File == "") {
+		return fmt.Errorf("line %d: a secret reference needs exactly one of env or file", node.Line)
+	}
+	s.Env = ref.Env
+	s.File = ref.File
+	return nil
+}
+
+// Resolve returns the secret's value, reading it from the environment or
+// from disk when it is a reference.
+func (s Secret) Resolve() (string, error) {
+	switch {
+	case s.Env != "":
+		value, ok := os.LookupEnv(s.Env)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", s.Env)
+		}
+		return value, nil
+	case s.File != "":
+		content, err := os.ReadFile(s.File)
+		if err != nil {
+			return "", fmt.Errorf("error reading secret file: %v", err)
+		}
+		return strings.TrimRight(string(content), "\r\n"), nil
+	}
+	return s.Value, nil
+}
+
+// Load reads and validates a configuration file. Unknown keys are rejected
+// so that typos are reported rather than ignored.
+func Load(path string) (*Config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %v", err)
+	}
+
+	var cfg Config
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("error parsing config file %s: %v", path, err)
+	}
+
+	if len(cfg.Sources) == 0 {
+		return nil, fmt.Errorf("config file %s has no sources", path)
+	}
+
+	seen := map[string]bool{}
+	for i := range cfg.Sources {
+		src := &cfg.Sources[i]
+		if src.Type == "" {
+			return nil, fmt.Errorf("source %d in %s has no type", i+1, path)
+		}
+		if _, ok := collector.Get(src.Type); !ok {
+			return nil, fmt.Errorf("source %d in %s has invalid type %q (available: %s)", i+1, path, src.Type, strings.Join(collector.Names(), ", "))
+		}
+		if src.Name == "" {
+			src.Name = src.Type
+		}
+		if seen[src.Name] {
+			return nil, fmt.Errorf("source name %q is used more than once in %s", src.Name, path)
+		}
+		seen[src.Name] = true
+	}
+
+	return &cfg, nil
+}
+
+// Select returns the sources named in value, which is a comma separated list
+// of source names or types, or "all". An empty value selects every source.
+func (c *Config) Select(value string) ([]Source, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "all" {
+		return c.Sources, nil
+	}
+
+	var selected []Source
+	added := map[string]bool{}
+	for _, want := range strings.Split(value, ",") {
+		want = strings.TrimSpace(want)
+		if want == "" {
+			continue
+		}
+		found := false
+		for _, src := range c.Sources {
+			if src.Name != want && src.Type != want {
+				continue
+			}
+			found = true
+			if !added[src.Name] {
+				added[src.Name] = true
+				selected = append(selected, src)
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no source named %s in config", want)
+		}
+	}
+
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no inventory type specified")
+	}
+	return selected, nil
+}
+
+// Collector returns the source in the form used to run collections. Its
+// secrets are resolved once here, so that a missing one is reported
+// straight away, and again before every collection, so that rotated secrets
+// are picked up by long-running schedules.
+func (s Source) Collector() (collector.Source, error) {
+	opts := collector.Options{
+		Kubeconfig:              s.Kubeconfig,
+		KubeContext:             s.KubeContext,
+		StorageOnly:             s.Storage,
+		AWSProfile:              s.AWSProfile,
+		AWSRole:                 s.AWSRole,
+		AzureSubscription:       s.AzureSubscription,
+		AzureManagementGroup:    s.AzureManagementGroup,
+		AzureResourceGraph:      s.AzureResourceGraph,
+		AzureRaw:                s.AzureRaw,
+		DockerHost:              s.DockerHost,
+		VaultAddr:               s.VaultAddr,
+		VaultInsecure:           s.VaultInsecure,
+		VeeamURL:                s.VeeamURL,
+		VeeamIgnoreSSL:          true,
+		TerraformStateFile:      s.TerraformState,
+		TerraformS3Bucket:       s.TerraformS3,
+		TerraformS3Region:       s.TerraformS3Region,
+		TerraformAzureContainer: s.TerraformAzure,
+		TerraformGCSBucket:      s.TerraformGCS,
+	}
+	if opts.Kubeconfig == "" {
+		opts.Kubeconfig = filepath.Join(os.Getenv("HOME"), ".kube", "config")
+	}
+	if s.VeeamIgnoreSSL != nil {
+		opts.VeeamIgnoreSSL = *s.VeeamIgnoreSSL
+	}
+	if err := s.resolveSecrets(&opts); err != nil {
+		return collector.Source{}, err
+	}
+
+	return collector.Source{Name: s.Name, Platform: s.Type, Options: opts, Resolve: s.resolveSecrets}, nil
+}
+
+// resolveSecrets sets the secret options of opts from the source's secrets.
+func (s Source) resolveSecrets(opts *collector.Options) error {
+	secrets := []struct {
+		key    string
+		secret Secret
+		target *string
+	}{
+		{"vault-token", s.VaultToken, &opts.VaultToken},
+		{"veeam-username", s.VeeamUsername, &opts.VeeamUsername},
+		{"veeam-password", s.VeeamPassword, &opts.VeeamPassword},
+	}
+	for _, secret := range secrets {
+		value, err := secret.secret.Resolve()
+		if err != nil {
+			return fmt.Errorf("source %s: %s: %v", s.Name, secret.key, err)
+		}
+		*secret.target = value
+	}
+	return nil
+}
+
+// Collectors resolves every source in sources.
+func Collectors(sources []Source) ([]collector.Source, error) {
+	resolved := make([]collector.Source, 0, len(sources))
+	for _, src := range sources {
+		c, err := src.Collector()
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, c)
+	}
+	return resolved, nil
+}
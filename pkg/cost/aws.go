@@ -47,6 +47,7 @@ func CalculateEbsSnapshotCosts(snapshots []map[string]string) []map[string]inter
 			"SnapshotId":      snapshot["SnapshotId"],
 			"Description":     snapshot["Description"],
 			"VolumeId":        snapshot["VolumeId"],
+			"VolumeDeleted":   snapshot["VolumeDeleted"] == "true",
 			"SizeGB":          sizeGB,
 			"Region":          region,
 			"State":           snapshot["State"],
@@ -136,6 +137,8 @@ func EstimateAwsResourceCosts(resourceData map[string]interface{}) (map[string]i
 
 	var totalSnapshotStorage float64
 	var totalMonthlyCost float64
+	var orphanedSnapshotCost float64
+	orphanedSnapshots := 0
 
 	if ebsCosts, ok := costData["EBSSnapshotCosts"].([]map[string]interface{}); ok {
 		for _, cost := range ebsCosts {
@@ -144,6 +147,10 @@ func EstimateAwsResourceCosts(resourceData map[string]interface{}) (map[string]i
 			}
 			if monthlyCost, ok := cost["MonthlyCost"].(float64); ok {
 				totalMonthlyCost += monthlyCost
+				if deleted, _ := cost["VolumeDeleted"].(bool); deleted {
+					orphanedSnapshotCost += monthlyCost
+					orphanedSnapshots++
+				}
 			}
 		}
 	}
@@ -163,11 +170,13 @@ func EstimateAwsResourceCosts(resourceData map[string]interface{}) (map[string]i
 	lastVerified := GetPricingMetadata("aws", "ebs_snapshot").LastVerified.Format("2006-01-02")
 
 	costData["Summary"] = map[string]interface{}{
-		"TotalSnapshotStorage": totalSnapshotStorage,
-		"TotalMonthlyCost":     totalMonthlyCost,
-		"Currency":             "USD",
-		"PriceSource":          priceSource,
-		"LastVerified":         lastVerified,
+		"TotalSnapshotStorage":  totalSnapshotStorage,
+		"TotalMonthlyCost":      totalMonthlyCost,
+		"OrphanedSnapshotCount": orphanedSnapshots,
+		"OrphanedSnapshotCost":  orphanedSnapshotCost,
+		"Currency":              "USD",
+		"PriceSource":           priceSource,
+		"LastVerified":          lastVerified,
 	}
 
 	return costData, nil
@@ -184,18 +193,46 @@ func ConvertAwsDataForCostAnalysis(ctx context.Context) (map[string]interface{},
 	if len(awsData.EC2Instances) > 0 {
 		instances := make([]map[string]interface{}, len(awsData.EC2Instances))
 		for i, instance := range awsData.EC2Instances {
+			volumes := make([]map[string]interface{}, len(instance.Volumes))
+			for j, volume := range instance.Volumes {
+				volumes[j] = map[string]interface{}{
+					"VolumeId":   volume.VolumeID,
+					"SizeGB":     float64(volume.SizeGiB),
+					"VolumeType": volume.VolumeType,
+				}
+			}
 			instances[i] = map[string]interface{}{
 				"InstanceId":   instance.InstanceID,
 				"Name":         instance.Name,
 				"InstanceType": instance.Type,
 				"State":        instance.State,
 				"Region":       instance.Region,
+				"Platform":     instance.Platform,
+				"Lifecycle":    instance.Lifecycle,
+				"Volumes":      volumes,
 			}
 		}
 		inventory["EC2Instances"] = instances
 		log.Printf("Added %d EC2 instances to cost inventory", len(instances))
 	}
 
+	if len(awsData.EBSVolumes) > 0 {
+		volumes := make([]map[string]interface{}, len(awsData.EBSVolumes))
+		for i, volume := range awsData.EBSVolumes {
+			volumes[i] = map[string]interface{}{
+				"VolumeId":   volume.VolumeID,
+				"Name":       volume.Name,
+				"SizeGB":     float64(volume.SizeGiB),
+				"VolumeType": volume.VolumeType,
+				"State":      volume.State,
+				"Unattached": volume.Unattached,
+				"Region":     volume.Region,
+			}
+		}
+		inventory["EBSVolumes"] = volumes
+		log.Printf("Added %d EBS volumes to cost inventory", len(volumes))
+	}
+
 	if len(awsData.S3Buckets) > 0 {
 		buckets := make([]map[string]interface{}, len(awsData.S3Buckets))
 		for i, bucket := range awsData.S3Buckets {
@@ -206,6 +243,14 @@ func ConvertAwsDataForCostAnalysis(ctx context.Context) (map[string]interface{},
 				"SizeGB":       100.0,
 				"StorageClass": "STANDARD",
 			}
+			if len(bucket.StorageClassBytes) > 0 {
+				storageClassGB := make(map[string]float64, len(bucket.StorageClassBytes))
+				for storageType, size := range bucket.StorageClassBytes {
+					storageClassGB[storageType] = float64(size) / bytesPerGB
+				}
+				buckets[i]["SizeGB"] = float64(bucket.SizeBytes) / bytesPerGB
+				buckets[i]["StorageClassGB"] = storageClassGB
+			}
 		}
 		inventory["S3Buckets"] = buckets
 		log.Printf("Added %d S3 buckets to cost inventory", len(buckets))
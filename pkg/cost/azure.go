@@ -15,7 +15,11 @@ func CalculateAzureDiskSnapshotCosts(snapshots []map[string]string) []map[string
 
 	for _, snapshot := range snapshots {
 		var sizeGB float64 = 0
-		if sizeStr, ok := snapshot["DiskSizeGB"]; ok && sizeStr != "" {
+		sizeStr, ok := snapshot["SizeGB"]
+		if !ok {
+			sizeStr, ok = snapshot["DiskSizeGB"]
+		}
+		if ok && sizeStr != "" {
 			numStr := strings.TrimSpace(sizeStr)
 			numStr = strings.TrimSuffix(numStr, " GB")
 			numStr = strings.Split(numStr, " ")[0]
@@ -46,6 +50,8 @@ func CalculateAzureDiskSnapshotCosts(snapshots []map[string]string) []map[string
 		result := map[string]interface{}{
 			"Name":            snapshot["Name"],
 			"ResourceGroup":   snapshot["ResourceGroup"],
+			"SourceDiskId":    snapshot["SourceDiskId"],
+			"DiskDeleted":     snapshot["DiskDeleted"] == "true",
 			"SizeGB":          sizeGB,
 			"Location":        region,
 			"CreationTime":    snapshot["TimeCreated"],
@@ -73,6 +79,8 @@ func EstimateAzureResourceCosts(resourceData map[string]interface{}) (map[string
 
 	var totalSnapshotStorage float64
 	var totalMonthlyCost float64
+	var orphanedSnapshotCost float64
+	orphanedSnapshots := 0
 
 	if diskCosts, ok := costData["DiskSnapshotCosts"].([]map[string]interface{}); ok {
 		for _, cost := range diskCosts {
@@ -81,6 +89,10 @@ func EstimateAzureResourceCosts(resourceData map[string]interface{}) (map[string
 			}
 			if monthlyCost, ok := cost["MonthlyCost"].(float64); ok {
 				totalMonthlyCost += monthlyCost
+				if deleted, _ := cost["DiskDeleted"].(bool); deleted {
+					orphanedSnapshotCost += monthlyCost
+					orphanedSnapshots++
+				}
 			}
 		}
 	}
@@ -89,11 +101,13 @@ func EstimateAzureResourceCosts(resourceData map[string]interface{}) (map[string
 	lastVerified := GetPricingMetadata("azure", "disk_snapshot").LastVerified.Format("2006-01-02")
 
 	costData["Summary"] = map[string]interface{}{
-		"TotalSnapshotStorage": totalSnapshotStorage,
-		"TotalMonthlyCost":     totalMonthlyCost,
-		"Currency":             "USD",
-		"PriceSource":          priceSource,
-		"LastVerified":         lastVerified,
+		"TotalSnapshotStorage":  totalSnapshotStorage,
+		"TotalMonthlyCost":      totalMonthlyCost,
+		"OrphanedSnapshotCount": orphanedSnapshots,
+		"OrphanedSnapshotCost":  orphanedSnapshotCost,
+		"Currency":              "USD",
+		"PriceSource":           priceSource,
+		"LastVerified":          lastVerified,
 	}
 
 	return costData, nil
@@ -107,101 +121,57 @@ func ConvertAzureDataForCostAnalysis(ctx context.Context) (map[string]interface{
 
 	inventory := make(map[string]interface{})
 
-	if len(azureData.AzureVMs) > 0 {
-		vms := make([]map[string]interface{}, len(azureData.AzureVMs))
-		for i, vm := range azureData.AzureVMs {
-			resourceGroup := "unknown"
-			if vm.ID != nil {
-				parts := strings.Split(*vm.ID, "/")
-				for j := 0; j < len(parts); j++ {
-					if parts[j] == "resourceGroups" && j+1 < len(parts) {
-						resourceGroup = parts[j+1]
-						break
-					}
-				}
-			}
-
-			vmSize := "unknown"
-			if vm.Properties != nil && vm.Properties.HardwareProfile != nil && vm.Properties.HardwareProfile.VMSize != nil {
-				vmSize = fmt.Sprint(*vm.Properties.HardwareProfile.VMSize)
-			}
-
-			name := "unknown"
-			if vm.Name != nil {
-				name = *vm.Name
-			}
-			location := "unknown"
-			if vm.Location != nil {
-				location = *vm.Location
-			}
+	if len(azureData.VirtualMachines) > 0 {
+		vms := make([]map[string]interface{}, len(azureData.VirtualMachines))
+		for i, vm := range azureData.VirtualMachines {
 			vms[i] = map[string]interface{}{
-				"Name":          name,
-				"ResourceGroup": resourceGroup,
-				"Location":      location,
-				"VMSize":        vmSize,
+				"Name":          orUnknown(vm.Name),
+				"ResourceGroup": orUnknown(vm.ResourceGroup),
+				"Location":      orUnknown(vm.Location),
+				"VMSize":        orUnknown(vm.Size),
 			}
 		}
 		inventory["VirtualMachines"] = vms
 	}
 
-	if len(azureData.AzureStorageAccounts) > 0 {
-		accounts := make([]map[string]interface{}, len(azureData.AzureStorageAccounts))
-		for i, account := range azureData.AzureStorageAccounts {
-			resourceGroup := "unknown"
-			if account.ID != nil {
-				parts := strings.Split(*account.ID, "/")
-				for j := 0; j < len(parts); j++ {
-					if parts[j] == "resourceGroups" && j+1 < len(parts) {
-						resourceGroup = parts[j+1]
-						break
-					}
-				}
+	if len(azureData.Disks) > 0 {
+		disks := make([]map[string]interface{}, len(azureData.Disks))
+		for i, disk := range azureData.Disks {
+			disks[i] = map[string]interface{}{
+				"ID":            disk.ID,
+				"Name":          disk.Name,
+				"ResourceGroup": disk.ResourceGroup,
+				"Location":      disk.Location,
+				"SizeGB":        float64(disk.SizeGB),
+				"SKU":           disk.SKU,
+				"DiskState":     disk.DiskState,
+				"Unattached":    disk.Unattached,
 			}
+		}
+		inventory["ManagedDisks"] = disks
+		log.Printf("Added %d managed disks to cost inventory", len(disks))
+	}
 
-			name := "unknown"
-			if account.Name != nil {
-				name = *account.Name
-			}
-			location := "unknown"
-			if account.Location != nil {
-				location = *account.Location
-			}
+	if len(azureData.StorageAccounts) > 0 {
+		accounts := make([]map[string]interface{}, len(azureData.StorageAccounts))
+		for i, account := range azureData.StorageAccounts {
 			accounts[i] = map[string]interface{}{
-				"Name":           name,
-				"ResourceGroup":  resourceGroup,
-				"Location":       location,
+				"Name":           orUnknown(account.Name),
+				"ResourceGroup":  orUnknown(account.ResourceGroup),
+				"Location":       orUnknown(account.Location),
 				"UsedCapacityGB": 100.0,
 			}
 		}
 		inventory["StorageAccounts"] = accounts
 	}
 
-	if len(azureData.AzureSQLDatabases) > 0 {
-		databases := make([]map[string]interface{}, len(azureData.AzureSQLDatabases))
-		for i, db := range azureData.AzureSQLDatabases {
-			resourceGroup := "unknown"
-			if db.ID != nil {
-				parts := strings.Split(*db.ID, "/")
-				for j := 0; j < len(parts); j++ {
-					if parts[j] == "resourceGroups" && j+1 < len(parts) {
-						resourceGroup = parts[j+1]
-						break
-					}
-				}
-			}
-
-			name := "unknown"
-			if db.Name != nil {
-				name = *db.Name
-			}
-			location := "unknown"
-			if db.Location != nil {
-				location = *db.Location
-			}
+	if len(azureData.SQLDatabases) > 0 {
+		databases := make([]map[string]interface{}, len(azureData.SQLDatabases))
+		for i, db := range azureData.SQLDatabases {
 			databases[i] = map[string]interface{}{
-				"Name":          name,
-				"ResourceGroup": resourceGroup,
-				"Location":      location,
+				"Name":          orUnknown(db.Name),
+				"ResourceGroup": orUnknown(db.ResourceGroup),
+				"Location":      orUnknown(db.Location),
 			}
 		}
 		inventory["SQLDatabases"] = databases
@@ -218,3 +188,10 @@ func ConvertAzureDataForCostAnalysis(ctx context.Context) (map[string]interface{
 
 	return inventory, nil
 }
+
+func orUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
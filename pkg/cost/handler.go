@@ -393,8 +393,8 @@ func isEmpty(data map[string]interface{}) bool {
 	}
 
 	for _, key := range []string{
-		"EBSSnapshots", "RDSSnapshots", "EC2Instances", "RDSInstances", "S3Buckets", "DynamoDBTables", "VPCs",
-		"DiskSnapshots", "VirtualMachines", "StorageAccounts", "SQLDatabases",
+		"EBSSnapshots", "RDSSnapshots", "EC2Instances", "RDSInstances", "S3Buckets", "DynamoDBTables", "VPCs", "EBSVolumes",
+		"DiskSnapshots", "VirtualMachines", "StorageAccounts", "SQLDatabases", "ManagedDisks",
 		"DiskSnapshots", "ComputeInstances", "GCSBuckets", "CloudSQLInstances", "CloudRunServices", "CloudFunctions"} {
 
 		if resources := countResources(data, key); resources > 0 {
@@ -459,6 +459,17 @@ func GenerateMockResourceData(platform, resourceType string) map[string]interfac
 			},
 		}
 
+		data["EBSVolumes"] = []map[string]interface{}{
+			{
+				"VolumeId":   "vol-0123456789abcdef0",
+				"SizeGB":     200.0,
+				"VolumeType": "gp2",
+				"State":      "available",
+				"Unattached": true,
+				"Region":     "us-east-1",
+			},
+		}
+
 		snapshots := GenerateMockSnapshotData(platform)
 		for k, v := range snapshots {
 			data[k] = v
@@ -506,6 +517,18 @@ func GenerateMockResourceData(platform, resourceType string) map[string]interfac
 			},
 		}
 
+		data["ManagedDisks"] = []map[string]interface{}{
+			{
+				"Name":          "vm-old-app3_DataDisk_0",
+				"ResourceGroup": "production-rg",
+				"Location":      "eastus",
+				"SizeGB":        256.0,
+				"SKU":           "Premium_LRS",
+				"DiskState":     "Unattached",
+				"Unattached":    true,
+			},
+		}
+
 		snapshots := GenerateMockSnapshotData(platform)
 		for k, v := range snapshots {
 			data[k] = v
@@ -13,11 +13,12 @@ func GenerateMockSnapshotData(platform string) map[string]interface{} {
 					"StartTime":  "2023-05-15T00:00:00Z",
 				},
 				{
-					"SnapshotId": "snap-0def987654321abc0",
-					"VolumeId":   "vol-0def987654321abc0",
-					"State":      "completed",
-					"VolumeSize": "250",
-					"StartTime":  "2023-05-10T00:00:00Z",
+					"SnapshotId":    "snap-0def987654321abc0",
+					"VolumeId":      "vol-0def987654321abc0",
+					"State":         "completed",
+					"VolumeSize":    "250",
+					"StartTime":     "2023-05-10T00:00:00Z",
+					"VolumeDeleted": "true",
 				},
 			},
 			"RDSSnapshots": []map[string]string{
@@ -46,6 +47,8 @@ func GenerateMockSnapshotData(platform string) map[string]interface{} {
 					"Location":          "westeurope",
 					"ProvisioningState": "Succeeded",
 					"TimeCreated":       "2023-05-15T00:00:00Z",
+					"SourceDiskId":      "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/development-rg/providers/Microsoft.Compute/disks/vm2-datadisk",
+					"DiskDeleted":       "true",
 				},
 			},
 		}
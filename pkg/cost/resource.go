@@ -1,7 +1,9 @@
 package cost
 
 import (
+	"fmt"
 	"log"
+	"sort"
 	"strings"
 )
 
@@ -42,14 +44,17 @@ func (r *ResourceCostEstimator) EstimateAwsResourcesCost(resourceData map[string
 					}
 				}
 
-				monthlyCost := hourlyCost * 24 * 30
+				computeCost := hourlyCost * 24 * 30
+				storageCost := ebsVolumesMonthlyCost(instance["Volumes"])
+				monthlyCost := computeCost + storageCost
 
 				cost := map[string]interface{}{
-					"InstanceId":   instance["InstanceId"],
-					"InstanceType": instance["InstanceType"],
-					"Region":       instance["Region"],
-					"HourlyCost":   hourlyCost,
-					"MonthlyCost":  monthlyCost,
+					"InstanceId":         instance["InstanceId"],
+					"InstanceType":       instance["InstanceType"],
+					"Region":             instance["Region"],
+					"HourlyCost":         hourlyCost,
+					"StorageMonthlyCost": storageCost,
+					"MonthlyCost":        monthlyCost,
 				}
 
 				ec2Costs = append(ec2Costs, cost)
@@ -78,25 +83,29 @@ func (r *ResourceCostEstimator) EstimateAwsResourcesCost(resourceData map[string
 					storageClass = sc
 				}
 
-				pricePerGB := 0.023
+				pricePerGB := s3PricePerGB(storageClass)
+				monthlyCost := sizeGB * pricePerGB
 
-				switch storageClass {
-				case "STANDARD_IA":
-					pricePerGB = 0.0125
-				case "ONEZONE_IA":
-					pricePerGB = 0.01
-				case "GLACIER":
-					pricePerGB = 0.004
-				case "DEEP_ARCHIVE":
-					pricePerGB = 0.00099
+				// With CloudWatch storage metrics the size is real and each
+				// storage type is priced separately.
+				storageClassGB, measured := bucket["StorageClassGB"].(map[string]float64)
+				if measured {
+					sizeGB, monthlyCost = 0, 0
+					for storageType, size := range storageClassGB {
+						sizeGB += size
+						monthlyCost += size * s3PricePerGB(storageType)
+					}
+					if sizeGB > 0 {
+						pricePerGB = monthlyCost / sizeGB
+					}
+					storageClass = strings.Join(sortedKeys(storageClassGB), ", ")
 				}
 
-				monthlyCost := sizeGB * pricePerGB
-
 				cost := map[string]interface{}{
 					"Name":         bucket["Name"],
 					"Region":       bucket["Region"],
 					"SizeGB":       sizeGB,
+					"SizeMeasured": measured,
 					"StorageClass": storageClass,
 					"PricePerGB":   pricePerGB,
 					"MonthlyCost":  monthlyCost,
@@ -111,10 +120,36 @@ func (r *ResourceCostEstimator) EstimateAwsResourcesCost(resourceData map[string
 		}
 	}
 
+	// Attached volumes are already priced with their instance, so only
+	// unattached volumes are added here.
+	var orphanedVolumeCost float64
+	if volumes, ok := resourceData["EBSVolumes"].([]map[string]interface{}); ok {
+		var volumeCosts []map[string]interface{}
+		for _, volume := range volumes {
+			if unattached, _ := volume["Unattached"].(bool); !unattached {
+				continue
+			}
+			sizeGB, _ := volume["SizeGB"].(float64)
+			monthlyCost := ebsVolumeMonthlyCost(sizeGB, fmt.Sprint(volume["VolumeType"]))
+			volumeCosts = append(volumeCosts, map[string]interface{}{
+				"VolumeId":    volume["VolumeId"],
+				"Name":        volume["Name"],
+				"SizeGB":      sizeGB,
+				"VolumeType":  volume["VolumeType"],
+				"Region":      volume["Region"],
+				"MonthlyCost": monthlyCost,
+			})
+			orphanedVolumeCost += monthlyCost
+		}
+		costData["UnattachedVolumeCosts"] = volumeCosts
+		log.Printf("Found %d unattached EBS volumes costing $%.2f per month", len(volumeCosts), orphanedVolumeCost)
+	}
+
 	if summary, ok := costData["Summary"].(map[string]interface{}); ok {
 		if totalCost, ok := summary["TotalMonthlyCost"].(float64); ok {
-			summary["TotalMonthlyCost"] = totalCost + totalResourceCost
+			summary["TotalMonthlyCost"] = totalCost + totalResourceCost + orphanedVolumeCost
 			summary["TotalComputeCost"] = totalResourceCost
+			summary["OrphanedVolumeCost"] = orphanedVolumeCost
 			log.Printf("Updated summary with total AWS resource cost of $%.2f", totalResourceCost)
 		}
 	}
@@ -342,12 +377,133 @@ func (r *ResourceCostEstimator) EstimateAzureResourcesCost(resourceData map[stri
 		}
 	}
 
+	// As with EBS, attached disks are left out and only the unattached
+	// disks that nothing uses are priced.
+	var orphanedDiskCost float64
+	if disks, ok := resourceData["ManagedDisks"].([]map[string]interface{}); ok {
+		var diskCosts []map[string]interface{}
+		for _, disk := range disks {
+			if unattached, _ := disk["Unattached"].(bool); !unattached {
+				continue
+			}
+			sizeGB, _ := disk["SizeGB"].(float64)
+			monthlyCost := azureDiskMonthlyCost(sizeGB, fmt.Sprint(disk["SKU"]))
+			diskCosts = append(diskCosts, map[string]interface{}{
+				"ID":            disk["ID"],
+				"Name":          disk["Name"],
+				"ResourceGroup": disk["ResourceGroup"],
+				"SizeGB":        sizeGB,
+				"SKU":           disk["SKU"],
+				"Location":      disk["Location"],
+				"MonthlyCost":   monthlyCost,
+			})
+			orphanedDiskCost += monthlyCost
+		}
+		costData["UnattachedDiskCosts"] = diskCosts
+		log.Printf("Found %d unattached Azure managed disks costing $%.2f per month", len(diskCosts), orphanedDiskCost)
+	}
+
 	if summary, ok := costData["Summary"].(map[string]interface{}); ok {
 		if totalCost, ok := summary["TotalMonthlyCost"].(float64); ok {
-			summary["TotalMonthlyCost"] = totalCost + totalResourceCost
+			summary["TotalMonthlyCost"] = totalCost + totalResourceCost + orphanedDiskCost
 			summary["TotalComputeCost"] = totalResourceCost
+			summary["OrphanedVolumeCost"] = orphanedDiskCost
 		}
 	}
 
 	return costData, nil
 }
+
+// ebsVolumePricePerGB is the us-east-1 monthly price per GB of each EBS
+// volume type.
+var ebsVolumePricePerGB = map[string]float64{
+	"gp3":      0.08,
+	"gp2":      0.10,
+	"io1":      0.125,
+	"io2":      0.125,
+	"st1":      0.045,
+	"sc1":      0.015,
+	"standard": 0.05,
+}
+
+// ebsVolumesMonthlyCost estimates the storage cost of an instance's
+// attached volumes.
+func ebsVolumesMonthlyCost(raw interface{}) float64 {
+	volumes, ok := raw.([]map[string]interface{})
+	if !ok {
+		return 0
+	}
+
+	var total float64
+	for _, volume := range volumes {
+		sizeGB, _ := volume["SizeGB"].(float64)
+		total += ebsVolumeMonthlyCost(sizeGB, fmt.Sprint(volume["VolumeType"]))
+	}
+	return total
+}
+
+// ebsVolumeMonthlyCost prices a volume by type. Volumes of unknown type are
+// priced as gp3.
+func ebsVolumeMonthlyCost(sizeGB float64, volumeType string) float64 {
+	price, ok := ebsVolumePricePerGB[volumeType]
+	if !ok {
+		price = ebsVolumePricePerGB["gp3"]
+	}
+	return sizeGB * price
+}
+
+// azureDiskPricePerGB is the East US monthly price per GB of each managed
+// disk SKU, taken from the price of a 128 GB disk.
+var azureDiskPricePerGB = map[string]float64{
+	"Standard_LRS":    0.046,
+	"StandardSSD_LRS": 0.075,
+	"StandardSSD_ZRS": 0.094,
+	"Premium_LRS":     0.154,
+	"Premium_ZRS":     0.231,
+	"PremiumV2_LRS":   0.082,
+	"UltraSSD_LRS":    0.12,
+}
+
+// azureDiskMonthlyCost prices a managed disk by SKU. Disks of unknown SKU
+// are priced as Standard SSD.
+func azureDiskMonthlyCost(sizeGB float64, sku string) float64 {
+	price, ok := azureDiskPricePerGB[sku]
+	if !ok {
+		price = azureDiskPricePerGB["StandardSSD_LRS"]
+	}
+	return sizeGB * price
+}
+
+const bytesPerGB = 1 << 30
+
+// s3PricePerGB returns the us-east-1 monthly price per GB of an S3 storage
+// class, given either as a class name such as "STANDARD_IA" or as a CloudWatch
+// StorageType such as "StandardIAStorage".
+func s3PricePerGB(storageClass string) float64 {
+	switch storageClass {
+	case "STANDARD_IA", "StandardIAStorage", "StandardIASizeOverhead",
+		"IntelligentTieringIAStorage":
+		return 0.0125
+	case "ONEZONE_IA", "OneZoneIAStorage", "OneZoneIASizeOverhead":
+		return 0.01
+	case "GLACIER", "GlacierStorage", "GlacierStagingStorage", "GlacierObjectOverhead", "GlacierS3ObjectOverhead",
+		"GLACIER_IR", "GlacierInstantRetrievalStorage", "GlacierInstantRetrievalSizeOverhead",
+		"IntelligentTieringAIAStorage", "IntelligentTieringAAStorage":
+		return 0.004
+	case "DEEP_ARCHIVE", "DeepArchiveStorage", "DeepArchiveStagingStorage", "DeepArchiveObjectOverhead", "DeepArchiveS3ObjectOverhead",
+		"IntelligentTieringDAAStorage":
+		return 0.00099
+	case "REDUCED_REDUNDANCY", "ReducedRedundancyStorage":
+		return 0.024
+	}
+	return 0.023
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,472 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	k8sdata "github.com/michaelcade/kollect/api/v1"
+	"github.com/michaelcade/kollect/pkg/aws"
+	"github.com/michaelcade/kollect/pkg/azure"
+	"github.com/michaelcade/kollect/pkg/docker"
+	"github.com/michaelcade/kollect/pkg/gcp"
+	"github.com/michaelcade/kollect/pkg/inventory"
+	"github.com/michaelcade/kollect/pkg/terraform"
+	"github.com/michaelcade/kollect/pkg/vault"
+	"github.com/michaelcade/kollect/pkg/veeam"
+)
+
+const (
+	SourceAdded     = "added"
+	SourceRemoved   = "removed"
+	SourceChanged   = "changed"
+	SourceUnchanged = "unchanged"
+	SourceSkipped   = "skipped"
+)
+
+// platformTypes returns the struct each platform's data is decoded into
+// before comparing, so both sides are normalised the same way.
+var platformTypes = map[string]func() interface{}{
+	"kubernetes": func() interface{} { return &k8sdata.K8sData{} },
+	"aws":        func() interface{} { return &aws.AWSData{} },
+	"azure":      func() interface{} { return &azure.AzureData{} },
+	"gcp":        func() interface{} { return &gcp.GCPData{} },
+	"docker":     func() interface{} { return &docker.DockerData{} },
+	"vault":      func() interface{} { return &vault.VaultData{} },
+	"veeam":      func() interface{} { return &veeam.VeeamData{} },
+	"terraform":  func() interface{} { return &terraform.TerraformData{} },
+}
+
+// identityFields lists the fields that identify a resource in a collection,
+// keyed by "platform.Collection". Collections not listed here fall back to
+// namespace/name or the first of defaultIdentityFields that is set.
+var identityFields = map[string][]string{
+	"aws.EC2Instances":                 {"InstanceID"},
+	"aws.S3Buckets":                    {"Name"},
+	"aws.RDSInstances":                 {"AccountID", "Region", "InstanceID"},
+	"aws.DynamoDBTables":               {"AccountID", "Region", "TableName"},
+	"aws.VPCs":                         {"VPCID"},
+	"aws.Subnets":                      {"SubnetID"},
+	"aws.RouteTables":                  {"RouteTableID"},
+	"aws.InternetGateways":             {"GatewayID"},
+	"aws.NATGateways":                  {"GatewayID"},
+	"aws.ElasticIPs":                   {"AccountID", "Region", "PublicIP"},
+	"aws.SecurityGroups":               {"GroupID"},
+	"aws.LoadBalancers":                {"AccountID", "Region", "Name"},
+	"aws.EBSVolumes":                   {"VolumeID"},
+	"aws.OrphanedSnapshots":            {"SnapshotID"},
+	"aws.EKSClusters":                  {"AccountID", "Region", "Name"},
+	"aws.EKSNodeGroups":                {"AccountID", "Region", "Cluster", "Name"},
+	"aws.ECSClusters":                  {"AccountID", "Region", "Name"},
+	"aws.ECSServices":                  {"AccountID", "Region", "Cluster", "Name"},
+	"aws.ECSTasks":                     {"AccountID", "Region", "Cluster", "TaskID"},
+	"aws.LambdaFunctions":              {"AccountID", "Region", "Name"},
+	"aws.Accounts":                     {"AccountID"},
+	"azure.Subscriptions":              {"SubscriptionID"},
+	"gcp.ComputeInstances":             {"Project", "Zone", "Name"},
+	"gcp.GCSBuckets":                   {"Name"},
+	"gcp.CloudSQLInstances":            {"Project", "Name"},
+	"gcp.CloudRunServices":             {"Project", "Region", "Name"},
+	"gcp.CloudFunctions":               {"Project", "Region", "Name"},
+	"terraform.Resources":              {"Module", "Type", "Name"},
+	"terraform.Outputs":                {"Name"},
+	"terraform.Providers":              {"Name"},
+	"docker.volumes":                   {"name"},
+	"vault.policies":                   {"name"},
+	"snapshots.aws.EBSSnapshots":       {"SnapshotId"},
+	"snapshots.aws.RDSSnapshots":       {"SnapshotId"},
+	"snapshots.aws.BackupVaults":       {"AccountId", "Region", "VaultName"},
+	"snapshots.aws.RecoveryPoints":     {"RecoveryPointArn"},
+	"snapshots.aws.BackupPlans":        {"BackupPlanId"},
+	"snapshots.aws.BackupSelections":   {"SelectionId"},
+	"snapshots.aws.ProtectedResources": {"ResourceArn"},
+}
+
+var defaultIdentityFields = []string{"id", "ID", "Id", "InstanceID", "SnapshotId", "path", "Path", "Name", "name"}
+
+// ignoredFields are fields that change on every collection and would
+// otherwise show up as modifications.
+var ignoredFields = map[string]bool{
+	"Age":                              true,
+	"docker.containers.status":         true,
+	"vault.serverInfo.serverTimestamp": true,
+	"vault.serverInfo.lastWALIndex":    true,
+}
+
+// ignoredCollections are left out of the comparison entirely.
+var ignoredCollections = map[string]bool{
+	"aws.Errors":            true,
+	"aws.Regions":           true,
+	"docker.stats":          true,
+	"vault.performanceInfo": true,
+	"vault.secretStats":     true,
+}
+
+type Change struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+type Resource struct {
+	Collection string   `json:"collection"`
+	ID         string   `json:"id"`
+	Changes    []Change `json:"changes,omitempty"`
+}
+
+type SourceDiff struct {
+	Name     string     `json:"name"`
+	Platform string     `json:"platform"`
+	Status   string     `json:"status"`
+	Note     string     `json:"note,omitempty"`
+	Added    []Resource `json:"added,omitempty"`
+	Removed  []Resource `json:"removed,omitempty"`
+	Modified []Resource `json:"modified,omitempty"`
+}
+
+type Side struct {
+	File           string    `json:"file"`
+	KollectVersion string    `json:"kollectVersion,omitempty"`
+	StartedAt      time.Time `json:"startedAt"`
+}
+
+type Report struct {
+	Old     Side         `json:"old"`
+	New     Side         `json:"new"`
+	Sources []SourceDiff `json:"sources"`
+}
+
+// Files loads two exports of any schema version and compares them.
+func Files(oldFile, newFile string) (*Report, error) {
+	oldDoc, err := load(oldFile)
+	if err != nil {
+		return nil, err
+	}
+	newDoc, err := load(newFile)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := Documents(oldDoc, newDoc)
+	if err != nil {
+		return nil, err
+	}
+	report.Old.File = oldFile
+	report.New.File = newFile
+	return report, nil
+}
+
+func load(filename string) (*inventory.Document, error) {
+	raw, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", filename, err)
+	}
+	doc, _, err := inventory.Decode(raw)
+	if err != nil {
+		return nil, fmt.Errorf("error loading %s: %v", filename, err)
+	}
+	return doc, nil
+}
+
+// Documents compares every source present in either document.
+func Documents(oldDoc, newDoc *inventory.Document) (*Report, error) {
+	report := &Report{
+		Old: Side{KollectVersion: oldDoc.KollectVersion, StartedAt: oldDoc.StartedAt},
+		New: Side{KollectVersion: newDoc.KollectVersion, StartedAt: newDoc.StartedAt},
+	}
+
+	names := map[string]bool{}
+	for name := range oldDoc.Sources {
+		names[name] = true
+	}
+	for name := range newDoc.Sources {
+		names[name] = true
+	}
+
+	for _, name := range sortedKeys(names) {
+		sourceDiff, err := compareSource(name, oldDoc.Sources[name], newDoc.Sources[name])
+		if err != nil {
+			return nil, err
+		}
+		report.Sources = append(report.Sources, sourceDiff)
+	}
+	return report, nil
+}
+
+func compareSource(name string, oldSource, newSource *inventory.SourceResult) (SourceDiff, error) {
+	result := SourceDiff{Name: name}
+
+	switch {
+	case oldSource == nil:
+		result.Platform = newSource.Platform
+		result.Status = SourceAdded
+		return result, nil
+	case newSource == nil:
+		result.Platform = oldSource.Platform
+		result.Status = SourceRemoved
+		return result, nil
+	}
+
+	result.Platform = newSource.Platform
+	if oldSource.Platform != newSource.Platform {
+		result.Status = SourceSkipped
+		result.Note = fmt.Sprintf("platform changed from %s to %s", oldSource.Platform, newSource.Platform)
+		return result, nil
+	}
+	if oldSource.Status != inventory.StatusSuccess || newSource.Status != inventory.StatusSuccess {
+		result.Status = SourceSkipped
+		result.Note = fmt.Sprintf("collection status was %s, now %s", oldSource.Status, newSource.Status)
+		return result, nil
+	}
+
+	oldCollections, err := collections(newSource.Platform, oldSource.Data)
+	if err != nil {
+		return result, fmt.Errorf("source %s in old file: %v", name, err)
+	}
+	newCollections, err := collections(newSource.Platform, newSource.Data)
+	if err != nil {
+		return result, fmt.Errorf("source %s in new file: %v", name, err)
+	}
+
+	collectionNames := map[string]bool{}
+	for collection := range oldCollections {
+		collectionNames[collection] = true
+	}
+	for collection := range newCollections {
+		collectionNames[collection] = true
+	}
+	for _, collection := range sortedKeys(collectionNames) {
+		path := newSource.Platform + "." + collection
+		oldItems := oldCollections[collection].index(path)
+		newItems := newCollections[collection].index(path)
+
+		for _, id := range sortedKeys(newItems) {
+			if _, ok := oldItems[id]; !ok {
+				result.Added = append(result.Added, Resource{Collection: collection, ID: id})
+			}
+		}
+		for _, id := range sortedKeys(oldItems) {
+			newItem, ok := newItems[id]
+			if !ok {
+				result.Removed = append(result.Removed, Resource{Collection: collection, ID: id})
+				continue
+			}
+			if changes := compareItems(path, oldItems[id], newItem); len(changes) > 0 {
+				result.Modified = append(result.Modified, Resource{Collection: collection, ID: id, Changes: changes})
+			}
+		}
+	}
+
+	result.Status = SourceUnchanged
+	if len(result.Added) > 0 || len(result.Removed) > 0 || len(result.Modified) > 0 {
+		result.Status = SourceChanged
+	}
+	return result, nil
+}
+
+// collections decodes a source's data through its platform type and splits it
+// into named lists of resources. Nested objects that contain lists (as in
+// Snapshot Hunter output) are flattened into "parent.Child" collections, and
+// any other top level value is treated as a single resource.
+func collections(platform string, data interface{}) (map[string]collection, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if newType, ok := platformTypes[platform]; ok {
+		typed := newType()
+		if err := json.Unmarshal(raw, typed); err != nil {
+			return nil, fmt.Errorf("data is not valid %s inventory: %v", platform, err)
+		}
+		if raw, err = json.Marshal(typed); err != nil {
+			return nil, err
+		}
+	}
+
+	var generic map[string]interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return nil, fmt.Errorf("data is not a JSON object: %v", err)
+	}
+
+	result := map[string]collection{}
+	split(platform, "", generic, result)
+	return result, nil
+}
+
+func split(platform, prefix string, values map[string]interface{}, result map[string]collection) {
+	for key, value := range values {
+		name := prefix + key
+		if ignoredCollections[platform+"."+name] {
+			continue
+		}
+
+		switch v := value.(type) {
+		case []interface{}:
+			result[name] = collection{items: v}
+		case map[string]interface{}:
+			if containsList(v) {
+				split(platform, name+".", v, result)
+			} else {
+				result[name] = collection{items: []interface{}{v}, single: true}
+			}
+		case nil:
+			result[name] = collection{}
+		default:
+			result[name] = collection{items: []interface{}{v}, single: true}
+		}
+	}
+}
+
+func containsList(values map[string]interface{}) bool {
+	for _, value := range values {
+		if _, ok := value.([]interface{}); ok {
+			return true
+		}
+	}
+	return false
+}
+
+type collection struct {
+	items  []interface{}
+	single bool
+}
+
+// index keys each item in a collection by its identity. Items that share an
+// identity are numbered in the order they appear, and a single value is keyed
+// by the collection name itself.
+func (c collection) index(path string) map[string]interface{} {
+	result := make(map[string]interface{}, len(c.items))
+	if c.single {
+		result[path[strings.LastIndex(path, ".")+1:]] = c.items[0]
+		return result
+	}
+
+	for _, item := range c.items {
+		id := identity(path, item)
+		key := id
+		for n := 2; ; n++ {
+			if _, exists := result[key]; !exists {
+				break
+			}
+			key = fmt.Sprintf("%s#%d", id, n)
+		}
+		result[key] = item
+	}
+	return result
+}
+
+func identity(path string, item interface{}) string {
+	fields, ok := item.(map[string]interface{})
+	if !ok {
+		return fmt.Sprintf("%v", item)
+	}
+
+	if keys, ok := identityFields[path]; ok {
+		var parts []string
+		for _, key := range keys {
+			if value := fieldString(fields, key); value != "" {
+				parts = append(parts, value)
+			}
+		}
+		if len(parts) > 0 {
+			return strings.Join(parts, "/")
+		}
+	}
+
+	namespace := fieldString(fields, "Namespace")
+	name := fieldString(fields, "Name")
+	if namespace != "" && name != "" {
+		return namespace + "/" + name
+	}
+
+	for _, key := range defaultIdentityFields {
+		if value := fieldString(fields, key); value != "" {
+			return value
+		}
+	}
+
+	raw, _ := json.Marshal(item)
+	return string(raw)
+}
+
+func fieldString(fields map[string]interface{}, key string) string {
+	value, ok := fields[key]
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+func compareItems(path string, oldItem, newItem interface{}) []Change {
+	oldFields := map[string]interface{}{}
+	newFields := map[string]interface{}{}
+	flatten("", oldItem, oldFields)
+	flatten("", newItem, newFields)
+
+	keys := map[string]bool{}
+	for key := range oldFields {
+		keys[key] = true
+	}
+	for key := range newFields {
+		keys[key] = true
+	}
+
+	var changes []Change
+	for _, key := range sortedKeys(keys) {
+		if isIgnored(path, key) {
+			continue
+		}
+		oldValue, newValue := oldFields[key], newFields[key]
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, Change{Field: key, Old: oldValue, New: newValue})
+		}
+	}
+	return changes
+}
+
+func isIgnored(path, field string) bool {
+	last := field
+	if i := strings.LastIndex(field, "."); i >= 0 {
+		last = field[i+1:]
+	}
+	return ignoredFields[last] || ignoredFields[path+"."+field]
+}
+
+// flatten turns nested objects into dotted field names. Lists are kept whole
+// and compared as a single value.
+func flatten(prefix string, value interface{}, out map[string]interface{}) {
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		if prefix == "" {
+			prefix = "value"
+		}
+		out[prefix] = value
+		return
+	}
+
+	for key, v := range fields {
+		name := key
+		if prefix != "" {
+			name = prefix + "." + key
+		}
+		if nested, ok := v.(map[string]interface{}); ok && len(nested) > 0 {
+			flatten(name, nested, out)
+		} else {
+			out[name] = v
+		}
+	}
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,130 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/michaelcade/kollect/pkg/inventory"
+)
+
+func awsSource(instances ...map[string]interface{}) *inventory.SourceResult {
+	items := make([]interface{}, len(instances))
+	for i, instance := range instances {
+		items[i] = instance
+	}
+	return &inventory.SourceResult{
+		Platform: "aws",
+		Status:   inventory.StatusSuccess,
+		Data:     map[string]interface{}{"EC2Instances": items},
+	}
+}
+
+func instance(id, state string) map[string]interface{} {
+	return map[string]interface{}{"InstanceID": id, "State": state}
+}
+
+func TestDocuments(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      *inventory.SourceResult
+		new      *inventory.SourceResult
+		status   string
+		added    []string
+		removed  []string
+		modified []string
+	}{
+		{
+			name:     "added removed and changed",
+			old:      awsSource(instance("i-1", "running"), instance("i-2", "running")),
+			new:      awsSource(instance("i-2", "stopped"), instance("i-3", "running")),
+			status:   SourceChanged,
+			added:    []string{"EC2Instances/i-3"},
+			removed:  []string{"EC2Instances/i-1"},
+			modified: []string{"EC2Instances/i-2"},
+		},
+		{
+			name:   "unchanged",
+			old:    awsSource(instance("i-1", "running")),
+			new:    awsSource(instance("i-1", "running")),
+			status: SourceUnchanged,
+		},
+		{
+			name:   "source added",
+			new:    awsSource(instance("i-1", "running")),
+			status: SourceAdded,
+		},
+		{
+			name:   "source removed",
+			old:    awsSource(instance("i-1", "running")),
+			status: SourceRemoved,
+		},
+		{
+			name:   "failed collection",
+			old:    awsSource(instance("i-1", "running")),
+			new:    &inventory.SourceResult{Platform: "aws", Status: inventory.StatusFailed},
+			status: SourceSkipped,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldDoc := &inventory.Document{Sources: map[string]*inventory.SourceResult{}}
+			newDoc := &inventory.Document{Sources: map[string]*inventory.SourceResult{}}
+			if tt.old != nil {
+				oldDoc.Sources["prod"] = tt.old
+			}
+			if tt.new != nil {
+				newDoc.Sources["prod"] = tt.new
+			}
+
+			report, err := Documents(oldDoc, newDoc)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(report.Sources) != 1 {
+				t.Fatalf("got %d sources, want 1", len(report.Sources))
+			}
+
+			source := report.Sources[0]
+			if source.Status != tt.status {
+				t.Errorf("status = %q, want %q", source.Status, tt.status)
+			}
+			if got := ids(source.Added); !reflect.DeepEqual(got, tt.added) {
+				t.Errorf("added = %v, want %v", got, tt.added)
+			}
+			if got := ids(source.Removed); !reflect.DeepEqual(got, tt.removed) {
+				t.Errorf("removed = %v, want %v", got, tt.removed)
+			}
+			if got := ids(source.Modified); !reflect.DeepEqual(got, tt.modified) {
+				t.Errorf("modified = %v, want %v", got, tt.modified)
+			}
+		})
+	}
+}
+
+func TestDocumentsChangedFields(t *testing.T) {
+	oldDoc := &inventory.Document{Sources: map[string]*inventory.SourceResult{
+		"prod": awsSource(instance("i-1", "running")),
+	}}
+	newDoc := &inventory.Document{Sources: map[string]*inventory.SourceResult{
+		"prod": awsSource(instance("i-1", "stopped")),
+	}}
+
+	report, err := Documents(oldDoc, newDoc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Change{{Field: "State", Old: "running", New: "stopped"}}
+	if got := report.Sources[0].Modified[0].Changes; !reflect.DeepEqual(got, want) {
+		t.Errorf("changes = %+v, want %+v", got, want)
+	}
+}
+
+func ids(resources []Resource) []string {
+	var result []string
+	for _, resource := range resources {
+		result = append(result, resource.Collection+"/"+resource.ID)
+	}
+	return result
+}
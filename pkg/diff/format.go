@@ -0,0 +1,158 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+var Formats = []string{"text", "json", "markdown"}
+
+// Write renders the report in one of Formats.
+func Write(w io.Writer, report *Report, format string) error {
+	switch format {
+	case "text":
+		return writeText(w, report)
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case "markdown", "md":
+		return writeMarkdown(w, report)
+	default:
+		return fmt.Errorf("unsupported format: %s (available: %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// Summary returns the number of added, removed and modified resources
+// across all sources.
+func (r *Report) Summary() (added, removed, modified int) {
+	for _, source := range r.Sources {
+		added += len(source.Added)
+		removed += len(source.Removed)
+		modified += len(source.Modified)
+	}
+	return added, removed, modified
+}
+
+func writeText(w io.Writer, report *Report) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Comparing %s (%s) with %s (%s)\n", report.Old.File, describeSide(report.Old), report.New.File, describeSide(report.New))
+	added, removed, modified := report.Summary()
+	fmt.Fprintf(&b, "%d added, %d removed, %d modified\n", added, removed, modified)
+
+	for _, source := range report.Sources {
+		fmt.Fprintf(&b, "\n%s (%s): %s\n", source.Name, source.Platform, describeSource(source))
+
+		for _, resource := range source.Added {
+			fmt.Fprintf(&b, "  + %s %s\n", resource.Collection, resource.ID)
+		}
+		for _, resource := range source.Removed {
+			fmt.Fprintf(&b, "  - %s %s\n", resource.Collection, resource.ID)
+		}
+		for _, resource := range source.Modified {
+			fmt.Fprintf(&b, "  ~ %s %s\n", resource.Collection, resource.ID)
+			for _, change := range resource.Changes {
+				fmt.Fprintf(&b, "      %s: %s -> %s\n", change.Field, formatValue(change.Old), formatValue(change.New))
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdown(w io.Writer, report *Report) error {
+	var b strings.Builder
+
+	b.WriteString("# Inventory changes\n\n")
+	fmt.Fprintf(&b, "- **Old:** `%s` (%s)\n", report.Old.File, describeSide(report.Old))
+	fmt.Fprintf(&b, "- **New:** `%s` (%s)\n\n", report.New.File, describeSide(report.New))
+
+	b.WriteString("| Source | Platform | Status | Added | Removed | Modified |\n")
+	b.WriteString("|---|---|---|---|---|---|\n")
+	for _, source := range report.Sources {
+		fmt.Fprintf(&b, "| %s | %s | %s | %d | %d | %d |\n", source.Name, source.Platform, source.Status, len(source.Added), len(source.Removed), len(source.Modified))
+	}
+
+	for _, source := range report.Sources {
+		if source.Status != SourceChanged && source.Note == "" {
+			continue
+		}
+
+		fmt.Fprintf(&b, "\n## %s\n", source.Name)
+		if source.Note != "" {
+			fmt.Fprintf(&b, "\n_%s_\n", source.Note)
+		}
+
+		if len(source.Added) > 0 {
+			b.WriteString("\n### Added\n\n")
+			for _, resource := range source.Added {
+				fmt.Fprintf(&b, "- %s `%s`\n", resource.Collection, resource.ID)
+			}
+		}
+		if len(source.Removed) > 0 {
+			b.WriteString("\n### Removed\n\n")
+			for _, resource := range source.Removed {
+				fmt.Fprintf(&b, "- %s `%s`\n", resource.Collection, resource.ID)
+			}
+		}
+		if len(source.Modified) > 0 {
+			b.WriteString("\n### Modified\n\n")
+			for _, resource := range source.Modified {
+				fmt.Fprintf(&b, "- %s `%s`\n", resource.Collection, resource.ID)
+				for _, change := range resource.Changes {
+					fmt.Fprintf(&b, "  - `%s`: `%s` → `%s`\n", change.Field, formatValue(change.Old), formatValue(change.New))
+				}
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func describeSide(side Side) string {
+	var parts []string
+	if !side.StartedAt.IsZero() {
+		parts = append(parts, side.StartedAt.Format(time.RFC3339))
+	}
+	if side.KollectVersion != "" {
+		parts = append(parts, "kollect "+side.KollectVersion)
+	}
+	if len(parts) == 0 {
+		return "unversioned export"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func describeSource(source SourceDiff) string {
+	switch source.Status {
+	case SourceAdded:
+		return "new source"
+	case SourceRemoved:
+		return "source no longer present"
+	case SourceSkipped:
+		return "not compared, " + source.Note
+	case SourceUnchanged:
+		return "no changes"
+	}
+	return fmt.Sprintf("%d added, %d removed, %d modified", len(source.Added), len(source.Removed), len(source.Modified))
+}
+
+func formatValue(value interface{}) string {
+	if value == nil {
+		return "<none>"
+	}
+	if s, ok := value.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(raw)
+}
@@ -0,0 +1,47 @@
+package docker
+
+import (
+	"context"
+	"os"
+
+	"github.com/michaelcade/kollect/pkg/collector"
+)
+
+type dockerCollector struct{}
+
+func init() {
+	collector.Register(dockerCollector{})
+}
+
+func (dockerCollector) Name() string {
+	return "docker"
+}
+
+func (dockerCollector) CheckCredentials(ctx context.Context, opts collector.Options) (bool, error) {
+	return CheckCredentials(ctx, opts.DockerHost)
+}
+
+func (dockerCollector) Collect(ctx context.Context, opts collector.Options) (interface{}, error) {
+	return CollectDockerData(ctx, opts.DockerHost)
+}
+
+func (dockerCollector) Identity(ctx context.Context, opts collector.Options, data interface{}) (map[string]string, error) {
+	dockerData, ok := data.(DockerData)
+	if !ok {
+		return nil, nil
+	}
+
+	host := opts.DockerHost
+	if host == "" {
+		host = os.Getenv("DOCKER_HOST")
+	}
+
+	identity := map[string]string{
+		"daemonId": dockerData.Info["ID"],
+		"name":     dockerData.Info["Name"],
+	}
+	if host != "" {
+		identity["host"] = host
+	}
+	return identity, nil
+}
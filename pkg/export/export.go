@@ -0,0 +1,199 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/michaelcade/kollect/pkg/inventory"
+	"github.com/xuri/excelize/v2"
+)
+
+// Formats lists the supported --format values.
+var Formats = []string{"json", "csv", "xlsx"}
+
+const maxColumnWidth = 60
+
+var (
+	unsafeFileChars  = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+	unsafeSheetChars = regexp.MustCompile(`[:\\/?*\[\]]`)
+)
+
+// WriteCSV writes summary.csv and one CSV file per table into dir, creating
+// it if needed.
+func WriteCSV(doc *inventory.Document, dir string) error {
+	tables, err := Tables(doc)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("error creating %s: %v", dir, err)
+	}
+
+	if err := writeCSVFile(filepath.Join(dir, "summary.csv"), Summary(doc, tables)); err != nil {
+		return err
+	}
+	for _, table := range tables {
+		filename := unsafeFileChars.ReplaceAllString(table.Source+"_"+table.Collection, "-") + ".csv"
+		if err := writeCSVFile(filepath.Join(dir, filename), table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeCSVFile(path string, table Table) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	if err := w.Write(table.Columns); err != nil {
+		return err
+	}
+	for _, row := range table.Rows {
+		record := make([]string, len(row))
+		for i, value := range row {
+			record[i] = fmt.Sprint(value)
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("error writing %s: %v", path, err)
+	}
+	return nil
+}
+
+// WriteXLSX writes a workbook with a Summary sheet followed by one sheet per
+// table.
+func WriteXLSX(doc *inventory.Document, path string) error {
+	tables, err := Tables(doc)
+	if err != nil {
+		return err
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"#D9E1F2"}},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := f.SetSheetName("Sheet1", "Summary"); err != nil {
+		return err
+	}
+	if err := writeSheet(f, "Summary", Summary(doc, tables), headerStyle); err != nil {
+		return err
+	}
+
+	used := map[string]bool{"summary": true}
+	multipleSources := len(doc.Sources) > 1
+	for _, table := range tables {
+		name := table.Collection
+		if multipleSources {
+			name = table.Source + " " + table.Collection
+		}
+		name = sheetName(name, used)
+
+		if _, err := f.NewSheet(name); err != nil {
+			return fmt.Errorf("error creating sheet %s: %v", name, err)
+		}
+		if err := writeSheet(f, name, table, headerStyle); err != nil {
+			return err
+		}
+	}
+
+	if err := f.SaveAs(path); err != nil {
+		return fmt.Errorf("error saving %s: %v", path, err)
+	}
+	return nil
+}
+
+func writeSheet(f *excelize.File, sheet string, table Table, headerStyle int) error {
+	header := make([]interface{}, len(table.Columns))
+	widths := make([]int, len(table.Columns))
+	for i, column := range table.Columns {
+		header[i] = column
+		widths[i] = len(column)
+	}
+	if err := f.SetSheetRow(sheet, "A1", &header); err != nil {
+		return err
+	}
+
+	for r, row := range table.Rows {
+		cell, err := excelize.CoordinatesToCellName(1, r+2)
+		if err != nil {
+			return err
+		}
+		if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+			return err
+		}
+		for i, value := range row {
+			if n := len(fmt.Sprint(value)); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	if len(table.Columns) == 0 {
+		return nil
+	}
+
+	lastColumn, err := excelize.ColumnNumberToName(len(table.Columns))
+	if err != nil {
+		return err
+	}
+	if err := f.SetCellStyle(sheet, "A1", lastColumn+"1", headerStyle); err != nil {
+		return err
+	}
+	for i, width := range widths {
+		column, _ := excelize.ColumnNumberToName(i + 1)
+		if width > maxColumnWidth {
+			width = maxColumnWidth
+		}
+		if err := f.SetColWidth(sheet, column, column, float64(width+2)); err != nil {
+			return err
+		}
+	}
+	if err := f.SetPanes(sheet, &excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+		return err
+	}
+	return f.AutoFilter(sheet, fmt.Sprintf("A1:%s%d", lastColumn, len(table.Rows)+1), nil)
+}
+
+// sheetName makes name valid as a worksheet name: at most 31 characters,
+// none of : \ / ? * [ ], and unique within the workbook.
+func sheetName(name string, used map[string]bool) string {
+	name = unsafeSheetChars.ReplaceAllString(name, "-")
+	base := truncateRunes(name, 31)
+
+	candidate := base
+	for i := 2; used[strings.ToLower(candidate)]; i++ {
+		suffix := fmt.Sprintf(" (%d)", i)
+		candidate = truncateRunes(base, 31-len(suffix)) + suffix
+	}
+	used[strings.ToLower(candidate)] = true
+	return candidate
+}
+
+// truncateRunes cuts s to at most n characters, never splitting a
+// multi-byte character.
+func truncateRunes(s string, n int) string {
+	if runes := []rune(s); len(runes) > n {
+		return string(runes[:n])
+	}
+	return s
+}
@@ -0,0 +1,292 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/michaelcade/kollect/pkg/inventory"
+)
+
+// Table is one resource type of one source, flattened into rows.
+type Table struct {
+	Source     string
+	Platform   string
+	Collection string
+	Columns    []string
+	Rows       [][]interface{}
+}
+
+// field and object keep JSON object keys in the order they were written, so
+// columns follow the field order of the platform's structs.
+type field struct {
+	key   string
+	value interface{}
+}
+
+type object []field
+
+// Tables flattens every list in every successfully collected source into a
+// table. Lists nested one level down, as in Snapshot Hunter output, become
+// "parent.Child" tables.
+func Tables(doc *inventory.Document) ([]Table, error) {
+	var tables []Table
+	for _, name := range doc.SourceNames() {
+		source := doc.Sources[name]
+		if source.Status != inventory.StatusSuccess || source.Data == nil {
+			continue
+		}
+
+		data, err := ordered(source.Data)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s data: %v", name, err)
+		}
+		fields, ok := data.(object)
+		if !ok {
+			continue
+		}
+
+		for _, f := range fields {
+			switch v := f.value.(type) {
+			case []interface{}:
+				tables = append(tables, newTable(name, source.Platform, f.key, v))
+			case object:
+				for _, child := range v {
+					if list, ok := child.value.([]interface{}); ok {
+						tables = append(tables, newTable(name, source.Platform, f.key+"."+child.key, list))
+					}
+				}
+			}
+		}
+	}
+	return tables, nil
+}
+
+// Summary lists every source with its status, identity and the number of
+// resources in each of its tables.
+func Summary(doc *inventory.Document, tables []Table) Table {
+	summary := Table{
+		Collection: "Summary",
+		Columns:    []string{"Source", "Platform", "Status", "Identity", "Resource Type", "Count", "Collected At", "Error"},
+	}
+
+	counts := map[string][]Table{}
+	for _, table := range tables {
+		counts[table.Source] = append(counts[table.Source], table)
+	}
+
+	for _, name := range doc.SourceNames() {
+		source := doc.Sources[name]
+		identity := formatIdentity(source.Identity)
+		collectedAt := ""
+		if !source.StartedAt.IsZero() {
+			collectedAt = source.StartedAt.Format("2006-01-02 15:04:05 MST")
+		}
+
+		if len(counts[name]) == 0 {
+			summary.Rows = append(summary.Rows, []interface{}{name, source.Platform, source.Status, identity, "", 0, collectedAt, source.Error})
+			continue
+		}
+		for _, table := range counts[name] {
+			summary.Rows = append(summary.Rows, []interface{}{name, source.Platform, source.Status, identity, table.Collection, len(table.Rows), collectedAt, source.Error})
+		}
+	}
+	return summary
+}
+
+func newTable(source, platform, collection string, items []interface{}) Table {
+	table := Table{Source: source, Platform: platform, Collection: collection}
+
+	var rows []map[string]interface{}
+	seen := map[string]bool{}
+	for _, item := range items {
+		row := map[string]interface{}{}
+		var keys []string
+		if obj, ok := item.(object); ok {
+			keys = flatten("", obj, row)
+		} else {
+			keys = []string{"Value"}
+			row["Value"] = cellValue(item)
+		}
+		for _, key := range keys {
+			if !seen[key] {
+				seen[key] = true
+				table.Columns = append(table.Columns, key)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	for _, row := range rows {
+		values := make([]interface{}, len(table.Columns))
+		for i, column := range table.Columns {
+			if value, ok := row[column]; ok {
+				values[i] = value
+			} else {
+				values[i] = ""
+			}
+		}
+		table.Rows = append(table.Rows, values)
+	}
+	return table
+}
+
+// flatten writes the fields of obj into row with nested objects as dotted
+// column names, and returns the column names in order.
+func flatten(prefix string, obj object, row map[string]interface{}) []string {
+	var keys []string
+	for _, f := range obj {
+		key := f.key
+		if prefix != "" {
+			key = prefix + "." + f.key
+		}
+		if nested, ok := f.value.(object); ok && len(nested) > 0 {
+			keys = append(keys, flatten(key, nested, row)...)
+			continue
+		}
+		row[key] = cellValue(f.value)
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// cellValue converts a decoded JSON value into something a single cell can
+// hold. Lists of plain values are joined, anything more complex is kept as
+// JSON.
+func cellValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case string, bool:
+		return v
+	case object:
+		if len(v) == 0 {
+			return ""
+		}
+		return encode(v)
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			switch item.(type) {
+			case object, []interface{}:
+				return encode(v)
+			}
+			parts = append(parts, fmt.Sprint(cellValue(item)))
+		}
+		return strings.Join(parts, ", ")
+	}
+	return fmt.Sprint(value)
+}
+
+func formatIdentity(identity map[string]string) string {
+	keys := make([]string, 0, len(identity))
+	for key := range identity {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, key+"="+identity[key])
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ordered round-trips value through JSON, keeping the order of object keys.
+func ordered(value interface{}) (interface{}, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	return decodeOrdered(decoder)
+}
+
+func decodeOrdered(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+
+	switch delim {
+	case '{':
+		obj := object{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, field{key: key.(string), value: value})
+		}
+		_, err = decoder.Token()
+		return obj, err
+	case '[':
+		list := []interface{}{}
+		for decoder.More() {
+			value, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err = decoder.Token()
+		return list, err
+	}
+	return nil, fmt.Errorf("unexpected %v", delim)
+}
+
+// encode writes a decoded value back out as compact JSON.
+func encode(value interface{}) string {
+	var b strings.Builder
+	writeJSON(&b, value)
+	return b.String()
+}
+
+func writeJSON(b *strings.Builder, value interface{}) {
+	switch v := value.(type) {
+	case object:
+		b.WriteByte('{')
+		for i, f := range v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			key, _ := json.Marshal(f.key)
+			b.Write(key)
+			b.WriteByte(':')
+			writeJSON(b, f.value)
+		}
+		b.WriteByte('}')
+	case []interface{}:
+		b.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeJSON(b, item)
+		}
+		b.WriteByte(']')
+	default:
+		raw, _ := json.Marshal(v)
+		b.Write(raw)
+	}
+}
@@ -0,0 +1,37 @@
+package gcp
+
+import (
+	"context"
+
+	"github.com/michaelcade/kollect/pkg/collector"
+)
+
+type gcpCollector struct{}
+
+func init() {
+	collector.Register(gcpCollector{})
+}
+
+func (gcpCollector) Name() string {
+	return "gcp"
+}
+
+func (gcpCollector) CheckCredentials(ctx context.Context, opts collector.Options) (bool, error) {
+	return CheckCredentials(ctx)
+}
+
+func (gcpCollector) Collect(ctx context.Context, opts collector.Options) (interface{}, error) {
+	return CollectGCPData(ctx)
+}
+
+func (gcpCollector) CollectSnapshots(ctx context.Context, opts collector.Options) (map[string]interface{}, error) {
+	return CollectSnapshotData(ctx)
+}
+
+func (gcpCollector) Identity(ctx context.Context, opts collector.Options, data interface{}) (map[string]string, error) {
+	projectID, err := getCurrentProject()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"projectId": projectID}, nil
+}
@@ -0,0 +1,251 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/michaelcade/kollect/pkg/inventory"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	runsBucket    = []byte("runs")
+	sourcesBucket = []byte("sources")
+)
+
+// idFormat is fixed width so run IDs sort in time order.
+const idFormat = "2006-01-02T15:04:05.000000000Z"
+
+// Run describes one collection stored in the history database.
+type Run struct {
+	ID             string                    `json:"id"`
+	StartedAt      time.Time                 `json:"startedAt"`
+	FinishedAt     time.Time                 `json:"finishedAt"`
+	KollectVersion string                    `json:"kollectVersion"`
+	Sources        []string                  `json:"sources"`
+	Failed         []string                  `json:"failed,omitempty"`
+	Counts         map[string]map[string]int `json:"counts"`
+}
+
+// Point is the resource count of one source at one run.
+type Point struct {
+	RunID     string         `json:"runId"`
+	StartedAt time.Time      `json:"startedAt"`
+	Total     int            `json:"total"`
+	Counts    map[string]int `json:"counts"`
+}
+
+type Store struct {
+	db *bolt.DB
+}
+
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("error opening history database %s: %v", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(runsBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(sourcesBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error initialising history database: %v", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Append stores every source in doc under a new run keyed by the document's
+// start time. Each source is kept in its own bucket so a single source's
+// history can be read without loading the others.
+func (s *Store) Append(doc *inventory.Document) (Run, error) {
+	startedAt := doc.StartedAt
+	if startedAt.IsZero() {
+		startedAt = time.Now().UTC()
+	}
+
+	run := Run{
+		ID:             startedAt.UTC().Format(idFormat),
+		StartedAt:      startedAt,
+		FinishedAt:     doc.FinishedAt,
+		KollectVersion: doc.KollectVersion,
+		Sources:        doc.SourceNames(),
+		Failed:         doc.Failed(),
+		Counts:         doc.Counts(),
+	}
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		runs := tx.Bucket(runsBucket)
+		for runs.Get([]byte(run.ID)) != nil {
+			startedAt = startedAt.Add(time.Nanosecond)
+			run.ID = startedAt.UTC().Format(idFormat)
+		}
+
+		sources := tx.Bucket(sourcesBucket)
+		for name, result := range doc.Sources {
+			bucket, err := sources.CreateBucketIfNotExists([]byte(name))
+			if err != nil {
+				return err
+			}
+			encoded, err := json.Marshal(result)
+			if err != nil {
+				return fmt.Errorf("error encoding %s: %v", name, err)
+			}
+			if err := bucket.Put([]byte(run.ID), encoded); err != nil {
+				return err
+			}
+		}
+
+		encoded, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		return runs.Put([]byte(run.ID), encoded)
+	})
+	if err != nil {
+		return Run{}, fmt.Errorf("error saving run to history: %v", err)
+	}
+	return run, nil
+}
+
+// Runs lists stored runs, oldest first. When source is not empty only runs
+// that include it are returned.
+func (s *Store) Runs(source string) ([]Run, error) {
+	var runs []Run
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(runsBucket).ForEach(func(k, v []byte) error {
+			var run Run
+			if err := json.Unmarshal(v, &run); err != nil {
+				return fmt.Errorf("error decoding run %s: %v", k, err)
+			}
+			if source == "" || containsString(run.Sources, source) {
+				runs = append(runs, run)
+			}
+			return nil
+		})
+	})
+	return runs, err
+}
+
+// Load rebuilds the document stored for a run.
+func (s *Store) Load(id string) (*inventory.Document, error) {
+	var doc *inventory.Document
+	err := s.db.View(func(tx *bolt.Tx) error {
+		encoded := tx.Bucket(runsBucket).Get([]byte(id))
+		if encoded == nil {
+			return fmt.Errorf("run %s not found", id)
+		}
+
+		var run Run
+		if err := json.Unmarshal(encoded, &run); err != nil {
+			return fmt.Errorf("error decoding run %s: %v", id, err)
+		}
+
+		doc = &inventory.Document{
+			SchemaVersion:  inventory.SchemaVersion,
+			KollectVersion: run.KollectVersion,
+			StartedAt:      run.StartedAt,
+			FinishedAt:     run.FinishedAt,
+			Sources:        map[string]*inventory.SourceResult{},
+		}
+
+		sources := tx.Bucket(sourcesBucket)
+		for _, name := range run.Sources {
+			bucket := sources.Bucket([]byte(name))
+			if bucket == nil {
+				continue
+			}
+			value := bucket.Get([]byte(id))
+			if value == nil {
+				continue
+			}
+			var result inventory.SourceResult
+			if err := json.Unmarshal(value, &result); err != nil {
+				return fmt.Errorf("error decoding %s for run %s: %v", name, id, err)
+			}
+			doc.Sources[name] = &result
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// At returns the most recent run started at or before t. When source is
+// not empty only runs that include it are considered.
+func (s *Store) At(t time.Time, source string) (*inventory.Document, error) {
+	runs, err := s.Runs(source)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := sort.Search(len(runs), func(i int) bool {
+		return runs[i].StartedAt.After(t)
+	})
+	if idx == 0 {
+		return nil, fmt.Errorf("no runs found at or before %s", t.Format(time.RFC3339))
+	}
+	return s.Load(runs[idx-1].ID)
+}
+
+// Latest returns the most recent run.
+func (s *Store) Latest() (*inventory.Document, error) {
+	var id string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		k, _ := tx.Bucket(runsBucket).Cursor().Last()
+		id = string(k)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if id == "" {
+		return nil, fmt.Errorf("history is empty")
+	}
+	return s.Load(id)
+}
+
+// Counts returns the resource counts of a source across every run that
+// collected it successfully.
+func (s *Store) Counts(source string) ([]Point, error) {
+	runs, err := s.Runs(source)
+	if err != nil {
+		return nil, err
+	}
+
+	var points []Point
+	for _, run := range runs {
+		counts, ok := run.Counts[source]
+		if !ok {
+			continue
+		}
+		point := Point{RunID: run.ID, StartedAt: run.StartedAt, Counts: counts}
+		for _, count := range counts {
+			point.Total += count
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+func containsString(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package inventory
+
+import (
+	"encoding/json"
+)
+
+// CountResources returns the number of items in each list of a source's
+// data, keyed by field name. Lists nested one level down, as in Snapshot
+// Hunter output, are reported as "parent.Child".
+func CountResources(data interface{}) map[string]int {
+	counts := map[string]int{}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return counts
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return counts
+	}
+
+	for key, value := range fields {
+		switch v := value.(type) {
+		case []interface{}:
+			counts[key] = len(v)
+		case map[string]interface{}:
+			for child, childValue := range v {
+				if list, ok := childValue.([]interface{}); ok {
+					counts[key+"."+child] = len(list)
+				}
+			}
+		}
+	}
+	return counts
+}
+
+// Counts returns CountResources for every successfully collected source.
+func (d *Document) Counts() map[string]map[string]int {
+	counts := map[string]map[string]int{}
+	for name, source := range d.Sources {
+		if source.Status == StatusSuccess {
+			counts[name] = CountResources(source.Data)
+		}
+	}
+	return counts
+}
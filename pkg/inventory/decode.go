@@ -0,0 +1,111 @@
+package inventory
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// platformKeys lists the top level fields that identify each platform's raw
+// output. The first platform with any matching field wins.
+var platformKeys = []struct {
+	platform string
+	keys     []string
+}{
+	{"kubernetes", []string{"Nodes", "Namespaces", "Pods", "PersistentVolumes", "StorageClasses"}},
+	{"aws", []string{"EC2Instances", "S3Buckets", "RDSInstances", "DynamoDBTables", "VPCs"}},
+	{"azure", []string{"VirtualMachines", "ResourceGroups", "AzureVMs", "AzureResourceGroups", "AzureStorageAccounts", "AzureAKSClusters"}},
+	{"gcp", []string{"ComputeInstances", "GCSBuckets", "CloudSQLInstances", "CloudRunServices"}},
+	{"veeam", []string{"ServerInfo", "BackupJobs", "Repositories"}},
+	{"vault", []string{"serverInfo", "secretEngines", "authMethods"}},
+	{"docker", []string{"containers", "images", "info"}},
+	{"terraform", []string{"Resources", "Providers"}},
+	{"snapshots", []string{"kubernetes", "aws", "azure", "gcp"}},
+}
+
+// DetectPlatform guesses which platform produced a bare (schema version 0)
+// export from its top level fields.
+func DetectPlatform(fields map[string]json.RawMessage) string {
+	for _, p := range platformKeys {
+		for _, key := range p.keys {
+			if _, ok := fields[key]; ok {
+				return p.platform
+			}
+		}
+	}
+	return ""
+}
+
+// Decode parses any document written by kollect, validates it and upgrades it
+// to the current schema. It also returns the schema version it was read as.
+func Decode(raw []byte) (*Document, int, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, 0, fmt.Errorf("error parsing inventory: %v", err)
+	}
+
+	version := 0
+	if v, ok := fields["schemaVersion"]; ok {
+		if err := json.Unmarshal(v, &version); err != nil {
+			return nil, 0, fmt.Errorf("invalid schemaVersion: %v", err)
+		}
+		if version < 1 || version > SchemaVersion {
+			return nil, version, fmt.Errorf("unsupported schema version %d (this build supports up to %d)", version, SchemaVersion)
+		}
+	} else if _, ok := fields["sources"]; ok {
+		version = 1
+	}
+
+	var doc *Document
+	switch version {
+	case 0:
+		platform := DetectPlatform(fields)
+		if platform == "" {
+			return nil, 0, fmt.Errorf("unrecognised inventory format")
+		}
+
+		var data interface{}
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return nil, 0, fmt.Errorf("error parsing %s inventory: %v", platform, err)
+		}
+
+		doc = &Document{
+			Sources: map[string]*SourceResult{
+				platform: {
+					Platform: platform,
+					Status:   StatusSuccess,
+					Data:     data,
+				},
+			},
+		}
+	default:
+		doc = &Document{}
+		if err := json.Unmarshal(raw, doc); err != nil {
+			return nil, version, fmt.Errorf("error parsing inventory document: %v", err)
+		}
+	}
+
+	if err := doc.validate(); err != nil {
+		return nil, version, err
+	}
+	doc.SchemaVersion = SchemaVersion
+	return doc, version, nil
+}
+
+func (d *Document) validate() error {
+	if d.Sources == nil {
+		return fmt.Errorf("invalid inventory document: missing sources")
+	}
+
+	for name, source := range d.Sources {
+		if source == nil {
+			return fmt.Errorf("invalid inventory document: source %q is empty", name)
+		}
+		if source.Platform == "" {
+			return fmt.Errorf("invalid inventory document: source %q has no platform", name)
+		}
+		if source.Status != StatusSuccess && source.Status != StatusFailed {
+			return fmt.Errorf("invalid inventory document: source %q has unknown status %q", name, source.Status)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package inventory
+
+import "testing"
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		version  int
+		source   string
+		platform string
+		wantErr  bool
+	}{
+		{
+			name:     "bare v0 aws",
+			raw:      `{"EC2Instances":[{"InstanceID":"i-1"}],"S3Buckets":[]}`,
+			version:  0,
+			source:   "aws",
+			platform: "aws",
+		},
+		{
+			name:     "bare v0 azure",
+			raw:      `{"AzureVMs":[{"Name":"web-01"}],"AzureResourceGroups":[]}`,
+			version:  0,
+			source:   "azure",
+			platform: "azure",
+		},
+		{
+			name:     "v1 sources",
+			raw:      `{"sources":{"prod":{"platform":"aws","status":"success","data":{"EC2Instances":[]}}}}`,
+			version:  1,
+			source:   "prod",
+			platform: "aws",
+		},
+		{
+			name:     "current schema",
+			raw:      `{"schemaVersion":2,"sources":{"cluster":{"platform":"kubernetes","status":"failed","error":"timeout"}}}`,
+			version:  2,
+			source:   "cluster",
+			platform: "kubernetes",
+		},
+		{
+			name:    "unsupported schema version",
+			raw:     `{"schemaVersion":99,"sources":{}}`,
+			version: 99,
+			wantErr: true,
+		},
+		{
+			name:    "schema version zero",
+			raw:     `{"schemaVersion":0,"EC2Instances":[]}`,
+			wantErr: true,
+		},
+		{
+			name:    "unrecognised bare document",
+			raw:     `{"Widgets":[]}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown source status",
+			raw:     `{"sources":{"prod":{"platform":"aws","status":"partial"}}}`,
+			version: 1,
+			wantErr: true,
+		},
+		{
+			name:    "not json",
+			raw:     `EC2Instances`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, version, err := Decode([]byte(tt.raw))
+			if version != tt.version {
+				t.Errorf("version = %d, want %d", version, tt.version)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if doc.SchemaVersion != SchemaVersion {
+				t.Errorf("SchemaVersion = %d, want %d", doc.SchemaVersion, SchemaVersion)
+			}
+			if len(doc.Sources) != 1 {
+				t.Fatalf("got %d sources, want 1", len(doc.Sources))
+			}
+			source, ok := doc.Sources[tt.source]
+			if !ok {
+				t.Fatalf("source %q missing", tt.source)
+			}
+			if source.Platform != tt.platform {
+				t.Errorf("platform = %q, want %q", source.Platform, tt.platform)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package inventory
+
+import (
+	"sort"
+	"time"
+)
+
+// SchemaVersion is the version of the document layout written by this build.
+// Version 1 was the unversioned multi-source envelope and version 0 a bare
+// platform struct; both are upgraded by Decode.
+const SchemaVersion = 2
+
+// Version is the kollect release recorded in every document. It is set at
+// build time with -ldflags "-X github.com/michaelcade/kollect/pkg/inventory.Version=<tag>".
+var Version = "dev"
+
+const (
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+)
+
+// SourceResult is the outcome of collecting a single inventory source.
+type SourceResult struct {
+	Platform        string            `json:"platform"`
+	Status          string            `json:"status"`
+	StartedAt       time.Time         `json:"startedAt"`
+	FinishedAt      time.Time         `json:"finishedAt"`
+	DurationSeconds float64           `json:"durationSeconds"`
+	Identity        map[string]string `json:"identity,omitempty"`
+	Error           string            `json:"error,omitempty"`
+	Data            interface{}       `json:"data,omitempty"`
+}
+
+// Document combines the results of one or more sources, keyed by source name.
+type Document struct {
+	SchemaVersion  int                      `json:"schemaVersion"`
+	KollectVersion string                   `json:"kollectVersion"`
+	StartedAt      time.Time                `json:"startedAt"`
+	FinishedAt     time.Time                `json:"finishedAt"`
+	Sources        map[string]*SourceResult `json:"sources"`
+}
+
+func NewDocument() *Document {
+	return &Document{
+		SchemaVersion:  SchemaVersion,
+		KollectVersion: Version,
+		StartedAt:      time.Now().UTC(),
+		Sources:        map[string]*SourceResult{},
+	}
+}
+
+// Finish stamps the document with the time collection completed.
+func (d *Document) Finish() {
+	d.FinishedAt = time.Now().UTC()
+}
+
+func (d *Document) SourceNames() []string {
+	names := make([]string, 0, len(d.Sources))
+	for name := range d.Sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (d *Document) Failed() []string {
+	var failed []string
+	for _, name := range d.SourceNames() {
+		if d.Sources[name].Status == StatusFailed {
+			failed = append(failed, name)
+		}
+	}
+	return failed
+}
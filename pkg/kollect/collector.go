@@ -0,0 +1,88 @@
+package kollect
+
+import (
+	"context"
+
+	"github.com/michaelcade/kollect/pkg/collector"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+type kubernetesCollector struct{}
+
+func init() {
+	collector.Register(kubernetesCollector{})
+}
+
+func (kubernetesCollector) Name() string {
+	return "kubernetes"
+}
+
+func (kubernetesCollector) CheckCredentials(ctx context.Context, opts collector.Options) (bool, error) {
+	return CheckCredentials(ctx, opts.Kubeconfig, opts.KubeContext)
+}
+
+func (kubernetesCollector) Collect(ctx context.Context, opts collector.Options) (interface{}, error) {
+	if opts.StorageOnly {
+		return CollectStorageDataWithContext(ctx, opts.Kubeconfig, opts.KubeContext)
+	}
+
+	if opts.KubeContext != "" {
+		return CollectDataWithContext(ctx, opts.Kubeconfig, opts.KubeContext)
+	}
+
+	return CollectData(ctx, opts.Kubeconfig)
+}
+
+func (kubernetesCollector) CollectSnapshots(ctx context.Context, opts collector.Options) (map[string]interface{}, error) {
+	return CollectSnapshotDataWithContext(ctx, opts.Kubeconfig, opts.KubeContext)
+}
+
+func (kubernetesCollector) Identity(ctx context.Context, opts collector.Options, data interface{}) (map[string]string, error) {
+	rawConfig, err := clientcmd.LoadFromFile(opts.Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	contextName := opts.KubeContext
+	if contextName == "" {
+		contextName = rawConfig.CurrentContext
+	}
+
+	identity := map[string]string{"context": contextName}
+	if kubeContext, ok := rawConfig.Contexts[contextName]; ok {
+		identity["cluster"] = kubeContext.Cluster
+		if cluster, ok := rawConfig.Clusters[kubeContext.Cluster]; ok {
+			identity["server"] = cluster.Server
+		}
+	}
+	return identity, nil
+}
+
+func CheckCredentials(ctx context.Context, kubeconfig string, contextName string) (bool, error) {
+	config, err := buildConfig(kubeconfig, contextName)
+	if err != nil {
+		return false, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = clientset.CoreV1().Namespaces().List(ctx, v1.ListOptions{Limit: 1})
+	return err == nil, err
+}
+
+func buildConfig(kubeconfig string, contextName string) (*rest.Config, error) {
+	if contextName == "" {
+		return clientcmd.BuildConfigFromFlags("", kubeconfig)
+	}
+
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig},
+		&clientcmd.ConfigOverrides{CurrentContext: contextName},
+	).ClientConfig()
+}
@@ -19,8 +19,14 @@ import (
 )
 
 func CollectStorageData(ctx context.Context, kubeconfig string) (k8sdata.K8sData, error) {
+	return CollectStorageDataWithContext(ctx, kubeconfig, "")
+}
+
+// CollectStorageDataWithContext collects the storage objects of the cluster
+// behind contextName, or of the current context when it is empty.
+func CollectStorageDataWithContext(ctx context.Context, kubeconfig string, contextName string) (k8sdata.K8sData, error) {
 	var data k8sdata.K8sData
-	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	config, err := buildConfig(kubeconfig, contextName)
 	if err != nil {
 		return k8sdata.K8sData{}, err
 	}
@@ -884,9 +890,15 @@ func fetchDataVolumes(ctx context.Context, dynamicClient dynamic.Interface) ([]k
 }
 
 func CollectSnapshotData(ctx context.Context, kubeconfigPath string) (map[string]interface{}, error) {
+	return CollectSnapshotDataWithContext(ctx, kubeconfigPath, "")
+}
+
+// CollectSnapshotDataWithContext collects the volume snapshots of the cluster
+// behind contextName, or of the current context when it is empty.
+func CollectSnapshotDataWithContext(ctx context.Context, kubeconfigPath string, contextName string) (map[string]interface{}, error) {
 	snapshotData := map[string]interface{}{}
 
-	config, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+	config, err := buildConfig(kubeconfigPath, contextName)
 	if err != nil {
 		return nil, fmt.Errorf("error building kubeconfig: %v", err)
 	}
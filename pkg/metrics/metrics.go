@@ -0,0 +1,317 @@
+package metrics
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/michaelcade/kollect/pkg/cost"
+	"github.com/michaelcade/kollect/pkg/export"
+	"github.com/michaelcade/kollect/pkg/inventory"
+	"github.com/michaelcade/kollect/pkg/scheduler"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// regionColumns and stateColumns are the fields, in order of preference,
+// that give a resource's region and state.
+var (
+	regionColumns = []string{"Region", "region", "Location", "location", "Zone", "zone"}
+	stateColumns  = []string{"State", "state", "Status", "status", "Phase", "properties.provisioningState"}
+)
+
+var (
+	resourcesDesc = prometheus.NewDesc("kollect_resources",
+		"Number of resources in the current inventory.",
+		[]string{"source", "platform", "type", "region", "state"}, nil)
+	snapshotsDesc = prometheus.NewDesc("kollect_snapshots",
+		"Number of snapshots found by Snapshot Hunter.",
+		[]string{"platform"}, nil)
+	snapshotSizeDesc = prometheus.NewDesc("kollect_snapshot_size_gib",
+		"Total size of snapshots found by Snapshot Hunter, in GiB.",
+		[]string{"platform"}, nil)
+	snapshotCostDesc = prometheus.NewDesc("kollect_snapshot_monthly_cost_usd",
+		"Estimated monthly cost of snapshots found by Snapshot Hunter, in US dollars.",
+		[]string{"platform"}, nil)
+	sourceUpDesc = prometheus.NewDesc("kollect_source_up",
+		"Whether the most recent collection of a source succeeded.",
+		[]string{"source", "platform"}, nil)
+	durationDesc = prometheus.NewDesc("kollect_source_collection_duration_seconds",
+		"Duration of the collection that produced the current data of a source.",
+		[]string{"source", "platform"}, nil)
+	lastSuccessDesc = prometheus.NewDesc("kollect_source_last_success_timestamp_seconds",
+		"Unix time the current data of a source was collected.",
+		[]string{"source", "platform"}, nil)
+	failuresDesc = prometheus.NewDesc("kollect_source_consecutive_failures",
+		"Number of scheduled collections of a source that failed since its last success.",
+		[]string{"source", "platform"}, nil)
+	runsDesc = prometheus.NewDesc("kollect_source_runs_total",
+		"Number of scheduled collections of a source.",
+		[]string{"source", "platform"}, nil)
+)
+
+// inventoryCollector turns the current inventory into metrics each time it
+// is scraped. Values are recomputed only when the document changes.
+type inventoryCollector struct {
+	current func() *inventory.Document
+	sched   *scheduler.Scheduler
+
+	mu      sync.Mutex
+	doc     *inventory.Document
+	metrics []prometheus.Metric
+}
+
+// Handler serves the metrics for the document returned by current. sched is
+// optional and adds run status metrics in serve mode.
+func Handler(current func() *inventory.Document, sched *scheduler.Scheduler) http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		&inventoryCollector{current: current, sched: sched},
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+func (c *inventoryCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{resourcesDesc, snapshotsDesc, snapshotSizeDesc, snapshotCostDesc, sourceUpDesc, durationDesc, lastSuccessDesc, failuresDesc, runsDesc} {
+		ch <- desc
+	}
+}
+
+func (c *inventoryCollector) Collect(ch chan<- prometheus.Metric) {
+	doc := c.current()
+
+	c.mu.Lock()
+	if doc != c.doc {
+		c.doc = doc
+		c.metrics = inventoryMetrics(doc, c.sched == nil)
+	}
+	cached := c.metrics
+	c.mu.Unlock()
+
+	for _, m := range cached {
+		ch <- m
+	}
+
+	if c.sched == nil {
+		return
+	}
+	for name, status := range c.sched.Status().Sources {
+		platform := ""
+		up := 0.0
+		if status.LastRun != nil {
+			platform = status.LastRun.Platform
+			if status.LastRun.Status == inventory.StatusSuccess {
+				up = 1
+			}
+		}
+		ch <- prometheus.MustNewConstMetric(failuresDesc, prometheus.GaugeValue, float64(status.ConsecutiveFailures), name, platform)
+		ch <- prometheus.MustNewConstMetric(runsDesc, prometheus.CounterValue, float64(status.Runs), name, platform)
+		if status.LastRun != nil {
+			ch <- prometheus.MustNewConstMetric(sourceUpDesc, prometheus.GaugeValue, up, name, platform)
+		}
+	}
+}
+
+func inventoryMetrics(doc *inventory.Document, withUp bool) []prometheus.Metric {
+	if doc == nil {
+		return nil
+	}
+
+	var metrics []prometheus.Metric
+	for _, name := range doc.SourceNames() {
+		source := doc.Sources[name]
+		if source.Status == inventory.StatusSuccess {
+			metrics = append(metrics,
+				prometheus.MustNewConstMetric(durationDesc, prometheus.GaugeValue, source.DurationSeconds, name, source.Platform),
+			)
+			if !source.FinishedAt.IsZero() {
+				metrics = append(metrics,
+					prometheus.MustNewConstMetric(lastSuccessDesc, prometheus.GaugeValue, float64(source.FinishedAt.Unix()), name, source.Platform),
+				)
+			}
+		}
+	}
+
+	// Serve mode reports kollect_source_up from the scheduler, which knows
+	// about failures the kept data hides, so only add it here otherwise.
+	if withUp {
+		for _, name := range doc.SourceNames() {
+			source := doc.Sources[name]
+			up := 0.0
+			if source.Status == inventory.StatusSuccess {
+				up = 1
+			}
+			metrics = append(metrics, prometheus.MustNewConstMetric(sourceUpDesc, prometheus.GaugeValue, up, name, source.Platform))
+		}
+	}
+
+	tables, err := export.Tables(doc)
+	if err != nil {
+		log.Printf("Warning: Unable to count resources for metrics: %v", err)
+	}
+	for _, table := range tables {
+		counts := map[[2]string]int{}
+		region := columnIndex(table.Columns, regionColumns)
+		state := columnIndex(table.Columns, stateColumns)
+		for _, row := range table.Rows {
+			key := [2]string{cell(row, region), cell(row, state)}
+			counts[key]++
+		}
+		for key, count := range counts {
+			metrics = append(metrics, prometheus.MustNewConstMetric(resourcesDesc, prometheus.GaugeValue, float64(count),
+				table.Source, table.Platform, table.Collection, key[0], key[1]))
+		}
+	}
+
+	for _, name := range doc.SourceNames() {
+		source := doc.Sources[name]
+		if source.Platform != "snapshots" || source.Status != inventory.StatusSuccess {
+			continue
+		}
+		platforms, ok := source.Data.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, platform := range sortedKeys(platforms) {
+			snapshots, ok := platforms[platform].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			count, size, monthlyCost := snapshotTotals(platform, snapshots)
+			metrics = append(metrics,
+				prometheus.MustNewConstMetric(snapshotsDesc, prometheus.GaugeValue, float64(count), platform),
+				prometheus.MustNewConstMetric(snapshotSizeDesc, prometheus.GaugeValue, size, platform),
+			)
+			if monthlyCost >= 0 {
+				metrics = append(metrics, prometheus.MustNewConstMetric(snapshotCostDesc, prometheus.GaugeValue, monthlyCost, platform))
+			}
+		}
+	}
+
+	return metrics
+}
+
+// snapshotTotals returns the number of snapshots of a platform, their size
+// and their estimated monthly cost from pkg/cost. The cost is -1 for
+// platforms without pricing.
+func snapshotTotals(platform string, snapshots map[string]interface{}) (int, float64, float64) {
+	count := 0
+	for name, list := range snapshots {
+		if !countedAsSnapshots(platform, name) {
+			continue
+		}
+		switch v := list.(type) {
+		case []interface{}:
+			count += len(v)
+		case []map[string]string:
+			count += len(v)
+		case []map[string]interface{}:
+			count += len(v)
+		}
+	}
+
+	var costs map[string]interface{}
+	var err error
+	switch platform {
+	case "aws":
+		costs, err = cost.EstimateAwsResourceCosts(snapshots)
+	case "azure":
+		costs, err = cost.EstimateAzureResourceCosts(snapshots)
+	case "gcp":
+		costs, err = cost.EstimateGcpResourceCosts(snapshots)
+	case "kubernetes":
+		return count, kubernetesSnapshotSize(snapshots["VolumeSnapshots"]), -1
+	default:
+		return count, 0, -1
+	}
+	if err != nil {
+		log.Printf("Warning: Unable to estimate %s snapshot costs: %v", platform, err)
+		return count, 0, -1
+	}
+
+	summary, _ := costs["Summary"].(map[string]interface{})
+	size, _ := summary["TotalSnapshotStorage"].(float64)
+	monthlyCost, _ := summary["TotalMonthlyCost"].(float64)
+	return count, size, monthlyCost
+}
+
+// countedAsSnapshots reports whether a Snapshot Hunter collection holds
+// snapshots. Backup vaults, plans, selections and protected resources do
+// not. AWS Backup recovery points are mostly EBS and RDS snapshots that are
+// already counted, while Azure recovery points are not disk snapshots and
+// are counted.
+func countedAsSnapshots(platform, name string) bool {
+	switch name {
+	case "BackupVaults", "BackupPlans", "BackupSelections", "ProtectedResources", "ProtectedItems":
+		return false
+	case "RecoveryPoints":
+		return platform == "azure"
+	}
+	return true
+}
+
+// kubernetesSnapshotSize adds up the restore size of volume snapshots, such
+// as "10Gi".
+func kubernetesSnapshotSize(list interface{}) float64 {
+	var sizes []string
+	switch v := list.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if snapshot, ok := item.(map[string]interface{}); ok {
+				sizes = append(sizes, fmt.Sprint(snapshot["RestoreSize"]))
+			}
+		}
+	case []map[string]interface{}:
+		for _, snapshot := range v {
+			sizes = append(sizes, fmt.Sprint(snapshot["RestoreSize"]))
+		}
+	case []map[string]string:
+		for _, snapshot := range v {
+			sizes = append(sizes, snapshot["RestoreSize"])
+		}
+	}
+
+	var total float64
+	for _, size := range sizes {
+		quantity, err := resource.ParseQuantity(strings.TrimSpace(size))
+		if err != nil {
+			continue
+		}
+		total += quantity.AsApproximateFloat64() / (1 << 30)
+	}
+	return total
+}
+
+func columnIndex(columns, candidates []string) int {
+	for _, candidate := range candidates {
+		for i, column := range columns {
+			if column == candidate {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func cell(row []interface{}, index int) string {
+	if index < 0 || index >= len(row) {
+		return ""
+	}
+	return fmt.Sprint(row[index])
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
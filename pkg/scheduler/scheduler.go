@@ -0,0 +1,229 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/michaelcade/kollect/pkg/collector"
+	"github.com/michaelcade/kollect/pkg/inventory"
+	"github.com/robfig/cron/v3"
+)
+
+// Status describes the scheduler and the state of every scheduled source.
+type Status struct {
+	StartedAt time.Time                `json:"startedAt"`
+	Sources   map[string]*SourceStatus `json:"sources"`
+}
+
+// SourceStatus is the state of one scheduled source.
+type SourceStatus struct {
+	Schedule            string                  `json:"schedule"`
+	Running             bool                    `json:"running"`
+	NextRun             time.Time               `json:"nextRun"`
+	Runs                int                     `json:"runs"`
+	ConsecutiveFailures int                     `json:"consecutiveFailures"`
+	LastRun             *inventory.SourceResult `json:"lastRun,omitempty"`
+	LastSuccess         time.Time               `json:"lastSuccess"`
+}
+
+type source struct {
+	collector.Source
+	schedule string
+	entry    cron.EntryID
+	running  bool
+	runs     int
+	failures int
+	lastRun  *inventory.SourceResult
+	lastGood *inventory.SourceResult
+}
+
+// Scheduler re-collects each source on its own cron schedule and keeps the
+// last successful result of every source.
+type Scheduler struct {
+	onRun func(run *inventory.Document)
+
+	cron      *cron.Cron
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	startedAt time.Time
+
+	mu      sync.Mutex
+	sources map[string]*source
+}
+
+// New creates a scheduler. onRun, when not nil, is called with the document
+// of every finished run, whether it succeeded or not.
+func New(onRun func(run *inventory.Document)) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		onRun:   onRun,
+		cron:    cron.New(),
+		ctx:     ctx,
+		cancel:  cancel,
+		sources: map[string]*source{},
+	}
+}
+
+// Add schedules a source. spec is a standard five field cron expression or
+// a descriptor such as "@hourly" or "@every 30m".
+func (s *Scheduler) Add(c collector.Source, spec string) error {
+	if _, ok := collector.Get(c.Platform); !ok {
+		return fmt.Errorf("invalid inventory type: %s", c.Platform)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.sources[c.Name]; exists {
+		return fmt.Errorf("%s is scheduled more than once", c.Name)
+	}
+
+	src := &source{Source: c, schedule: spec}
+	entry, err := s.cron.AddFunc(spec, func() { s.run(src) })
+	if err != nil {
+		return fmt.Errorf("invalid schedule %q for %s: %v", spec, c.Name, err)
+	}
+	src.entry = entry
+	s.sources[c.Name] = src
+	return nil
+}
+
+// Start collects every source once and then follows the schedules.
+func (s *Scheduler) Start() {
+	s.startedAt = time.Now().UTC()
+
+	s.mu.Lock()
+	for _, src := range s.sources {
+		go s.run(src)
+	}
+	s.mu.Unlock()
+
+	s.cron.Start()
+}
+
+// Stop cancels running collections and waits for them to return.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	s.cancel()
+	s.mu.Unlock()
+	<-s.cron.Stop().Done()
+	s.wg.Wait()
+}
+
+func (s *Scheduler) run(src *source) {
+	s.mu.Lock()
+	if src.running || s.ctx.Err() != nil {
+		if src.running {
+			log.Printf("Warning: Skipping scheduled %s collection, previous run is still in progress", src.Name)
+		}
+		s.mu.Unlock()
+		return
+	}
+	src.running = true
+	s.wg.Add(1)
+	s.mu.Unlock()
+	defer s.wg.Done()
+
+	doc := collector.RunSources(s.ctx, []collector.Source{src.Source})
+	result := doc.Sources[src.Name]
+
+	s.mu.Lock()
+	src.running = false
+	src.runs++
+	src.lastRun = result
+	if result.Status == inventory.StatusSuccess {
+		src.failures = 0
+		src.lastGood = result
+	} else {
+		src.failures++
+		if src.lastGood != nil {
+			log.Printf("Warning: Keeping %s data from %s", src.Name, src.lastGood.StartedAt.Format(time.RFC3339))
+		}
+	}
+	s.mu.Unlock()
+
+	if s.onRun != nil {
+		s.onRun(doc)
+	}
+}
+
+// Document returns the latest successful result of every source. Sources
+// that have not succeeded yet are included with their most recent failure.
+func (s *Scheduler) Document() *inventory.Document {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc := inventory.NewDocument()
+	doc.StartedAt = time.Time{}
+	for name, src := range s.sources {
+		result := src.lastGood
+		if result == nil {
+			result = src.lastRun
+		}
+		if result == nil {
+			continue
+		}
+		doc.Sources[name] = result
+		if doc.StartedAt.IsZero() || result.StartedAt.Before(doc.StartedAt) {
+			doc.StartedAt = result.StartedAt
+		}
+		if result.FinishedAt.After(doc.FinishedAt) {
+			doc.FinishedAt = result.FinishedAt
+		}
+	}
+	if doc.StartedAt.IsZero() {
+		doc.StartedAt = s.startedAt
+	}
+	return doc
+}
+
+// Status reports the schedule, next run and last result of every source.
+func (s *Scheduler) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := Status{StartedAt: s.startedAt, Sources: map[string]*SourceStatus{}}
+	for name, src := range s.sources {
+		sourceStatus := &SourceStatus{
+			Schedule:            src.schedule,
+			Running:             src.running,
+			NextRun:             s.cron.Entry(src.entry).Next,
+			Runs:                src.runs,
+			ConsecutiveFailures: src.failures,
+			LastRun:             withoutData(src.lastRun),
+		}
+		if src.lastGood != nil {
+			sourceStatus.LastSuccess = src.lastGood.FinishedAt
+		}
+		status.Sources[name] = sourceStatus
+	}
+	return status
+}
+
+// Names returns the scheduled sources in alphabetical order.
+func (s *Scheduler) Names() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.sources))
+	for name := range s.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// withoutData copies a result without the collected data, which is served
+// separately and can be large.
+func withoutData(result *inventory.SourceResult) *inventory.SourceResult {
+	if result == nil {
+		return nil
+	}
+	copied := *result
+	copied.Data = nil
+	return &copied
+}
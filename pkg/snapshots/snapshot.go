@@ -4,82 +4,96 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
-	"github.com/michaelcade/kollect/pkg/aws"
-	"github.com/michaelcade/kollect/pkg/azure"
-	"github.com/michaelcade/kollect/pkg/gcp"
-	"github.com/michaelcade/kollect/pkg/kollect"
+	"github.com/michaelcade/kollect/pkg/collector"
+	"github.com/michaelcade/kollect/pkg/inventory"
 )
 
-func CollectAllSnapshots(ctx context.Context, kubeconfigPath string) (map[string]interface{}, error) {
+func CollectAllSnapshots(ctx context.Context, opts collector.Options) (map[string]interface{}, error) {
 	results := make(map[string]interface{})
 
-	log.Println("Collecting Kubernetes snapshots...")
-	k8sSnapshots, err := kollect.CollectSnapshotData(ctx, kubeconfigPath)
-	if err != nil {
-		log.Printf("Warning: Error collecting Kubernetes snapshots: %v", err)
-	} else if k8sSnapshots != nil {
-		log.Println("Successfully collected Kubernetes snapshots")
-		results["kubernetes"] = k8sSnapshots
+	for _, c := range collector.All() {
+		snapshotCollector, ok := c.(collector.SnapshotCollector)
+		if !ok {
+			continue
+		}
+
+		log.Printf("Collecting %s snapshots...", c.Name())
+		platformSnapshots, err := snapshotCollector.CollectSnapshots(ctx, opts)
+		if err != nil {
+			log.Printf("Warning: Error collecting %s snapshots: %v", c.Name(), err)
+		} else if platformSnapshots != nil {
+			log.Printf("Successfully collected %s snapshots", c.Name())
+			results[c.Name()] = platformSnapshots
+		}
 	}
 
-	log.Println("Collecting AWS snapshots...")
-	awsSnapshots, err := aws.CollectSnapshotData(ctx)
-	if err != nil {
-		log.Printf("Warning: Error collecting AWS snapshots: %v", err)
-	} else if awsSnapshots != nil {
-		log.Println("Successfully collected AWS snapshots")
-		results["aws"] = awsSnapshots
+	return results, nil
+}
+
+func CollectPlatformSnapshots(ctx context.Context, platform string, opts collector.Options) (map[string]interface{}, error) {
+	c, ok := collector.Get(platform)
+	if !ok {
+		return nil, fmt.Errorf("unsupported platform: %s", platform)
 	}
 
-	log.Println("Collecting Azure snapshots...")
-	azureSnapshots, err := azure.CollectSnapshotData(ctx)
-	if err != nil {
-		log.Printf("Warning: Error collecting Azure snapshots: %v", err)
-	} else if azureSnapshots != nil {
-		log.Println("Successfully collected Azure snapshots")
-		results["azure"] = azureSnapshots
+	snapshotCollector, ok := c.(collector.SnapshotCollector)
+	if !ok {
+		return nil, fmt.Errorf("unsupported platform: %s", platform)
 	}
 
-	log.Println("Collecting GCP snapshots...")
-	gcpSnapshots, err := gcp.CollectSnapshotData(ctx)
+	snapshots, err := snapshotCollector.CollectSnapshots(ctx, opts)
 	if err != nil {
-		log.Printf("Warning: Error collecting GCP snapshots: %v", err)
-	} else if gcpSnapshots != nil {
-		log.Println("Successfully collected GCP snapshots")
-		results["gcp"] = gcpSnapshots
+		return nil, fmt.Errorf("error collecting %s snapshots: %v", platform, err)
 	}
+	return snapshots, nil
+}
 
-	return results, nil
+// Supported reports whether Snapshot Hunter can collect from platform.
+func Supported(platform string) bool {
+	c, ok := collector.Get(platform)
+	if !ok {
+		return false
+	}
+	_, ok = c.(collector.SnapshotCollector)
+	return ok
 }
 
-func CollectPlatformSnapshots(ctx context.Context, platform string, kubeconfigPath string) (map[string]interface{}, error) {
-	switch platform {
-	case "kubernetes":
-		snapshots, err := kollect.CollectSnapshotData(ctx, kubeconfigPath)
-		if err != nil {
-			return nil, fmt.Errorf("error collecting Kubernetes snapshots: %v", err)
-		}
-		return snapshots, nil
-	case "aws":
-		snapshots, err := aws.CollectSnapshotData(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("error collecting AWS snapshots: %v", err)
+// CollectSources collects the snapshots of every source whose platform
+// Snapshot Hunter supports and returns a document with one result per source.
+// Each result holds the source's snapshots under its platform name, in the
+// same shape as CollectAllSnapshots.
+func CollectSources(ctx context.Context, sources []collector.Source) *inventory.Document {
+	doc := inventory.NewDocument()
+
+	for _, src := range sources {
+		if !Supported(src.Platform) {
+			log.Printf("Skipping %s, %s has no snapshots to collect", src.Name, src.Platform)
+			continue
 		}
-		return snapshots, nil
-	case "azure":
-		snapshots, err := azure.CollectSnapshotData(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("error collecting Azure snapshots: %v", err)
+
+		startedAt := time.Now().UTC()
+		opts := src.Options
+		if src.Resolve != nil {
+			if err := src.Resolve(&opts); err != nil {
+				log.Printf("Warning: Error collecting %s snapshots: %v", src.Name, err)
+				doc.Sources[src.Name] = collector.Failed("snapshots", startedAt, err)
+				continue
+			}
 		}
-		return snapshots, nil
-	case "gcp":
-		snapshots, err := gcp.CollectSnapshotData(ctx)
+
+		log.Printf("Collecting %s snapshots...", src.Name)
+		platformSnapshots, err := CollectPlatformSnapshots(ctx, src.Platform, opts)
 		if err != nil {
-			return nil, fmt.Errorf("error collecting GCP snapshots: %v", err)
+			log.Printf("Warning: Error collecting %s snapshots: %v", src.Name, err)
+			doc.Sources[src.Name] = collector.Failed("snapshots", startedAt, err)
+			continue
 		}
-		return snapshots, nil
-	default:
-		return nil, fmt.Errorf("unsupported platform: %s", platform)
+		collected := map[string]interface{}{src.Platform: platformSnapshots}
+		doc.Sources[src.Name] = collector.Succeeded(ctx, "snapshots", opts, collected, startedAt)
 	}
+
+	doc.Finish()
+	return doc
 }
@@ -0,0 +1,74 @@
+package terraform
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/michaelcade/kollect/pkg/collector"
+)
+
+type terraformCollector struct{}
+
+func init() {
+	collector.Register(terraformCollector{})
+}
+
+func (terraformCollector) Name() string {
+	return "terraform"
+}
+
+func (terraformCollector) CheckCredentials(ctx context.Context, opts collector.Options) (bool, error) {
+	_, err := exec.LookPath("terraform")
+	return err == nil, err
+}
+
+func (terraformCollector) Collect(ctx context.Context, opts collector.Options) (interface{}, error) {
+	switch {
+	case opts.TerraformStateFile != "":
+		return CollectTerraformData(ctx, opts.TerraformStateFile)
+	case opts.TerraformS3Bucket != "":
+		parts := strings.SplitN(opts.TerraformS3Bucket, "/", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("terraform S3 source must be in format 'bucket/key'")
+		}
+		region := opts.TerraformS3Region
+		if region == "" {
+			region = os.Getenv("AWS_REGION")
+			if region == "" {
+				region = "us-east-1"
+			}
+		}
+		return CollectTerraformDataFromS3(ctx, parts[0], parts[1], region)
+	case opts.TerraformAzureContainer != "":
+		parts := strings.SplitN(opts.TerraformAzureContainer, "/", 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("terraform Azure source must be in format 'storageaccount/container/blob'")
+		}
+		return CollectTerraformDataFromAzure(ctx, parts[0], parts[1], parts[2])
+	case opts.TerraformGCSBucket != "":
+		parts := strings.SplitN(opts.TerraformGCSBucket, "/", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("terraform GCS source must be in format 'bucket/object'")
+		}
+		return CollectTerraformDataFromGCS(ctx, parts[0], parts[1])
+	default:
+		return nil, fmt.Errorf("no Terraform state source specified (local file, S3, Azure Blob Storage or Google Cloud Storage)")
+	}
+}
+
+func (terraformCollector) Identity(ctx context.Context, opts collector.Options, data interface{}) (map[string]string, error) {
+	switch {
+	case opts.TerraformStateFile != "":
+		return map[string]string{"stateSource": opts.TerraformStateFile}, nil
+	case opts.TerraformS3Bucket != "":
+		return map[string]string{"stateSource": "s3://" + opts.TerraformS3Bucket}, nil
+	case opts.TerraformAzureContainer != "":
+		return map[string]string{"stateSource": "azure://" + opts.TerraformAzureContainer}, nil
+	case opts.TerraformGCSBucket != "":
+		return map[string]string{"stateSource": "gs://" + opts.TerraformGCSBucket}, nil
+	}
+	return nil, nil
+}
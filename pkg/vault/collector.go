@@ -0,0 +1,47 @@
+package vault
+
+import (
+	"context"
+	"os"
+
+	"github.com/michaelcade/kollect/pkg/collector"
+)
+
+type vaultCollector struct{}
+
+func init() {
+	collector.Register(vaultCollector{})
+}
+
+func (vaultCollector) Name() string {
+	return "vault"
+}
+
+func (vaultCollector) CheckCredentials(ctx context.Context, opts collector.Options) (bool, error) {
+	if opts.VaultAddr == "" && os.Getenv("VAULT_ADDR") == "" {
+		return false, nil
+	}
+	return CheckCredentials(ctx, opts.VaultAddr, opts.VaultToken, opts.VaultInsecure)
+}
+
+func (vaultCollector) Collect(ctx context.Context, opts collector.Options) (interface{}, error) {
+	return CollectVaultData(ctx, opts.VaultAddr, opts.VaultToken, opts.VaultInsecure)
+}
+
+func (vaultCollector) Identity(ctx context.Context, opts collector.Options, data interface{}) (map[string]string, error) {
+	vaultData, ok := data.(VaultData)
+	if !ok {
+		return nil, nil
+	}
+
+	address := opts.VaultAddr
+	if address == "" {
+		address = os.Getenv("VAULT_ADDR")
+	}
+
+	return map[string]string{
+		"address":     address,
+		"clusterId":   vaultData.ServerInfo.ClusterID,
+		"clusterName": vaultData.ServerInfo.ClusterName,
+	}, nil
+}
@@ -0,0 +1,42 @@
+package veeam
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/michaelcade/kollect/pkg/collector"
+)
+
+type veeamCollector struct{}
+
+func init() {
+	collector.Register(veeamCollector{})
+}
+
+func (veeamCollector) Name() string {
+	return "veeam"
+}
+
+func (veeamCollector) CheckCredentials(ctx context.Context, opts collector.Options) (bool, error) {
+	return CheckCredentials(ctx)
+}
+
+func (veeamCollector) Collect(ctx context.Context, opts collector.Options) (interface{}, error) {
+	if opts.VeeamURL == "" || opts.VeeamUsername == "" || opts.VeeamPassword == "" {
+		return nil, fmt.Errorf("veeam URL, username, and password must be provided")
+	}
+	return CollectVeeamData(ctx, opts.VeeamURL, opts.VeeamUsername, opts.VeeamPassword, opts.VeeamIgnoreSSL)
+}
+
+func (veeamCollector) Identity(ctx context.Context, opts collector.Options, data interface{}) (map[string]string, error) {
+	identity := map[string]string{"server": opts.VeeamURL}
+	if veeamData, ok := data.(VeeamData); ok {
+		if vbrID, ok := veeamData.ServerInfo["vbrId"].(string); ok {
+			identity["vbrId"] = vbrID
+		}
+		if name, ok := veeamData.ServerInfo["name"].(string); ok {
+			identity["name"] = name
+		}
+	}
+	return identity, nil
+}